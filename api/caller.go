@@ -0,0 +1,81 @@
+package api
+
+import (
+	"analytics-service/service/auth"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Headers are set by the gateway after the user is authenticated.
+const (
+	userIDHeader       = "X-User-ID"
+	userRoleHeader     = "X-User-Role"
+	userTenantsHeader  = "X-User-Tenants"
+	forwardedForHeader = "X-Forwarded-For"
+	realIPHeader       = "X-Real-IP"
+)
+
+func withCaller(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := strconv.Atoi(r.Header.Get(userIDHeader))
+		if err != nil {
+			userID = 0
+		}
+
+		caller := auth.Caller{
+			UserID:   userID,
+			Role:     userRole(r),
+			ClientIP: clientIP(r),
+			Tenants:  tenants(r),
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithCaller(r.Context(), caller)))
+	})
+}
+
+// userRole accepts only the roles of users. The system role is reserved for work started by the service itself,
+// any other value is an unknown role, which is denied everywhere.
+func userRole(r *http.Request) auth.Role {
+	switch role := auth.Role(r.Header.Get(userRoleHeader)); role {
+	case auth.RoleAdmin, auth.RoleAnalyst, auth.RoleContractor:
+		return role
+	default:
+		return auth.RoleUnknown
+	}
+}
+
+// tenants reads a comma separated list of tenants the user may access.
+func tenants(r *http.Request) []string {
+	var result []string
+	for _, tenant := range strings.Split(r.Header.Get(userTenantsHeader), ",") {
+		if tenant = strings.TrimSpace(tenant); len(tenant) > 0 {
+			result = append(result, tenant)
+		}
+	}
+
+	return result
+}
+
+// clientIP takes the last X-Forwarded-For hop, it's the one added by the gateway. The earlier hops come from the client
+// and can be spoofed.
+func clientIP(r *http.Request) string {
+	if forwardedFor := r.Header.Get(forwardedForHeader); len(forwardedFor) > 0 {
+		hops := strings.Split(forwardedFor, ",")
+		if last := strings.TrimSpace(hops[len(hops)-1]); len(last) > 0 {
+			return last
+		}
+	}
+
+	if realIP := r.Header.Get(realIPHeader); len(realIP) > 0 {
+		return realIP
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
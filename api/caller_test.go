@@ -0,0 +1,29 @@
+package api
+
+import (
+	"analytics-service/service/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithCallerRejectsSystemRole(t *testing.T) {
+	for header, want := range map[string]auth.Role{
+		"analyst": auth.RoleAnalyst,
+		"system":  auth.RoleUnknown,
+		"root":    auth.RoleUnknown,
+	} {
+		var got auth.Role
+		handler := withCaller(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = auth.FromContext(r.Context()).Role
+		}))
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(userRoleHeader, header)
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		if got != want {
+			t.Errorf("role header %q: expected %q, got %q", header, want, got)
+		}
+	}
+}
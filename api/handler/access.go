@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"analytics-service/service/auth"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+)
+
+// requireRole writes a 403 response and returns false if the caller has none of the roles.
+func requireRole(c gorouter.Context, roles ...auth.Role) (bool, error) {
+	caller := auth.FromContext(c.Ctx())
+	if slices.Contains(roles, caller.Role) {
+		return true, nil
+	}
+
+	return false, c.WriteJson(http.StatusForbidden, gorouter.ErrorResponse{
+		Error: gorouter.ErrorInfo{
+			Code:    "forbidden",
+			Message: fmt.Sprintf("role %q is not allowed", caller.Role),
+		},
+	})
+}
@@ -1,15 +1,24 @@
 package handler
 
 import (
+	"analytics-service/cluster/file"
 	"analytics-service/service/analytics"
+	"analytics-service/service/audit"
+	"analytics-service/service/auth"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/sunshineOfficial/golib/gohttp/gorouter"
 	"github.com/sunshineOfficial/golib/pagination"
 )
 
+type reportFileVars struct {
+	ReportID int `path:"reportID"`
+	FileID   int `path:"fileID"`
+}
+
 type periodVars struct {
 	PeriodStart string `path:"periodStart"`
 	PeriodEnd   string `path:"periodEnd"`
@@ -17,35 +26,40 @@ type periodVars struct {
 
 // CreateBasicReport godoc
 // @Summary Create basic report
-// @Description Generates a basic analytics report for the inclusive date period.
+// @Description Generates a basic analytics report for the inclusive date period. Subscriber personal data is masked according to the caller role.
+// @Description Only admins, analysts and contractors are allowed.
 // @Tags reports
 // @Produce json
 // @Param periodStart path string true "Period start date in YYYY-MM-DD format"
 // @Param periodEnd path string true "Period end date in YYYY-MM-DD format"
 // @Success 200 {object} analytics.Report
 // @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
 // @Failure 500 {object} gorouter.ErrorResponse
 // @Router /reports/basic/{periodStart}/{periodEnd} [post]
-func CreateBasicReport(s *analytics.Service) gorouter.Handler {
+func CreateBasicReport(s *analytics.Service, auditService *audit.Service) gorouter.Handler {
 	return func(c gorouter.Context) error {
-		var vars periodVars
-		if err := c.Vars(&vars); err != nil {
-			return fmt.Errorf("failed to read period: %w", err)
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst, auth.RoleContractor); !ok {
+			return err
 		}
 
-		periodStart, err := time.Parse(time.DateOnly, vars.PeriodStart)
+		periodStart, periodEnd, err := readPeriod(c)
 		if err != nil {
-			return fmt.Errorf("failed to parse periodStart: %w", err)
+			return err
 		}
 
-		periodEnd, err := time.Parse(time.DateOnly, vars.PeriodEnd)
+		response, err := s.CreateBasicReport(c.Ctx(), c.Log().WithTags("basicReport"), periodStart, periodEnd)
 		if err != nil {
-			return fmt.Errorf("failed to parse periodEnd: %w", err)
+			return fmt.Errorf("failed to create report: %w", err)
 		}
 
-		response, err := s.CreateBasicReport(c.Ctx(), c.Log().WithTags("basicReport"), periodStart, periodEnd)
+		err = auditService.Record(c.Ctx(), audit.ActionReportGenerate, []int{response.ID}, map[string]string{
+			"periodStart":   periodStart.Format(time.DateOnly),
+			"periodEnd":     periodEnd.Format(time.DateOnly),
+			"maskingPolicy": string(response.MaskingPolicy),
+		})
 		if err != nil {
-			return fmt.Errorf("failed to create report: %w", err)
+			return fmt.Errorf("failed to record audit: %w", err)
 		}
 
 		return c.WriteJson(http.StatusOK, response)
@@ -54,17 +68,23 @@ func CreateBasicReport(s *analytics.Service) gorouter.Handler {
 
 // GetAllReports godoc
 // @Summary List reports
-// @Description Returns all generated analytics reports.
+// @Description Returns all generated analytics reports. File URLs are empty, download links are issued by /reports/{reportID}/files/{fileID}.
+// @Description Reports masked less strictly than the caller's own reports of the type are left out. Only admins, analysts and contractors are allowed.
 // @Tags reports
 // @Produce json
 // @Param limit query int false "Maximum number of items to return; 0 means no limit"
 // @Param offset query int false "Number of items to skip"
 // @Success 200 {array} analytics.Report
 // @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
 // @Failure 500 {object} gorouter.ErrorResponse
 // @Router /reports [get]
-func GetAllReports(s *analytics.Service) gorouter.Handler {
+func GetAllReports(s *analytics.Service, auditService *audit.Service) gorouter.Handler {
 	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst, auth.RoleContractor); !ok {
+			return err
+		}
+
 		var vars pagination.Pagination
 		if err := c.Vars(&vars); err != nil {
 			return fmt.Errorf("failed to read pagination: %w", err)
@@ -75,6 +95,81 @@ func GetAllReports(s *analytics.Service) gorouter.Handler {
 			return fmt.Errorf("failed to get reports: %w", err)
 		}
 
+		reportIDs := make([]int, 0, len(response))
+		for _, report := range response {
+			reportIDs = append(reportIDs, report.ID)
+		}
+
+		if len(reportIDs) > 0 {
+			err = auditService.Record(c.Ctx(), audit.ActionReportAccess, reportIDs, map[string]string{
+				"limit":  strconv.Itoa(vars.Limit),
+				"offset": strconv.Itoa(vars.Offset),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record audit: %w", err)
+			}
+		}
+
 		return c.WriteJson(http.StatusOK, response)
 	}
 }
+
+// GetReportFile godoc
+// @Summary Get report file
+// @Description Returns a report file with its download URL, the download is audited.
+// @Description The report is checked like in the report list. Only admins, analysts and contractors are allowed.
+// @Tags reports
+// @Produce json
+// @Param reportID path int true "Report ID"
+// @Param fileID path int true "File ID"
+// @Success 200 {object} file.File
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /reports/{reportID}/files/{fileID} [get]
+func GetReportFile(s *analytics.Service, auditService *audit.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst, auth.RoleContractor); !ok {
+			return err
+		}
+
+		var vars reportFileVars
+		if err := c.Vars(&vars); err != nil {
+			return fmt.Errorf("failed to read vars: %w", err)
+		}
+
+		var response file.File
+		response, err := s.GetReportFile(c.Ctx(), vars.ReportID, vars.FileID)
+		if err != nil {
+			return fmt.Errorf("failed to get report file: %w", err)
+		}
+
+		err = auditService.Record(c.Ctx(), audit.ActionReportDownload, []int{vars.ReportID}, map[string]string{
+			"fileID": strconv.Itoa(vars.FileID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record audit: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+func readPeriod(c gorouter.Context) (time.Time, time.Time, error) {
+	var vars periodVars
+	if err := c.Vars(&vars); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to read period: %w", err)
+	}
+
+	periodStart, err := time.Parse(time.DateOnly, vars.PeriodStart)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse periodStart: %w", err)
+	}
+
+	periodEnd, err := time.Parse(time.DateOnly, vars.PeriodEnd)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse periodEnd: %w", err)
+	}
+
+	return periodStart, periodEnd, nil
+}
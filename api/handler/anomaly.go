@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"analytics-service/service/anomaly"
+	"analytics-service/service/auth"
+	"fmt"
+	"net/http"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+type anomaliesVars struct {
+	Status   string `query:"status"`
+	RuleID   int    `query:"ruleID"`
+	ObjectID int    `query:"objectID"`
+}
+
+type triageVars struct {
+	ID      int    `path:"id"`
+	Status  string `query:"status"`
+	Comment string `query:"comment"`
+}
+
+// GetAnomalies godoc
+// @Summary List consumption anomalies
+// @Description Returns anomalies flagged by the anomaly rules, the latest months and the highest scores first.
+// @Description Account numbers and addresses are masked according to the caller role. Only admins and analysts are allowed.
+// @Tags anomalies
+// @Produce json
+// @Param status query string false "Filter by status" Enums(new, confirmed, dismissed)
+// @Param ruleID query int false "Filter by rule ID"
+// @Param objectID query int false "Filter by object ID"
+// @Param limit query int false "Maximum number of items to return; 0 means no limit"
+// @Param offset query int false "Number of items to skip"
+// @Success 200 {array} anomaly.Anomaly
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /anomalies [get]
+func GetAnomalies(s *anomaly.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		var vars anomaliesVars
+		if err := c.Vars(&vars); err != nil {
+			return fmt.Errorf("failed to read filter: %w", err)
+		}
+
+		var page pagination.Pagination
+		if err := c.Vars(&page); err != nil {
+			return fmt.Errorf("failed to read pagination: %w", err)
+		}
+
+		filter := anomaly.Filter{
+			RuleID:   vars.RuleID,
+			ObjectID: vars.ObjectID,
+		}
+		if vars.Status != "" {
+			status, err := anomaly.ParseStatus(vars.Status)
+			if err != nil {
+				return fmt.Errorf("failed to parse status: %w", err)
+			}
+
+			filter.Status = status
+		}
+
+		response, err := s.GetAnomalies(c.Ctx(), filter, page)
+		if err != nil {
+			return fmt.Errorf("failed to get anomalies: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// GetAnomalyRules godoc
+// @Summary List anomaly rules
+// @Description Returns the anomaly rules configured in Postgres. Only admins and analysts are allowed.
+// @Tags anomalies
+// @Produce json
+// @Success 200 {array} anomaly.Rule
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /anomalies/rules [get]
+func GetAnomalyRules(s *anomaly.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		response, err := s.GetRules(c.Ctx())
+		if err != nil {
+			return fmt.Errorf("failed to get anomaly rules: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// TriageAnomaly godoc
+// @Summary Triage anomaly
+// @Description Sets the status of the anomaly, the caller is saved as the triager. Triaged anomalies are not changed by later evaluations.
+// @Description Only admins and analysts are allowed.
+// @Tags anomalies
+// @Produce json
+// @Param id path int true "Anomaly ID"
+// @Param status query string true "New status" Enums(new, confirmed, dismissed)
+// @Param comment query string false "Analyst comment"
+// @Success 200 {object} anomaly.Anomaly
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /anomalies/{id}/triage [post]
+func TriageAnomaly(s *anomaly.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		var vars triageVars
+		if err := c.Vars(&vars); err != nil {
+			return fmt.Errorf("failed to read vars: %w", err)
+		}
+
+		status, err := anomaly.ParseStatus(vars.Status)
+		if err != nil {
+			return fmt.Errorf("failed to parse status: %w", err)
+		}
+
+		response, err := s.Triage(c.Ctx(), vars.ID, status, vars.Comment)
+		if err != nil {
+			return fmt.Errorf("failed to triage anomaly: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// EvaluateAnomalies godoc
+// @Summary Evaluate anomaly rules
+// @Description Runs the anomaly rules now instead of waiting for the daily job, e.g. after a rule was changed. Only admins are allowed.
+// @Tags anomalies
+// @Produce json
+// @Success 200 {object} anomaly.EvaluationResult
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /anomalies/evaluate [post]
+func EvaluateAnomalies(s *anomaly.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin); !ok {
+			return err
+		}
+
+		response, err := s.Evaluate(c.Ctx())
+		if err != nil {
+			return fmt.Errorf("failed to evaluate anomalies: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
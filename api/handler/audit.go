@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"analytics-service/service/audit"
+	"analytics-service/service/auth"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+type auditVars struct {
+	ActorID  int    `query:"actorID"`
+	Action   string `query:"action"`
+	ReportID int    `query:"reportID"`
+	From     string `query:"from"`
+	To       string `query:"to"`
+}
+
+// GetAuditRecords godoc
+// @Summary List audit records
+// @Description Returns report generation, access and download and subscriber erasure records, newest first. Only admins are allowed.
+// @Tags audit
+// @Produce json
+// @Param actorID query int false "Filter by actor user ID"
+// @Param action query string false "Filter by action" Enums(report_generate, report_access, report_download, watch_list_access, subscriber_erase)
+// @Param reportID query int false "Filter by report ID"
+// @Param from query string false "Inclusive start date in YYYY-MM-DD format"
+// @Param to query string false "Exclusive end date in YYYY-MM-DD format"
+// @Param limit query int false "Maximum number of items to return; 0 means no limit"
+// @Param offset query int false "Number of items to skip"
+// @Success 200 {array} audit.Record
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /audit [get]
+func GetAuditRecords(s *audit.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin); !ok {
+			return err
+		}
+
+		var vars auditVars
+		if err := c.Vars(&vars); err != nil {
+			return fmt.Errorf("failed to read filter: %w", err)
+		}
+
+		var page pagination.Pagination
+		if err := c.Vars(&page); err != nil {
+			return fmt.Errorf("failed to read pagination: %w", err)
+		}
+
+		from, err := parseOptionalDate(vars.From)
+		if err != nil {
+			return fmt.Errorf("failed to parse from: %w", err)
+		}
+
+		to, err := parseOptionalDate(vars.To)
+		if err != nil {
+			return fmt.Errorf("failed to parse to: %w", err)
+		}
+
+		filter := audit.Filter{
+			ActorID:  vars.ActorID,
+			Action:   audit.Action(vars.Action),
+			ReportID: vars.ReportID,
+			From:     from,
+			To:       to,
+		}
+
+		response, err := s.GetRecords(c.Ctx(), filter, page)
+		if err != nil {
+			return fmt.Errorf("failed to get audit records: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+func parseOptionalDate(s string) (*time.Time, error) {
+	if len(s) == 0 {
+		return nil, nil //nolint:nilnil // no date means no filter
+	}
+
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
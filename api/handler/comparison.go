@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"analytics-service/service/analytics"
+	"analytics-service/service/audit"
+	"analytics-service/service/auth"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+)
+
+type comparisonVars struct {
+	Mode         string `query:"mode"`
+	CompareStart string `query:"compareStart"`
+	CompareEnd   string `query:"compareEnd"`
+}
+
+// GetComparison godoc
+// @Summary Compare KPIs of two periods
+// @Description Returns the main KPIs of the period and of the period it is compared with, with absolute and percentage deltas,
+// @Description in total, by inspection type and by brigade. Only admins and analysts are allowed.
+// @Tags comparison
+// @Produce json
+// @Param periodStart path string true "Period start date in YYYY-MM-DD format"
+// @Param periodEnd path string true "Period end date in YYYY-MM-DD format"
+// @Param mode query string false "Period to compare with; previous by default, whole months are shifted by months" Enums(previous, year, custom)
+// @Param compareStart query string false "Start date of the custom period in YYYY-MM-DD format"
+// @Param compareEnd query string false "End date of the custom period in YYYY-MM-DD format"
+// @Success 200 {object} analytics.Comparison
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /comparison/{periodStart}/{periodEnd} [get]
+func GetComparison(s *analytics.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		current, previous, err := readComparisonPeriods(c)
+		if err != nil {
+			return err
+		}
+
+		response, err := s.GetComparison(c.Ctx(), current, previous)
+		if err != nil {
+			return fmt.Errorf("failed to get comparison: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// CreateComparisonReport godoc
+// @Summary Create comparison report
+// @Description Generates an XLSX comparison of the KPIs of two periods with increases and decreases highlighted.
+// @Description Only admins and analysts are allowed.
+// @Tags reports
+// @Produce json
+// @Param periodStart path string true "Period start date in YYYY-MM-DD format"
+// @Param periodEnd path string true "Period end date in YYYY-MM-DD format"
+// @Param mode query string false "Period to compare with; previous by default, whole months are shifted by months" Enums(previous, year, custom)
+// @Param compareStart query string false "Start date of the custom period in YYYY-MM-DD format"
+// @Param compareEnd query string false "End date of the custom period in YYYY-MM-DD format"
+// @Success 200 {object} analytics.Report
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /reports/comparison/{periodStart}/{periodEnd} [post]
+func CreateComparisonReport(s *analytics.Service, auditService *audit.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		current, previous, err := readComparisonPeriods(c)
+		if err != nil {
+			return err
+		}
+
+		response, err := s.CreateComparisonReport(c.Ctx(), c.Log().WithTags("comparisonReport"), current, previous)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+
+		err = auditService.Record(c.Ctx(), audit.ActionReportGenerate, []int{response.ID}, map[string]string{
+			"type":          response.Type.Name(),
+			"periodStart":   current.Start.Format(time.DateOnly),
+			"periodEnd":     current.End.Format(time.DateOnly),
+			"compareStart":  previous.Start.Format(time.DateOnly),
+			"compareEnd":    previous.End.Format(time.DateOnly),
+			"maskingPolicy": string(response.MaskingPolicy),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record audit: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+func readComparisonPeriods(c gorouter.Context) (analytics.Period, analytics.Period, error) {
+	periodStart, periodEnd, err := readPeriod(c)
+	if err != nil {
+		return analytics.Period{}, analytics.Period{}, err
+	}
+
+	current := analytics.Period{Start: periodStart, End: periodEnd}
+
+	var vars comparisonVars
+	if err = c.Vars(&vars); err != nil {
+		return analytics.Period{}, analytics.Period{}, fmt.Errorf("failed to read vars: %w", err)
+	}
+
+	mode := analytics.ComparisonMode(vars.Mode)
+	if mode == "" {
+		mode = analytics.ComparisonModePrevious
+		if vars.CompareStart != "" || vars.CompareEnd != "" {
+			mode = analytics.ComparisonModeCustom
+		}
+	}
+
+	if mode != analytics.ComparisonModeCustom {
+		previous, prevErr := analytics.PreviousPeriod(current, mode)
+		if prevErr != nil {
+			return analytics.Period{}, analytics.Period{}, fmt.Errorf("failed to get previous period: %w", prevErr)
+		}
+
+		return current, previous, nil
+	}
+
+	compareStart, err := time.Parse(time.DateOnly, vars.CompareStart)
+	if err != nil {
+		return analytics.Period{}, analytics.Period{}, fmt.Errorf("failed to parse compareStart: %w", err)
+	}
+
+	compareEnd, err := time.Parse(time.DateOnly, vars.CompareEnd)
+	if err != nil {
+		return analytics.Period{}, analytics.Period{}, fmt.Errorf("failed to parse compareEnd: %w", err)
+	}
+
+	return current, analytics.Period{Start: compareStart, End: compareEnd}, nil
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"analytics-service/service/analytics"
+	"analytics-service/service/audit"
+	"analytics-service/service/auth"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+)
+
+type deviceVars struct {
+	DeviceID int `path:"deviceID"`
+}
+
+// GetDeviceReadings godoc
+// @Summary Get device reading history
+// @Description Returns meter readings of the device in chronological order with deltas between visits, rollbacks and implausible jumps.
+// @Description Addresses and account numbers are masked according to the caller role. Only admins and analysts are allowed.
+// @Tags devices
+// @Produce json
+// @Param deviceID path int true "Device ID"
+// @Success 200 {object} analytics.DeviceHistory
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /devices/{deviceID}/readings [get]
+func GetDeviceReadings(s *analytics.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		var vars deviceVars
+		if err := c.Vars(&vars); err != nil {
+			return fmt.Errorf("failed to read vars: %w", err)
+		}
+
+		response, err := s.GetDeviceHistory(c.Ctx(), vars.DeviceID)
+		if err != nil {
+			return fmt.Errorf("failed to get device history: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// CreateDevicePassportReport godoc
+// @Summary Create device passport report
+// @Description Generates an XLSX passport of the device with its reading timeline, rollbacks and jumps.
+// @Description The report period spans the first and the last readings. Only admins and analysts are allowed.
+// @Tags reports
+// @Produce json
+// @Param deviceID path int true "Device ID"
+// @Success 200 {object} analytics.Report
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /reports/devices/{deviceID} [post]
+func CreateDevicePassportReport(s *analytics.Service, auditService *audit.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		var vars deviceVars
+		if err := c.Vars(&vars); err != nil {
+			return fmt.Errorf("failed to read vars: %w", err)
+		}
+
+		response, err := s.CreateDevicePassportReport(c.Ctx(), c.Log().WithTags("devicePassportReport"), vars.DeviceID)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+
+		err = auditService.Record(c.Ctx(), audit.ActionReportGenerate, []int{response.ID}, map[string]string{
+			"type":          response.Type.Name(),
+			"deviceID":      strconv.Itoa(vars.DeviceID),
+			"periodStart":   response.PeriodStart.Format(time.DateOnly),
+			"periodEnd":     response.PeriodEnd.Format(time.DateOnly),
+			"maskingPolicy": string(response.MaskingPolicy),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record audit: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"analytics-service/service/audit"
+	"analytics-service/service/auth"
+	"analytics-service/service/erasure"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+type erasureVars struct {
+	SubscriberID int    `path:"subscriberID"`
+	Reason       string `query:"reason"`
+}
+
+// EraseSubscriber godoc
+// @Summary Erase subscriber personal data
+// @Description Anonymizes personal data of the subscriber in the finished tasks and anomalies of the caller's tenants and records the request.
+// @Description Rows are kept, so aggregate metrics don't change. Finished tasks and replay tables are rewritten by ClickHouse in the background.
+// @Description The request is recorded as pending first and stays pending if the erasure fails, new tasks of the subscriber are anonymized on ingestion.
+// @Description Only admins are allowed.
+// @Tags erasure
+// @Produce json
+// @Param subscriberID path int true "Subscriber ID"
+// @Param reason query string false "Reason of the request, e.g. the application number"
+// @Success 200 {object} erasure.Request
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /erasure/subscribers/{subscriberID} [post]
+func EraseSubscriber(s *erasure.Service, auditService *audit.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin); !ok {
+			return err
+		}
+
+		var vars erasureVars
+		if err := c.Vars(&vars); err != nil {
+			return fmt.Errorf("failed to read vars: %w", err)
+		}
+
+		response, err := s.Erase(c.Ctx(), vars.SubscriberID, vars.Reason)
+		if err != nil {
+			return fmt.Errorf("failed to erase subscriber: %w", err)
+		}
+
+		err = auditService.Record(c.Ctx(), audit.ActionSubscriberErase, nil, map[string]string{
+			"subscriberID": strconv.Itoa(response.SubscriberID),
+			"requestID":    strconv.Itoa(response.ID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record audit: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// GetErasureRequests godoc
+// @Summary List erasure requests
+// @Description Returns subscriber erasure requests of the caller's tenants, newest first. Only admins are allowed.
+// @Tags erasure
+// @Produce json
+// @Param limit query int false "Maximum number of items to return; 0 means no limit"
+// @Param offset query int false "Number of items to skip"
+// @Success 200 {array} erasure.Request
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /erasure [get]
+func GetErasureRequests(s *erasure.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin); !ok {
+			return err
+		}
+
+		var page pagination.Pagination
+		if err := c.Vars(&page); err != nil {
+			return fmt.Errorf("failed to read pagination: %w", err)
+		}
+
+		response, err := s.GetRequests(c.Ctx(), page)
+		if err != nil {
+			return fmt.Errorf("failed to get erasure requests: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
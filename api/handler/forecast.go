@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"analytics-service/service/auth"
+	"analytics-service/service/forecast"
+	"fmt"
+	"net/http"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+)
+
+const defaultForecastDays = 14
+
+type forecastVars struct {
+	Days int `query:"days"`
+}
+
+// GetTasksForecast godoc
+// @Summary Forecast daily tasks
+// @Description Predicts task counts per inspection type for the next days starting from today with 95% prediction intervals.
+// @Description The forecast uses exponential smoothing with weekday offsets. Only admins and analysts are allowed.
+// @Tags forecast
+// @Produce json
+// @Param days query int false "Number of days to forecast; 0 means 14"
+// @Success 200 {object} forecast.Forecast
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /forecast/tasks [get]
+func GetTasksForecast(s *forecast.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		days, err := readForecastDays(c)
+		if err != nil {
+			return err
+		}
+
+		response, err := s.Forecast(c.Ctx(), days)
+		if err != nil {
+			return fmt.Errorf("failed to forecast tasks: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// GetTasksBacktest godoc
+// @Summary Backtest daily tasks forecast
+// @Description Forecasts the last days before today from the earlier history with every method and compares the predictions
+// @Description with the actual task counts. Only admins and analysts are allowed.
+// @Tags forecast
+// @Produce json
+// @Param days query int false "Number of past days to forecast; 0 means 14"
+// @Success 200 {object} forecast.Backtest
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /forecast/tasks/backtest [get]
+func GetTasksBacktest(s *forecast.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		days, err := readForecastDays(c)
+		if err != nil {
+			return err
+		}
+
+		response, err := s.Backtest(c.Ctx(), days)
+		if err != nil {
+			return fmt.Errorf("failed to backtest tasks forecast: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+func readForecastDays(c gorouter.Context) (int, error) {
+	var vars forecastVars
+	if err := c.Vars(&vars); err != nil {
+		return 0, fmt.Errorf("failed to read vars: %w", err)
+	}
+
+	if vars.Days == 0 {
+		return defaultForecastDays, nil
+	}
+
+	return vars.Days, nil
+}
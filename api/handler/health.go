@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"analytics-service/service/health"
+	"net/http"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+)
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Reports that the service process is running. Dependencies are not checked.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Router /health/live [get]
+func Live(s *health.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		return c.WriteJson(http.StatusOK, s.Live())
+	}
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Checks Postgres, ClickHouse and Kafka consumer lag. Peer services, if enabled, are informational and don't fail readiness.
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /health/ready [get]
+func Ready(s *health.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		report := s.Ready(c.Ctx())
+
+		status := http.StatusOK
+		if report.Status != health.StatusUp {
+			status = http.StatusServiceUnavailable
+		}
+
+		return c.WriteJson(status, report)
+	}
+}
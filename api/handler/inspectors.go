@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"analytics-service/service/analytics"
+	"analytics-service/service/audit"
+	"analytics-service/service/auth"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+)
+
+// GetInspectorPerformance godoc
+// @Summary Get inspector performance
+// @Description Returns tasks, violations found, average duration, daily workload and brigades of every inspector for the period. Only admins and analysts are allowed.
+// @Tags inspectors
+// @Produce json
+// @Param periodStart path string true "Period start date in YYYY-MM-DD format"
+// @Param periodEnd path string true "Period end date in YYYY-MM-DD format"
+// @Success 200 {array} analytics.InspectorPerformance
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /inspectors/{periodStart}/{periodEnd} [get]
+func GetInspectorPerformance(s *analytics.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		periodStart, periodEnd, err := readPeriod(c)
+		if err != nil {
+			return err
+		}
+
+		response, err := s.GetInspectorPerformance(c.Ctx(), periodStart, periodEnd)
+		if err != nil {
+			return fmt.Errorf("failed to get inspector performance: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// CreateInspectorReport godoc
+// @Summary Create inspector report
+// @Description Generates an XLSX report with per-inspector metrics and daily workload for the period. Only admins and analysts are allowed.
+// @Tags reports
+// @Produce json
+// @Param periodStart path string true "Period start date in YYYY-MM-DD format"
+// @Param periodEnd path string true "Period end date in YYYY-MM-DD format"
+// @Success 200 {object} analytics.Report
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /reports/inspectors/{periodStart}/{periodEnd} [post]
+func CreateInspectorReport(s *analytics.Service, auditService *audit.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		periodStart, periodEnd, err := readPeriod(c)
+		if err != nil {
+			return err
+		}
+
+		response, err := s.CreateInspectorReport(c.Ctx(), c.Log().WithTags("inspectorReport"), periodStart, periodEnd)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+
+		err = auditService.Record(c.Ctx(), audit.ActionReportGenerate, []int{response.ID}, map[string]string{
+			"type":          response.Type.Name(),
+			"periodStart":   periodStart.Format(time.DateOnly),
+			"periodEnd":     periodEnd.Format(time.DateOnly),
+			"maskingPolicy": string(response.MaskingPolicy),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record audit: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
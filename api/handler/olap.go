@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"analytics-service/service/auth"
+	"analytics-service/service/olap"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+)
+
+type olapVars struct {
+	From             string `query:"from"`
+	To               string `query:"to"`
+	Dimensions       string `query:"dimensions"`
+	Metrics          string `query:"metrics"`
+	BrigadeID        int    `query:"brigadeID"`
+	InspectorID      int    `query:"inspectorID"`
+	InspectionType   string `query:"inspectionType"`
+	Resolution       string `query:"resolution"`
+	SubscriberStatus string `query:"subscriberStatus"`
+	District         string `query:"district"`
+	HaveAutomaton    string `query:"haveAutomaton"`
+	Limit            int    `query:"limit"`
+}
+
+// QueryMetrics godoc
+// @Summary Query task metrics
+// @Description Aggregates metrics of finished tasks grouped by the chosen dimensions. Dimensions and metrics are comma-separated
+// @Description names from the whitelist. Grouping or filtering by inspector counts a task for each inspector of its brigade.
+// @Description Only admins and analysts are allowed.
+// @Tags olap
+// @Produce json
+// @Param from query string true "Inclusive start date in YYYY-MM-DD format"
+// @Param to query string true "Exclusive end date in YYYY-MM-DD format"
+// @Param dimensions query string false "Comma-separated dimensions: day, week, month, brigade, inspector, inspection_type, resolution, subscriber_status, district, automaton"
+// @Param metrics query string true "Comma-separated metrics: count, violations, avg_duration, median_duration, p90_duration, consumption_sum"
+// @Param brigadeID query int false "Filter by brigade ID"
+// @Param inspectorID query int false "Filter by inspector ID"
+// @Param inspectionType query string false "Filter by inspection type" Enums(unknown, limitation, resumption, verification, unauthorized_connection)
+// @Param resolution query string false "Filter by inspection resolution" Enums(unknown, limited, stopped, resumed)
+// @Param subscriberStatus query string false "Filter by subscriber status" Enums(unknown, active, violator, archived)
+// @Param district query string false "Filter by district, the first part of the object address"
+// @Param haveAutomaton query bool false "Filter by automaton presence"
+// @Param limit query int false "Maximum number of rows to return; 0 means the configured maximum"
+// @Success 200 {object} olap.Result
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /olap/query [get]
+func QueryMetrics(s *olap.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		var vars olapVars
+		if err := c.Vars(&vars); err != nil {
+			return fmt.Errorf("failed to read vars: %w", err)
+		}
+
+		from, err := time.Parse(time.DateOnly, vars.From)
+		if err != nil {
+			return fmt.Errorf("failed to parse from: %w", err)
+		}
+
+		to, err := time.Parse(time.DateOnly, vars.To)
+		if err != nil {
+			return fmt.Errorf("failed to parse to: %w", err)
+		}
+
+		query := olap.Query{
+			From:       from,
+			To:         to,
+			Dimensions: splitList[olap.Dimension](vars.Dimensions),
+			Metrics:    splitList[olap.Metric](vars.Metrics),
+			Filter: olap.Filter{
+				BrigadeID:        vars.BrigadeID,
+				InspectorID:      vars.InspectorID,
+				InspectionType:   vars.InspectionType,
+				Resolution:       vars.Resolution,
+				SubscriberStatus: vars.SubscriberStatus,
+				District:         vars.District,
+			},
+			Limit: vars.Limit,
+		}
+
+		if vars.HaveAutomaton != "" {
+			haveAutomaton, parseErr := strconv.ParseBool(vars.HaveAutomaton)
+			if parseErr != nil {
+				return fmt.Errorf("failed to parse haveAutomaton: %w", parseErr)
+			}
+
+			query.Filter.HaveAutomaton = &haveAutomaton
+		}
+
+		response, err := s.Query(c.Ctx(), query)
+		if err != nil {
+			return fmt.Errorf("failed to query metrics: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+func splitList[T ~string](s string) []T {
+	result := make([]T, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, T(item))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"analytics-service/service/analytics"
+	"analytics-service/service/audit"
+	"analytics-service/service/auth"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+)
+
+// GetPunctuality godoc
+// @Summary Get visit punctuality
+// @Description Compares task start with the planned visit time: on-time share, median and p90 delay, delay distribution in total and per brigade, inspector, district and day.
+// @Description Only admins and analysts are allowed.
+// @Tags punctuality
+// @Produce json
+// @Param periodStart path string true "Period start date in YYYY-MM-DD format"
+// @Param periodEnd path string true "Period end date in YYYY-MM-DD format"
+// @Success 200 {object} analytics.Punctuality
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /punctuality/{periodStart}/{periodEnd} [get]
+func GetPunctuality(s *analytics.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		periodStart, periodEnd, err := readPeriod(c)
+		if err != nil {
+			return err
+		}
+
+		response, err := s.GetPunctuality(c.Ctx(), periodStart, periodEnd)
+		if err != nil {
+			return fmt.Errorf("failed to get punctuality: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// CreatePunctualityReport godoc
+// @Summary Create punctuality report
+// @Description Generates an XLSX report with visit punctuality in total and per brigade, inspector, district and day. Only admins and analysts are allowed.
+// @Tags reports
+// @Produce json
+// @Param periodStart path string true "Period start date in YYYY-MM-DD format"
+// @Param periodEnd path string true "Period end date in YYYY-MM-DD format"
+// @Success 200 {object} analytics.Report
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /reports/punctuality/{periodStart}/{periodEnd} [post]
+func CreatePunctualityReport(s *analytics.Service, auditService *audit.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		periodStart, periodEnd, err := readPeriod(c)
+		if err != nil {
+			return err
+		}
+
+		response, err := s.CreatePunctualityReport(c.Ctx(), c.Log().WithTags("punctualityReport"), periodStart, periodEnd)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+
+		err = auditService.Record(c.Ctx(), audit.ActionReportGenerate, []int{response.ID}, map[string]string{
+			"type":          response.Type.Name(),
+			"periodStart":   periodStart.Format(time.DateOnly),
+			"periodEnd":     periodEnd.Format(time.DateOnly),
+			"maskingPolicy": string(response.MaskingPolicy),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record audit: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"analytics-service/service/analytics"
+	"analytics-service/service/auth"
+	"fmt"
+	"net/http"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+type repairVars struct {
+	ID int `query:"id"`
+}
+
+// GetQuarantinedTasks godoc
+// @Summary List quarantined tasks
+// @Description Returns finished task events that failed validation and are not repaired yet. Only admins are allowed.
+// @Tags quarantine
+// @Produce json
+// @Param limit query int false "Maximum number of items to return; 0 means no limit"
+// @Param offset query int false "Number of items to skip"
+// @Success 200 {array} analytics.QuarantinedTask
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /quarantine [get]
+func GetQuarantinedTasks(s *analytics.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin); !ok {
+			return err
+		}
+
+		var page pagination.Pagination
+		if err := c.Vars(&page); err != nil {
+			return fmt.Errorf("failed to read pagination: %w", err)
+		}
+
+		response, err := s.GetQuarantinedTasks(c.Ctx(), page)
+		if err != nil {
+			return fmt.Errorf("failed to get quarantined tasks: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// RepairQuarantinedTasks godoc
+// @Summary Repair quarantined tasks
+// @Description Re-ingests quarantined tasks with fresh upstream data. Incomplete or failed tasks stay in quarantine, stored tasks aren't inserted again. Only admins are allowed.
+// @Tags quarantine
+// @Produce json
+// @Param id query int false "Quarantined task ID; all pending tasks are repaired if omitted"
+// @Success 200 {object} analytics.RepairResult
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /quarantine/repair [post]
+func RepairQuarantinedTasks(s *analytics.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin); !ok {
+			return err
+		}
+
+		var vars repairVars
+		if err := c.Vars(&vars); err != nil {
+			return fmt.Errorf("failed to read id: %w", err)
+		}
+
+		response, err := s.RepairQuarantinedTasks(c.Ctx(), vars.ID)
+		if err != nil {
+			return fmt.Errorf("failed to repair quarantined tasks: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// GetDeadLetters godoc
+// @Summary List dead letters
+// @Description Returns consumed messages that could not be handled, newest first. Payload is the raw Kafka message. Only admins are allowed.
+// @Tags quarantine
+// @Produce json
+// @Param limit query int false "Maximum number of items to return; 0 means no limit"
+// @Param offset query int false "Number of items to skip"
+// @Success 200 {array} analytics.DeadLetter
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /quarantine/dead-letters [get]
+func GetDeadLetters(s *analytics.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin); !ok {
+			return err
+		}
+
+		var page pagination.Pagination
+		if err := c.Vars(&page); err != nil {
+			return fmt.Errorf("failed to read pagination: %w", err)
+		}
+
+		response, err := s.GetDeadLetters(c.Ctx(), page)
+		if err != nil {
+			return fmt.Errorf("failed to get dead letters: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"analytics-service/service/analytics"
+	"analytics-service/service/auth"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+type replayVars struct {
+	From string `query:"from"`
+	To   string `query:"to"`
+}
+
+type replayJobVars struct {
+	ID int `query:"id"`
+}
+
+// Replay godoc
+// @Summary Replay archived task events
+// @Description Queues a job that re-runs task events received in the period through the ingestion pipeline into a new table. GET /replay shows its status. Only admins are allowed.
+// @Tags replay
+// @Produce json
+// @Param from query string true "Inclusive start date in YYYY-MM-DD format"
+// @Param to query string true "Exclusive end date in YYYY-MM-DD format"
+// @Success 202 {object} analytics.ReplayJob
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /replay [post]
+func Replay(s *analytics.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin); !ok {
+			return err
+		}
+
+		var vars replayVars
+		if err := c.Vars(&vars); err != nil {
+			return fmt.Errorf("failed to read period: %w", err)
+		}
+
+		from, err := time.Parse(time.DateOnly, vars.From)
+		if err != nil {
+			return fmt.Errorf("failed to parse from: %w", err)
+		}
+
+		to, err := time.Parse(time.DateOnly, vars.To)
+		if err != nil {
+			return fmt.Errorf("failed to parse to: %w", err)
+		}
+
+		response, err := s.Replay(c.Ctx(), from, to)
+		if err != nil {
+			return fmt.Errorf("failed to queue replay: %w", err)
+		}
+
+		return c.WriteJson(http.StatusAccepted, response)
+	}
+}
+
+// GetReplayJobs godoc
+// @Summary List replay jobs
+// @Description Returns replay jobs with their status, the latest first. Tables of jobs finished a week ago are dropped and the jobs become expired. Only admins are allowed.
+// @Tags replay
+// @Produce json
+// @Param id query int false "Replay job ID; all jobs are returned if omitted"
+// @Param limit query int false "Maximum number of items to return; 0 means no limit"
+// @Param offset query int false "Number of items to skip"
+// @Success 200 {array} analytics.ReplayJob
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /replay [get]
+func GetReplayJobs(s *analytics.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin); !ok {
+			return err
+		}
+
+		var page pagination.Pagination
+		if err := c.Vars(&page); err != nil {
+			return fmt.Errorf("failed to read pagination: %w", err)
+		}
+
+		var vars replayJobVars
+		if err := c.Vars(&vars); err != nil {
+			return fmt.Errorf("failed to read id: %w", err)
+		}
+
+		response, err := s.GetReplayJobs(c.Ctx(), vars.ID, page)
+		if err != nil {
+			return fmt.Errorf("failed to get replay jobs: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"analytics-service/service/analytics"
+	"analytics-service/service/auth"
+	"fmt"
+	"net/http"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+)
+
+const defaultOpenLimitationDays = 30
+
+type openLimitationsVars struct {
+	OlderThanDays int `query:"olderThanDays"`
+}
+
+// GetObjectTurnaround godoc
+// @Summary Get limitation turnaround per object
+// @Description Pairs each limitation made in the period with the next resumption of the same object and returns per object time disconnected,
+// @Description open limitations and refusals of access. Addresses are masked according to the caller role. Only admins and analysts are allowed.
+// @Tags turnaround
+// @Produce json
+// @Param periodStart path string true "Period start date in YYYY-MM-DD format"
+// @Param periodEnd path string true "Period end date in YYYY-MM-DD format"
+// @Success 200 {array} analytics.ObjectTurnaround
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /turnaround/{periodStart}/{periodEnd} [get]
+func GetObjectTurnaround(s *analytics.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		periodStart, periodEnd, err := readPeriod(c)
+		if err != nil {
+			return err
+		}
+
+		response, err := s.GetObjectTurnaround(c.Ctx(), periodStart, periodEnd)
+		if err != nil {
+			return fmt.Errorf("failed to get object turnaround: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// GetOpenLimitations godoc
+// @Summary List open limitations
+// @Description Returns limitations that were not resumed for more than olderThanDays days, the oldest first.
+// @Description Addresses and account numbers are masked according to the caller role. Only admins and analysts are allowed.
+// @Tags turnaround
+// @Produce json
+// @Param olderThanDays query int false "Minimum age of a limitation in days; 0 means 30"
+// @Success 200 {array} analytics.LimitationTurnaround
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /turnaround/open [get]
+func GetOpenLimitations(s *analytics.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		var vars openLimitationsVars
+		if err := c.Vars(&vars); err != nil {
+			return fmt.Errorf("failed to read vars: %w", err)
+		}
+
+		if vars.OlderThanDays == 0 {
+			vars.OlderThanDays = defaultOpenLimitationDays
+		}
+
+		response, err := s.GetOpenLimitations(c.Ctx(), vars.OlderThanDays)
+		if err != nil {
+			return fmt.Errorf("failed to get open limitations: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
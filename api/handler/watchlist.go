@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"analytics-service/service/analytics"
+	"analytics-service/service/audit"
+	"analytics-service/service/auth"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sunshineOfficial/golib/gohttp/gorouter"
+)
+
+// GetWatchList godoc
+// @Summary Get recidivism watch-list
+// @Description Returns subscribers and objects ranked by the recidivism rules fired in the period with the supporting task IDs.
+// @Description Subscriber personal data is masked according to the caller role. Only admins and analysts are allowed.
+// @Tags watchlist
+// @Produce json
+// @Param periodStart path string true "Period start date in YYYY-MM-DD format"
+// @Param periodEnd path string true "Period end date in YYYY-MM-DD format"
+// @Success 200 {object} analytics.WatchList
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /watchlist/{periodStart}/{periodEnd} [get]
+func GetWatchList(s *analytics.Service, auditService *audit.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		periodStart, periodEnd, err := readPeriod(c)
+		if err != nil {
+			return err
+		}
+
+		response, err := s.GetWatchList(c.Ctx(), periodStart, periodEnd)
+		if err != nil {
+			return fmt.Errorf("failed to get watch-list: %w", err)
+		}
+
+		err = auditService.Record(c.Ctx(), audit.ActionWatchListAccess, nil, map[string]string{
+			"periodStart":   periodStart.Format(time.DateOnly),
+			"periodEnd":     periodEnd.Format(time.DateOnly),
+			"maskingPolicy": string(response.MaskingPolicy),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record audit: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
+
+// CreateWatchListReport godoc
+// @Summary Create watch-list report
+// @Description Generates an XLSX watch-list for the security department. The report is also created weekly by cron. Only admins and analysts are allowed.
+// @Tags reports
+// @Produce json
+// @Param periodStart path string true "Period start date in YYYY-MM-DD format"
+// @Param periodEnd path string true "Period end date in YYYY-MM-DD format"
+// @Success 200 {object} analytics.Report
+// @Failure 400 {object} gorouter.ErrorResponse
+// @Failure 403 {object} gorouter.ErrorResponse
+// @Failure 500 {object} gorouter.ErrorResponse
+// @Router /reports/watchlist/{periodStart}/{periodEnd} [post]
+func CreateWatchListReport(s *analytics.Service, auditService *audit.Service) gorouter.Handler {
+	return func(c gorouter.Context) error {
+		if ok, err := requireRole(c, auth.RoleAdmin, auth.RoleAnalyst); !ok {
+			return err
+		}
+
+		periodStart, periodEnd, err := readPeriod(c)
+		if err != nil {
+			return err
+		}
+
+		response, err := s.CreateWatchListReport(c.Ctx(), c.Log().WithTags("watchListReport"), periodStart, periodEnd)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+
+		err = auditService.Record(c.Ctx(), audit.ActionReportGenerate, []int{response.ID}, map[string]string{
+			"type":          response.Type.Name(),
+			"periodStart":   periodStart.Format(time.DateOnly),
+			"periodEnd":     periodEnd.Format(time.DateOnly),
+			"maskingPolicy": string(response.MaskingPolicy),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record audit: %w", err)
+		}
+
+		return c.WriteJson(http.StatusOK, response)
+	}
+}
@@ -4,6 +4,12 @@ import (
 	"analytics-service/api/handler"
 	"analytics-service/config"
 	"analytics-service/service/analytics"
+	"analytics-service/service/anomaly"
+	"analytics-service/service/audit"
+	"analytics-service/service/erasure"
+	"analytics-service/service/forecast"
+	"analytics-service/service/health"
+	"analytics-service/service/olap"
 	"context"
 	"fmt"
 
@@ -12,6 +18,7 @@ import (
 	"github.com/sunshineOfficial/golib/gohttp/gorouter/plugin"
 	"github.com/sunshineOfficial/golib/gohttp/goserver"
 	"github.com/sunshineOfficial/golib/golog"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type ServerBuilder struct {
@@ -34,14 +41,100 @@ func (s *ServerBuilder) AddDebug() {
 	s.router.Install(plugin.NewPProf(), plugin.NewMetrics(), plugin.NewSwaggo("api/analytics-service"))
 }
 
-func (s *ServerBuilder) AddReports(service *analytics.Service) {
+func (s *ServerBuilder) AddHealth(service *health.Service) {
+	r := s.router.SubRouter("/health")
+	r.HandleGet("/live", handler.Live(service))
+	r.HandleGet("/ready", handler.Ready(service))
+}
+
+func (s *ServerBuilder) AddReports(service *analytics.Service, auditService *audit.Service) {
 	r := s.router.SubRouter("/reports")
-	r.HandlePost("/basic/{periodStart}/{periodEnd}", handler.CreateBasicReport(service))
-	r.HandleGet("", handler.GetAllReports(service))
+	r.HandlePost("/basic/{periodStart}/{periodEnd}", handler.CreateBasicReport(service, auditService))
+	r.HandlePost("/inspectors/{periodStart}/{periodEnd}", handler.CreateInspectorReport(service, auditService))
+	r.HandlePost("/punctuality/{periodStart}/{periodEnd}", handler.CreatePunctualityReport(service, auditService))
+	r.HandlePost("/watchlist/{periodStart}/{periodEnd}", handler.CreateWatchListReport(service, auditService))
+	r.HandlePost("/devices/{deviceID}", handler.CreateDevicePassportReport(service, auditService))
+	r.HandlePost("/comparison/{periodStart}/{periodEnd}", handler.CreateComparisonReport(service, auditService))
+	r.HandleGet("", handler.GetAllReports(service, auditService))
+	r.HandleGet("/{reportID}/files/{fileID}", handler.GetReportFile(service, auditService))
+}
+
+func (s *ServerBuilder) AddInspectors(service *analytics.Service) {
+	r := s.router.SubRouter("/inspectors")
+	r.HandleGet("/{periodStart}/{periodEnd}", handler.GetInspectorPerformance(service))
+}
+
+func (s *ServerBuilder) AddPunctuality(service *analytics.Service) {
+	r := s.router.SubRouter("/punctuality")
+	r.HandleGet("/{periodStart}/{periodEnd}", handler.GetPunctuality(service))
+}
+
+func (s *ServerBuilder) AddTurnaround(service *analytics.Service) {
+	r := s.router.SubRouter("/turnaround")
+	r.HandleGet("/open", handler.GetOpenLimitations(service))
+	r.HandleGet("/{periodStart}/{periodEnd}", handler.GetObjectTurnaround(service))
+}
+
+func (s *ServerBuilder) AddWatchList(service *analytics.Service, auditService *audit.Service) {
+	r := s.router.SubRouter("/watchlist")
+	r.HandleGet("/{periodStart}/{periodEnd}", handler.GetWatchList(service, auditService))
+}
+
+func (s *ServerBuilder) AddComparison(service *analytics.Service) {
+	r := s.router.SubRouter("/comparison")
+	r.HandleGet("/{periodStart}/{periodEnd}", handler.GetComparison(service))
+}
+
+func (s *ServerBuilder) AddDevices(service *analytics.Service) {
+	r := s.router.SubRouter("/devices")
+	r.HandleGet("/{deviceID}/readings", handler.GetDeviceReadings(service))
+}
+
+func (s *ServerBuilder) AddAnomalies(service *anomaly.Service) {
+	r := s.router.SubRouter("/anomalies")
+	r.HandleGet("", handler.GetAnomalies(service))
+	r.HandleGet("/rules", handler.GetAnomalyRules(service))
+	r.HandlePost("/evaluate", handler.EvaluateAnomalies(service))
+	r.HandlePost("/{id}/triage", handler.TriageAnomaly(service))
+}
+
+func (s *ServerBuilder) AddForecast(service *forecast.Service) {
+	r := s.router.SubRouter("/forecast")
+	r.HandleGet("/tasks", handler.GetTasksForecast(service))
+	r.HandleGet("/tasks/backtest", handler.GetTasksBacktest(service))
+}
+
+func (s *ServerBuilder) AddOLAP(service *olap.Service) {
+	r := s.router.SubRouter("/olap")
+	r.HandleGet("/query", handler.QueryMetrics(service))
+}
+
+func (s *ServerBuilder) AddAudit(service *audit.Service) {
+	r := s.router.SubRouter("/audit")
+	r.HandleGet("", handler.GetAuditRecords(service))
+}
+
+func (s *ServerBuilder) AddErasure(service *erasure.Service, auditService *audit.Service) {
+	r := s.router.SubRouter("/erasure")
+	r.HandleGet("", handler.GetErasureRequests(service))
+	r.HandlePost("/subscribers/{subscriberID}", handler.EraseSubscriber(service, auditService))
+}
+
+func (s *ServerBuilder) AddQuarantine(service *analytics.Service) {
+	r := s.router.SubRouter("/quarantine")
+	r.HandleGet("", handler.GetQuarantinedTasks(service))
+	r.HandlePost("/repair", handler.RepairQuarantinedTasks(service))
+	r.HandleGet("/dead-letters", handler.GetDeadLetters(service))
+}
+
+func (s *ServerBuilder) AddReplay(service *analytics.Service) {
+	r := s.router.SubRouter("/replay")
+	r.HandleGet("", handler.GetReplayJobs(service))
+	r.HandlePost("", handler.Replay(service))
 }
 
 func (s *ServerBuilder) Build() goserver.Server {
-	s.server.UseHandler(s.router)
+	s.server.UseHandler(otelhttp.NewHandler(withCaller(s.router), "http.server"))
 
 	return s.server
 }
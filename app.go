@@ -3,13 +3,33 @@ package main
 import (
 	"analytics-service/api"
 	"analytics-service/cluster/brigade"
+	"analytics-service/cluster/cache"
 	"analytics-service/cluster/file"
 	"analytics-service/cluster/inspection"
+	"analytics-service/cluster/resilient"
 	"analytics-service/cluster/subscriber"
 	"analytics-service/config"
 	dbanalytics "analytics-service/database/analytics"
+	dbanomaly "analytics-service/database/anomaly"
+	dbaudit "analytics-service/database/audit"
+	"analytics-service/database/backfill"
+	"analytics-service/database/consumer"
+	dberasure "analytics-service/database/erasure"
+	dbforecast "analytics-service/database/forecast"
+	dbkpi "analytics-service/database/kpi"
+	dbolap "analytics-service/database/olap"
+	"analytics-service/database/retention"
 	"analytics-service/service/analytics"
+	"analytics-service/service/anomaly"
+	"analytics-service/service/audit"
 	"analytics-service/service/cron"
+	"analytics-service/service/erasure"
+	"analytics-service/service/forecast"
+	"analytics-service/service/health"
+	"analytics-service/service/kpi"
+	"analytics-service/service/masking"
+	"analytics-service/service/olap"
+	"analytics-service/tracing"
 	"context"
 	"fmt"
 	"io/fs"
@@ -17,16 +37,18 @@ import (
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
 	"github.com/sunshineOfficial/golib/db"
 	"github.com/sunshineOfficial/golib/gohttp"
 	"github.com/sunshineOfficial/golib/gohttp/goserver"
-	"github.com/sunshineOfficial/golib/gokafka"
 	"github.com/sunshineOfficial/golib/golog"
 )
 
 const (
 	serviceName = "analytics-service"
 	dbTimeout   = 15 * time.Second
+	// Backfills scan the whole finished_tasks table, the mutations themselves run in the background.
+	backfillTimeout = 2 * time.Minute
 )
 
 type App struct {
@@ -42,12 +64,21 @@ type App struct {
 	postgres         *sqlx.DB
 	clickhouse       *sqlx.DB
 	clickhouseNative driver.Conn
-	kafka            gokafka.Kafka
-	taskConsumer     gokafka.Consumer
+	taskConsumer     *consumer.Consumer
+	redis            *redis.Client
+
+	/* tracing */
+	shutdownTracing func(ctx context.Context) error
 
 	/* services */
 	analyticsService *analytics.Service
+	anomalyService   *anomaly.Service
+	auditService     *audit.Service
 	cronService      *cron.Service
+	erasureService   *erasure.Service
+	forecastService  *forecast.Service
+	healthService    *health.Service
+	olapService      *olap.Service
 }
 
 func NewApp(mainCtx context.Context, log golog.Logger, settings config.Settings) *App {
@@ -58,6 +89,15 @@ func NewApp(mainCtx context.Context, log golog.Logger, settings config.Settings)
 	}
 }
 
+func (a *App) InitTracing() (err error) {
+	a.shutdownTracing, err = tracing.Init(a.mainCtx, a.settings.Tracing, serviceName)
+	if err != nil {
+		return fmt.Errorf("init tracing: %w", err)
+	}
+
+	return nil
+}
+
 func (a *App) InitDatabases(fs fs.FS, path string) (err error) {
 	postgresCtx, cancelPostgresCtx := context.WithTimeout(a.mainCtx, dbTimeout)
 	defer cancelPostgresCtx()
@@ -72,6 +112,11 @@ func (a *App) InitDatabases(fs fs.FS, path string) (err error) {
 		return fmt.Errorf("migrate postgres: %w", err)
 	}
 
+	err = backfill.ReportTenants(postgresCtx, a.postgres, a.settings.Tenancy)
+	if err != nil {
+		return fmt.Errorf("backfill postgres report tenants: %w", err)
+	}
+
 	clickCtx, cancelClickCtx := context.WithTimeout(a.mainCtx, dbTimeout)
 	defer cancelClickCtx()
 
@@ -85,6 +130,11 @@ func (a *App) InitDatabases(fs fs.FS, path string) (err error) {
 		return fmt.Errorf("migrate clickhouse: %w", err)
 	}
 
+	err = retention.Migrate(clickCtx, a.log, a.clickhouse, a.settings.Retention)
+	if err != nil {
+		return fmt.Errorf("migrate clickhouse retention: %w", err)
+	}
+
 	nativeClickCtx, cancelNativeClickCtx := context.WithTimeout(a.mainCtx, dbTimeout)
 	defer cancelNativeClickCtx()
 
@@ -99,53 +149,161 @@ func (a *App) InitDatabases(fs fs.FS, path string) (err error) {
 		return fmt.Errorf("init native clickhouse: %w", err)
 	}
 
-	a.kafka = gokafka.NewKafka(a.settings.Databases.Kafka.Brokers)
+	backfillCtx, cancelBackfillCtx := context.WithTimeout(a.mainCtx, backfillTimeout)
+	defer cancelBackfillCtx()
 
-	a.taskConsumer, err = a.kafka.Consumer(a.log.WithTags("taskConsumer"), func() (context.Context, context.CancelFunc) {
-		return context.WithCancel(a.mainCtx)
-	}, gokafka.WithTopic(a.settings.Databases.Kafka.Topics.Tasks), gokafka.WithConsumerGroup(serviceName))
+	err = backfill.Addresses(backfillCtx, a.log, a.clickhouseNative, a.settings.Address)
 	if err != nil {
-		return fmt.Errorf("init task consumer: %w", err)
+		return fmt.Errorf("backfill clickhouse address parts: %w", err)
 	}
 
+	err = backfill.Tenants(backfillCtx, a.log, a.clickhouseNative, a.settings.Tenancy)
+	if err != nil {
+		return fmt.Errorf("backfill clickhouse tenants: %w", err)
+	}
+
+	if len(a.settings.Databases.Redis.Address) > 0 {
+		a.redis = redis.NewClient(&redis.Options{
+			Addr:     a.settings.Databases.Redis.Address,
+			Password: a.settings.Databases.Redis.Password,
+			DB:       a.settings.Databases.Redis.DB,
+		})
+
+		redisCtx, cancelRedisCtx := context.WithTimeout(a.mainCtx, dbTimeout)
+		defer cancelRedisCtx()
+
+		// Redis is shared by the enrichment caches only, they fall back to process memory while it's down.
+		if err = a.redis.Ping(redisCtx).Err(); err != nil {
+			a.log.Errorf("redis is unavailable, enrichment caches are local until it's back: %v", err)
+		}
+	}
+
+	a.taskConsumer = consumer.NewConsumer(a.settings.Databases.Kafka, a.settings.Databases.Kafka.Topics.Tasks, serviceName,
+		a.log.WithTags("taskConsumer"))
+
 	return nil
 }
 
 func (a *App) InitServices() error {
 	analyticsRepository := dbanalytics.NewRepository(a.postgres, a.clickhouseNative)
+	auditRepository := dbaudit.NewRepository(a.postgres)
 
 	httpClient := gohttp.NewClient(gohttp.WithTimeout(1 * time.Minute))
+	clusterClient := resilient.NewClient(httpClient, a.settings.Cluster.Resilience)
+
+	brigadeClient := brigade.NewClient(clusterClient, a.settings.Cluster.BrigadeService)
+	fileClient := file.NewClient(clusterClient, a.settings.Cluster.FileService)
+	inspectionClient := inspection.NewClient(clusterClient, a.settings.Cluster.InspectionService)
+	subscriberClient := subscriber.NewClient(clusterClient, a.settings.Cluster.SubscriberService)
 
-	brigadeClient := brigade.NewClient(httpClient, a.settings.Cluster.BrigadeService)
-	fileClient := file.NewClient(httpClient, a.settings.Cluster.FileService)
-	inspectionClient := inspection.NewClient(httpClient, a.settings.Cluster.InspectionService)
-	subscriberClient := subscriber.NewClient(httpClient, a.settings.Cluster.SubscriberService)
+	var (
+		brigadeService    analytics.BrigadeService    = brigadeClient
+		subscriberService analytics.SubscriberService = subscriberClient
+	)
+	if a.settings.Cluster.Cache.Size > 0 {
+		brigadeService = brigade.NewCachedClient(brigadeClient, newEnrichmentCache[brigade.Brigade](a, "brigades"))
+		subscriberService = subscriber.NewCachedClient(subscriberClient, newEnrichmentCache[subscriber.Contract](a, "contracts"))
+	}
+
+	masker, err := masking.NewMasker(a.settings.Masking)
+	if err != nil {
+		return fmt.Errorf("init masker: %w", err)
+	}
 
 	a.analyticsService = analytics.NewService(
 		analyticsRepository,
-		inspectionClient,
-		brigadeClient,
-		subscriberClient,
-		fileClient,
-		a.settings.Templates,
+		analytics.Clients{
+			Inspection: inspectionClient,
+			Brigade:    brigadeService,
+			Subscriber: subscriberService,
+			File:       fileClient,
+		},
+		masker,
+		analytics.Settings{
+			Templates:   a.settings.Templates,
+			Ingestion:   a.settings.Ingestion,
+			Punctuality: a.settings.Punctuality,
+			Recidivism:  a.settings.Recidivism,
+			Devices:     a.settings.Devices,
+			Address:     a.settings.Address,
+			Tenancy:     a.settings.Tenancy,
+		},
 	)
 
-	a.cronService = cron.NewService(a.settings.Cron, a.analyticsService)
+	a.auditService = audit.NewService(auditRepository)
+
+	a.anomalyService = anomaly.NewService(dbanomaly.NewRepository(a.postgres, a.clickhouseNative), masker, a.settings.Anomaly)
+
+	a.erasureService = erasure.NewService(dberasure.NewRepository(a.postgres, a.clickhouseNative), a.analyticsService)
+
+	a.forecastService = forecast.NewService(dbforecast.NewRepository(a.clickhouseNative), a.settings.Forecast)
+
+	a.olapService = olap.NewService(dbolap.NewRepository(a.clickhouseNative), a.settings.OLAP)
+
+	kpiService := kpi.NewService(dbkpi.NewRepository(a.postgres, a.clickhouseNative), a.settings.Tenancy)
+
+	cronSettings := cron.Settings{Cron: a.settings.Cron, Tenants: a.settings.Tenancy.Tenants}
+	a.cronService = cron.NewService(cronSettings, a.analyticsService, a.auditService, kpiService, a.anomalyService)
+
+	checkers := []health.Checker{
+		health.NewSQLChecker("postgres", a.postgres),
+		health.NewSQLChecker("clickhouse", a.clickhouse),
+		health.NewPingChecker("clickhouseNative", a.clickhouseNative),
+		health.NewKafkaChecker(a.settings.Databases.Kafka.Brokers, a.settings.Databases.Kafka.Topics.Tasks, serviceName,
+			a.settings.Health.MaxKafkaLag),
+	}
+	if a.settings.Health.ProbePeers {
+		checkers = append(checkers,
+			health.Informational(health.NewHTTPChecker("brigadeService", httpClient, a.settings.Cluster.BrigadeService)),
+			health.Informational(health.NewHTTPChecker("fileService", httpClient, a.settings.Cluster.FileService)),
+			health.Informational(health.NewHTTPChecker("inspectionService", httpClient, a.settings.Cluster.InspectionService)),
+			health.Informational(health.NewHTTPChecker("subscriberService", httpClient, a.settings.Cluster.SubscriberService)),
+		)
+	}
+
+	a.healthService = health.NewService(time.Duration(a.settings.Health.Timeout), checkers...)
 
 	return nil
 }
 
+// newEnrichmentCache keeps values in process memory and, if Redis is configured, shares them between replicas.
+func newEnrichmentCache[V any](a *App, name string) cache.Cache[V] {
+	ttl := time.Duration(a.settings.Cluster.Cache.TTL)
+
+	var c cache.Cache[V] = cache.NewLRU[V](a.settings.Cluster.Cache.Size, ttl)
+	if a.redis != nil {
+		c = cache.NewTiered[V](c, cache.NewRedis[V](a.redis, serviceName+":"+name+":", ttl))
+	}
+
+	return cache.NewInstrumented(c, name)
+}
+
 func (a *App) InitServer() {
 	sb := api.NewServerBuilder(a.mainCtx, a.log, a.settings)
 	sb.AddDebug()
-	sb.AddReports(a.analyticsService)
+	sb.AddHealth(a.healthService)
+	sb.AddReports(a.analyticsService, a.auditService)
+	sb.AddInspectors(a.analyticsService)
+	sb.AddPunctuality(a.analyticsService)
+	sb.AddTurnaround(a.analyticsService)
+	sb.AddWatchList(a.analyticsService, a.auditService)
+	sb.AddDevices(a.analyticsService)
+	sb.AddComparison(a.analyticsService)
+	sb.AddAnomalies(a.anomalyService)
+	sb.AddForecast(a.forecastService)
+	sb.AddOLAP(a.olapService)
+	sb.AddAudit(a.auditService)
+	sb.AddErasure(a.erasureService, a.auditService)
+	sb.AddQuarantine(a.analyticsService)
+	sb.AddReplay(a.analyticsService)
 
 	a.server = sb.Build()
 }
 
 func (a *App) Start() error {
 	a.server.Start()
-	a.taskConsumer.Subscribe(a.analyticsService.SubscriberOnTaskEvent(a.mainCtx, a.log.WithTags("taskSubscriber")))
+	a.taskConsumer.Subscribe(a.mainCtx, a.analyticsService.SubscriberOnTaskEvent(a.mainCtx, a.log.WithTags("taskSubscriber")))
+	a.analyticsService.StartReplays(a.mainCtx, a.log.WithTags("replay"))
 
 	if err := a.cronService.Start(a.mainCtx, a.log.WithTags("cronService")); err != nil {
 		return fmt.Errorf("start cron: %w", err)
@@ -160,9 +318,19 @@ func (a *App) Stop(ctx context.Context) {
 		a.log.Errorf("failed to stop cron: %v", err)
 	}
 
+	a.analyticsService.StopReplays()
+
+	a.taskConsumer.Stop()
+	a.analyticsService.WaitTaskEvents()
+
 	consumerCtx, cancelConsumerCtx := context.WithTimeout(ctx, dbTimeout)
 	defer cancelConsumerCtx()
 
+	// Offsets of the flushed messages are committed when the consumer is closed.
+	if err = a.analyticsService.FlushBatches(consumerCtx); err != nil {
+		a.log.Errorf("failed to flush ingestion batches: %v", err)
+	}
+
 	if err = a.taskConsumer.Close(consumerCtx); err != nil {
 		a.log.Errorf("failed to close task consumer: %v", err)
 	}
@@ -180,4 +348,17 @@ func (a *App) Stop(ctx context.Context) {
 	if err = a.postgres.Close(); err != nil {
 		a.log.Errorf("failed to close postgres connection: %v", err)
 	}
+
+	if a.redis != nil {
+		if err = a.redis.Close(); err != nil {
+			a.log.Errorf("failed to close redis connection: %v", err)
+		}
+	}
+
+	tracingCtx, cancelTracingCtx := context.WithTimeout(ctx, dbTimeout)
+	defer cancelTracingCtx()
+
+	if err = a.shutdownTracing(tracingCtx); err != nil {
+		a.log.Errorf("failed to shutdown tracing: %v", err)
+	}
 }
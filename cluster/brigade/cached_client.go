@@ -0,0 +1,44 @@
+package brigade
+
+import (
+	"analytics-service/cluster/cache"
+	"context"
+	"strconv"
+
+	"github.com/sunshineOfficial/golib/goctx"
+)
+
+// CachedClient serves brigades from cache and falls back to brigade-service on a miss or a cache error.
+type CachedClient struct {
+	client *Client
+	cache  cache.Cache[Brigade]
+}
+
+func NewCachedClient(client *Client, c cache.Cache[Brigade]) *CachedClient {
+	return &CachedClient{
+		client: client,
+		cache:  c,
+	}
+}
+
+func (c *CachedClient) GetBrigadeByID(ctx goctx.Context, id int) (Brigade, error) {
+	key := strconv.Itoa(id)
+
+	if brigade, ok, err := c.cache.Get(ctx, key); err == nil && ok {
+		return brigade, nil
+	}
+
+	brigade, err := c.client.GetBrigadeByID(ctx, id)
+	if err != nil {
+		return Brigade{}, err
+	}
+
+	_ = c.cache.Set(ctx, key, brigade)
+
+	return brigade, nil
+}
+
+// Invalidate drops the cached brigade, it should be called when the brigade composition changes.
+func (c *CachedClient) Invalidate(ctx context.Context, id int) error {
+	return c.cache.Delete(ctx, strconv.Itoa(id))
+}
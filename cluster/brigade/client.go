@@ -1,19 +1,19 @@
 package brigade
 
 import (
+	"analytics-service/cluster/resilient"
+	"context"
 	"fmt"
-	"net/http"
 
 	"github.com/sunshineOfficial/golib/goctx"
-	"github.com/sunshineOfficial/golib/gohttp"
 )
 
 type Client struct {
-	client  gohttp.Client
+	client  *resilient.Client
 	baseURL string
 }
 
-func NewClient(client gohttp.Client, baseURL string) *Client {
+func NewClient(client *resilient.Client, baseURL string) *Client {
 	return &Client{
 		client:  client,
 		baseURL: baseURL,
@@ -22,14 +22,14 @@ func NewClient(client gohttp.Client, baseURL string) *Client {
 
 func (c *Client) GetBrigadeByID(ctx goctx.Context, id int) (Brigade, error) {
 	var response Brigade
-	status, err := c.client.DoJson(ctx, http.MethodGet, fmt.Sprintf("%s/brigades/%d", c.baseURL, id), nil, &response)
-	if err != nil {
-		return Brigade{}, fmt.Errorf("c.client.DoJson: %w", err)
-	}
-
-	if status != http.StatusOK {
-		return Brigade{}, fmt.Errorf("unexpected status code: %d", status)
+	if err := c.client.GetJson(ctx, fmt.Sprintf("%s/brigades/%d", c.baseURL, id), &response); err != nil {
+		return Brigade{}, fmt.Errorf("c.client.GetJson: %w", err)
 	}
 
 	return response, nil
 }
+
+// Invalidate does nothing, Client doesn't cache. It's there so Client and CachedClient are interchangeable.
+func (c *Client) Invalidate(context.Context, int) error {
+	return nil
+}
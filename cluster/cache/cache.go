@@ -0,0 +1,10 @@
+package cache
+
+import "context"
+
+// Cache stores enrichment data fetched from peer services. A miss is reported with ok == false and no error.
+type Cache[V any] interface {
+	Get(ctx context.Context, key string) (value V, ok bool, err error)
+	Set(ctx context.Context, key string, value V) error
+	Delete(ctx context.Context, key string) error
+}
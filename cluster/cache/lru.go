@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type lruEntry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// LRU is an in-process cache that evicts the least recently used entry when full and expires entries after ttl.
+type LRU[V any] struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+func NewLRU[V any](size int, ttl time.Duration) *LRU[V] {
+	return &LRU[V]{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+		now:     time.Now,
+	}
+}
+
+func (c *LRU[V]) Get(_ context.Context, key string) (V, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+
+	element, ok := c.entries[key]
+	if !ok {
+		return zero, false, nil
+	}
+
+	entry := element.Value.(*lruEntry[V]) //nolint:errcheck // only *lruEntry[V] is stored in the list
+	if c.ttl > 0 && !c.now().Before(entry.expiresAt) {
+		c.remove(element)
+		return zero, false, nil
+	}
+
+	c.order.MoveToFront(element)
+
+	return entry.value, true, nil
+}
+
+func (c *LRU[V]) Set(_ context.Context, key string, value V) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry[V]) //nolint:errcheck // only *lruEntry[V] is stored in the list
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry[V]{
+		key:       key,
+		value:     value,
+		expiresAt: expiresAt,
+	})
+
+	for c.size > 0 && c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+func (c *LRU[V]) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	return nil
+}
+
+func (c *LRU[V]) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*lruEntry[V]) //nolint:errcheck // only *lruEntry[V] is stored in the list
+	delete(c.entries, entry.key)
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU[int](2, time.Minute)
+
+	_ = c.Set(ctx, "a", 1)
+	_ = c.Set(ctx, "b", 2)
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+
+	_ = c.Set(ctx, "c", 3)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	if v, ok, _ := c.Get(ctx, "a"); !ok || v != 1 {
+		t.Fatalf("expected a = 1, got %d (ok = %v)", v, ok)
+	}
+	if v, ok, _ := c.Get(ctx, "c"); !ok || v != 3 {
+		t.Fatalf("expected c = 3, got %d (ok = %v)", v, ok)
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	c := NewLRU[string](10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	_ = c.Set(ctx, "key", "value")
+
+	now = now.Add(59 * time.Second)
+	if _, ok, _ := c.Get(ctx, "key"); !ok {
+		t.Fatal("expected key to be cached before ttl")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok, _ := c.Get(ctx, "key"); ok {
+		t.Fatal("expected key to expire after ttl")
+	}
+}
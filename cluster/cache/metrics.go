@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	resultHit   = "hit"
+	resultMiss  = "miss"
+	resultError = "error"
+)
+
+var lookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "analytics",
+	Subsystem: "enrichment_cache",
+	Name:      "lookups_total",
+	Help:      "Enrichment cache lookups by cache name and result (hit, miss or error).",
+}, []string{"cache", "result"})
+
+// Instrumented counts hits, misses and errors of the wrapped cache.
+type Instrumented[V any] struct {
+	cache Cache[V]
+	name  string
+}
+
+func NewInstrumented[V any](cache Cache[V], name string) *Instrumented[V] {
+	return &Instrumented[V]{
+		cache: cache,
+		name:  name,
+	}
+}
+
+func (c *Instrumented[V]) Get(ctx context.Context, key string) (V, bool, error) {
+	value, ok, err := c.cache.Get(ctx, key)
+
+	switch {
+	case err != nil:
+		lookupsTotal.WithLabelValues(c.name, resultError).Inc()
+	case ok:
+		lookupsTotal.WithLabelValues(c.name, resultHit).Inc()
+	default:
+		lookupsTotal.WithLabelValues(c.name, resultMiss).Inc()
+	}
+
+	return value, ok, err
+}
+
+func (c *Instrumented[V]) Set(ctx context.Context, key string, value V) error {
+	return c.cache.Set(ctx, key, value)
+}
+
+func (c *Instrumented[V]) Delete(ctx context.Context, key string) error {
+	return c.cache.Delete(ctx, key)
+}
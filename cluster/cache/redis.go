@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis shares cached values between service replicas. Values are stored as JSON under prefix + key.
+type Redis[V any] struct {
+	client redis.UniversalClient
+	prefix string
+	ttl    time.Duration
+}
+
+func NewRedis[V any](client redis.UniversalClient, prefix string, ttl time.Duration) *Redis[V] {
+	return &Redis[V]{
+		client: client,
+		prefix: prefix,
+		ttl:    ttl,
+	}
+}
+
+func (c *Redis[V]) Get(ctx context.Context, key string) (V, bool, error) {
+	var value V
+
+	data, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return value, false, nil
+	}
+	if err != nil {
+		return value, false, fmt.Errorf("c.client.Get: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &value); err != nil {
+		return value, false, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return value, true, nil
+}
+
+func (c *Redis[V]) Set(ctx context.Context, key string, value V) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	if err = c.client.Set(ctx, c.prefix+key, data, c.ttl).Err(); err != nil {
+		return fmt.Errorf("c.client.Set: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Redis[V]) Delete(ctx context.Context, key string) error {
+	if err := c.client.Del(ctx, c.prefix+key).Err(); err != nil {
+		return fmt.Errorf("c.client.Del: %w", err)
+	}
+
+	return nil
+}
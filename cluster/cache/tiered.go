@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// sharedRetryAfter is how long Tiered serves from the local cache only after the shared one failed.
+const sharedRetryAfter = 30 * time.Second
+
+// Tiered looks up the fast local cache first and fills it from the shared one on a hit. While the shared cache fails,
+// it's skipped for sharedRetryAfter, so lookups don't wait for it.
+type Tiered[V any] struct {
+	local  Cache[V]
+	shared Cache[V]
+
+	sharedDownUntil atomic.Int64
+}
+
+func NewTiered[V any](local, shared Cache[V]) *Tiered[V] {
+	return &Tiered[V]{
+		local:  local,
+		shared: shared,
+	}
+}
+
+func (c *Tiered[V]) Get(ctx context.Context, key string) (V, bool, error) {
+	value, ok, err := c.local.Get(ctx, key)
+	if (err == nil && ok) || c.sharedDown() {
+		return value, ok, err
+	}
+
+	value, ok, err = c.shared.Get(ctx, key)
+	if err != nil || !ok {
+		return value, ok, c.checkShared(err)
+	}
+
+	return value, true, c.local.Set(ctx, key, value)
+}
+
+func (c *Tiered[V]) Set(ctx context.Context, key string, value V) error {
+	err := c.local.Set(ctx, key, value)
+	if c.sharedDown() {
+		return err
+	}
+
+	return errors.Join(err, c.checkShared(c.shared.Set(ctx, key, value)))
+}
+
+// Delete always tries the shared cache, so an invalidation isn't lost while it's considered down.
+func (c *Tiered[V]) Delete(ctx context.Context, key string) error {
+	return errors.Join(c.local.Delete(ctx, key), c.checkShared(c.shared.Delete(ctx, key)))
+}
+
+func (c *Tiered[V]) sharedDown() bool {
+	return time.Now().UnixNano() < c.sharedDownUntil.Load()
+}
+
+func (c *Tiered[V]) checkShared(err error) error {
+	if err != nil {
+		c.sharedDownUntil.Store(time.Now().Add(sharedRetryAfter).UnixNano())
+	}
+
+	return err
+}
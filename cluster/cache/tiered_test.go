@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type failingCache[V any] struct {
+	calls int
+}
+
+func (c *failingCache[V]) Get(context.Context, string) (V, bool, error) {
+	c.calls++
+
+	var zero V
+	return zero, false, errors.New("connection refused")
+}
+
+func (c *failingCache[V]) Set(context.Context, string, V) error {
+	c.calls++
+	return errors.New("connection refused")
+}
+
+func (c *failingCache[V]) Delete(context.Context, string) error {
+	c.calls++
+	return errors.New("connection refused")
+}
+
+func TestTieredServesLocalWhileSharedIsDown(t *testing.T) {
+	ctx := context.Background()
+	shared := &failingCache[int]{}
+	c := NewTiered[int](NewLRU[int](2, time.Minute), shared)
+
+	if _, _, err := c.Get(ctx, "a"); err == nil {
+		t.Fatal("expected the shared cache error")
+	}
+
+	if err := c.Set(ctx, "a", 1); err != nil {
+		t.Fatalf("expected the local cache to be used only, got %v", err)
+	}
+	if v, ok, err := c.Get(ctx, "a"); err != nil || !ok || v != 1 {
+		t.Fatalf("expected a = 1 from the local cache, got %d (ok = %v, err = %v)", v, ok, err)
+	}
+	if _, ok, err := c.Get(ctx, "b"); err != nil || ok {
+		t.Fatalf("expected a miss without the shared cache, got ok = %v, err = %v", ok, err)
+	}
+	if shared.calls != 1 {
+		t.Fatalf("expected the shared cache to be skipped after a failure, got %d calls", shared.calls)
+	}
+}
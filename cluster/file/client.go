@@ -1,6 +1,7 @@
 package file
 
 import (
+	"analytics-service/cluster/resilient"
 	"errors"
 	"fmt"
 	"io"
@@ -11,11 +12,11 @@ import (
 )
 
 type Client struct {
-	client  gohttp.Client
+	client  *resilient.Client
 	baseURL string
 }
 
-func NewClient(client gohttp.Client, baseURL string) *Client {
+func NewClient(client *resilient.Client, baseURL string) *Client {
 	return &Client{
 		client:  client,
 		baseURL: baseURL,
@@ -42,30 +43,20 @@ func (c *Client) Upload(ctx goctx.Context, fileName string, file io.Reader) (Fil
 
 	rs, err := c.client.Do(rq)
 	if err != nil {
-		if rs != nil && rs.Body != nil {
-			closeErr := rs.Body.Close()
-			err = errors.Join(err, closeErr)
-		}
-
 		return File{}, fmt.Errorf("c.client.Do: %w", err)
 	}
 
-	if rs == nil {
-		return File{}, errors.New("got nil response from server")
-	}
-
-	if rs.StatusCode != http.StatusOK {
+	if err = resilient.CheckStatus(rs.StatusCode); err != nil {
 		if rs.Body != nil {
-			closeErr := rs.Body.Close()
-			err = errors.Join(err, closeErr)
+			err = errors.Join(err, rs.Body.Close())
 		}
 
-		return File{}, errors.Join(err, fmt.Errorf("got status code %d", rs.StatusCode))
+		return File{}, err
 	}
 
 	var response File
 	if err = gohttp.ReadResponseJson(rs, &response); err != nil {
-		return File{}, fmt.Errorf("ReadResponseJson: %w", err)
+		return File{}, fmt.Errorf("%w: ReadResponseJson: %w", resilient.ErrBadPayload, err)
 	}
 
 	return response, nil
@@ -78,13 +69,8 @@ func (c *Client) GetFilesByIDs(ctx goctx.Context, ids []int) ([]File, error) {
 	}
 
 	var response []File
-	status, err := c.client.DoJson(ctx, http.MethodGet, url, nil, &response)
-	if err != nil {
-		return nil, fmt.Errorf("c.client.DoJson: %w", err)
-	}
-
-	if status != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", status)
+	if err = c.client.GetJson(ctx, url, &response); err != nil {
+		return nil, fmt.Errorf("c.client.GetJson: %w", err)
 	}
 
 	return response, nil
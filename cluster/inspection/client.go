@@ -1,19 +1,18 @@
 package inspection
 
 import (
+	"analytics-service/cluster/resilient"
 	"fmt"
-	"net/http"
 
 	"github.com/sunshineOfficial/golib/goctx"
-	"github.com/sunshineOfficial/golib/gohttp"
 )
 
 type Client struct {
-	client  gohttp.Client
+	client  *resilient.Client
 	baseURL string
 }
 
-func NewClient(client gohttp.Client, baseURL string) *Client {
+func NewClient(client *resilient.Client, baseURL string) *Client {
 	return &Client{
 		client:  client,
 		baseURL: baseURL,
@@ -22,13 +21,8 @@ func NewClient(client gohttp.Client, baseURL string) *Client {
 
 func (c *Client) GetInspectionByTaskID(ctx goctx.Context, taskID int) (Inspection, error) {
 	var response Inspection
-	status, err := c.client.DoJson(ctx, http.MethodGet, fmt.Sprintf("%s/inspections/task/%d", c.baseURL, taskID), nil, &response)
-	if err != nil {
-		return Inspection{}, fmt.Errorf("c.client.DoJson: %w", err)
-	}
-
-	if status != http.StatusOK {
-		return Inspection{}, fmt.Errorf("unexpected status code: %d", status)
+	if err := c.client.GetJson(ctx, fmt.Sprintf("%s/inspections/task/%d", c.baseURL, taskID), &response); err != nil {
+		return Inspection{}, fmt.Errorf("c.client.GetJson: %w", err)
 	}
 
 	return response, nil
@@ -0,0 +1,83 @@
+package resilient
+
+import (
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// breaker opens after maxFailures consecutive failures and lets a single probe request through after openTimeout.
+type breaker struct {
+	mu          sync.Mutex
+	state       breakerState
+	failures    int
+	openedAt    time.Time
+	maxFailures int
+	openTimeout time.Duration
+}
+
+func newBreaker(maxFailures int, openTimeout time.Duration) *breaker {
+	return &breaker{
+		maxFailures: maxFailures,
+		openTimeout: openTimeout,
+	}
+}
+
+func (b *breaker) allow(now time.Time) error {
+	if b.maxFailures <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if now.Sub(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		return ErrCircuitOpen
+	default:
+		return nil
+	}
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = breakerClosed
+	b.failures = 0
+}
+
+func (b *breaker) failure(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || (b.maxFailures > 0 && b.failures >= b.maxFailures) {
+		b.state = breakerOpen
+		b.openedAt = now
+	}
+}
+
+// cancel gives up a request that neither failed nor succeeded. A cancelled probe lets the next request probe again.
+func (b *breaker) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
@@ -0,0 +1,72 @@
+package resilient
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAndProbesAfterTimeout(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	b := newBreaker(2, time.Minute)
+
+	b.failure(now)
+	if err := b.allow(now); err != nil {
+		t.Fatalf("expected closed breaker after one failure, got %v", err)
+	}
+
+	b.failure(now)
+	if err := b.allow(now.Add(30 * time.Second)); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected open breaker, got %v", err)
+	}
+
+	if err := b.allow(now.Add(time.Minute)); err != nil {
+		t.Fatalf("expected probe to be allowed, got %v", err)
+	}
+	if err := b.allow(now.Add(time.Minute)); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected only one probe, got %v", err)
+	}
+
+	b.failure(now.Add(time.Minute))
+	if err := b.allow(now.Add(90 * time.Second)); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected breaker to reopen after failed probe, got %v", err)
+	}
+
+	if err := b.allow(now.Add(2 * time.Minute)); err != nil {
+		t.Fatalf("expected probe to be allowed, got %v", err)
+	}
+	b.success()
+	if err := b.allow(now.Add(2 * time.Minute)); err != nil {
+		t.Fatalf("expected closed breaker after successful probe, got %v", err)
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	for status, want := range map[int]error{
+		200: nil,
+		404: ErrNotFound,
+		429: ErrUnavailable,
+		503: ErrUnavailable,
+		400: ErrUnexpectedStatus,
+	} {
+		err := CheckStatus(status)
+		if !errors.Is(err, want) || (want == nil && err != nil) {
+			t.Fatalf("status %d: expected %v, got %v", status, want, err)
+		}
+	}
+}
+
+func TestBreakerCancelledProbeLetsNextProbe(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	b := newBreaker(1, time.Minute)
+
+	b.failure(now)
+	if err := b.allow(now.Add(time.Minute)); err != nil {
+		t.Fatalf("expected probe to be allowed, got %v", err)
+	}
+
+	b.cancel()
+	if err := b.allow(now.Add(time.Minute)); err != nil {
+		t.Fatalf("expected another probe after a cancelled one, got %v", err)
+	}
+}
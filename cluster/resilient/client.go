@@ -0,0 +1,193 @@
+package resilient
+
+import (
+	"analytics-service/config"
+	"analytics-service/tracing"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/gohttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("analytics-service/cluster/resilient")
+
+// Client wraps gohttp.Client with per-host circuit breakers, retries of GET requests and typed errors.
+type Client struct {
+	client   gohttp.Client
+	settings config.Resilience
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+func NewClient(client gohttp.Client, settings config.Resilience) *Client {
+	return &Client{
+		client:   client,
+		settings: settings,
+		breakers: make(map[string]*breaker),
+	}
+}
+
+// GetJson sends a GET request and decodes a 200 response into response. Unavailable errors are retried.
+func (c *Client) GetJson(ctx goctx.Context, rawURL string, response any) error {
+	attempts := max(c.settings.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = c.getJsonOnce(ctx, rawURL, response)
+		if err == nil || !errors.Is(err, ErrUnavailable) || errors.Is(err, ErrCircuitOpen) || attempt >= attempts {
+			return err
+		}
+
+		timer := time.NewTimer(c.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) getJsonOnce(ctx goctx.Context, rawURL string, response any) error {
+	rq, err := gohttp.NewRequest(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("NewRequest: %w", err)
+	}
+
+	rs, err := c.Do(rq)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rs.Body.Close()
+	}()
+
+	if err = CheckStatus(rs.StatusCode); err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(rs.Body)
+	if err != nil {
+		return fmt.Errorf("%w: read body: %w", ErrUnavailable, err)
+	}
+
+	if err = json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadPayload, err)
+	}
+
+	return nil
+}
+
+// Do sends the request through the circuit breaker of its host without retries. The trace context of the request
+// is propagated to the peer. The response body must be closed by the caller if there is no error.
+func (c *Client) Do(rq *http.Request) (rs *http.Response, err error) {
+	ctx, span := tracer.Start(rq.Context(), rq.Method+" "+rq.URL.Host, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", rq.Method),
+			attribute.String("server.address", rq.URL.Host),
+			attribute.String("url.path", rq.URL.Path),
+		))
+	defer func() {
+		if rs != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", rs.StatusCode))
+		}
+
+		tracing.End(span, err)
+	}()
+
+	rq = rq.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(rq.Header))
+
+	b := c.breaker(rq.URL)
+	if err = b.allow(time.Now()); err != nil {
+		return nil, fmt.Errorf("%s: %w", rq.URL.Host, err)
+	}
+
+	rs, err = c.client.Do(rq)
+	if err != nil {
+		if rs != nil && rs.Body != nil {
+			err = errors.Join(err, rs.Body.Close())
+		}
+
+		// A cancelled request says nothing about the host, e.g. enrichment cancels the other lookups once one fails.
+		if rq.Context().Err() != nil || errors.Is(err, context.Canceled) {
+			b.cancel()
+			return nil, fmt.Errorf("c.client.Do: %w", err)
+		}
+
+		b.failure(time.Now())
+		return nil, fmt.Errorf("%w: c.client.Do: %w", ErrUnavailable, err)
+	}
+
+	if rs == nil {
+		b.failure(time.Now())
+		return nil, fmt.Errorf("%w: got nil response from server", ErrUnavailable)
+	}
+
+	if isUnavailableStatus(rs.StatusCode) {
+		b.failure(time.Now())
+	} else {
+		b.success()
+	}
+
+	return rs, nil
+}
+
+func (c *Client) breaker(u *url.URL) *breaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.breakers[u.Host]
+	if !ok {
+		b = newBreaker(c.settings.BreakerFailures, time.Duration(c.settings.BreakerOpenTimeout))
+		c.breakers[u.Host] = b
+	}
+
+	return b
+}
+
+// backoff grows exponentially with full jitter and is capped by MaxRetryBackoff.
+func (c *Client) backoff(attempt int) time.Duration {
+	backoff := time.Duration(c.settings.RetryBackoff) << (attempt - 1)
+	if maxBackoff := time.Duration(c.settings.MaxRetryBackoff); maxBackoff > 0 && (backoff > maxBackoff || backoff <= 0) {
+		backoff = maxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+
+	return rand.N(backoff) + 1
+}
+
+// CheckStatus converts a response status code into one of the typed errors.
+func CheckStatus(statusCode int) error {
+	switch {
+	case statusCode == http.StatusOK:
+		return nil
+	case statusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: status code %d", ErrNotFound, statusCode)
+	case isUnavailableStatus(statusCode):
+		return fmt.Errorf("%w: status code %d", ErrUnavailable, statusCode)
+	default:
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, statusCode)
+	}
+}
+
+func isUnavailableStatus(statusCode int) bool {
+	return statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests ||
+		statusCode == http.StatusRequestTimeout
+}
@@ -0,0 +1,41 @@
+package resilient
+
+import (
+	"analytics-service/config"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/sunshineOfficial/golib/gohttp"
+)
+
+type cancelledClient struct {
+	gohttp.Client
+}
+
+func (cancelledClient) Do(rq *http.Request) (*http.Response, error) {
+	return nil, rq.Context().Err()
+}
+
+func TestDoDoesNotCountCancelledRequests(t *testing.T) {
+	c := NewClient(cancelledClient{}, config.Resilience{BreakerFailures: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rq, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://brigade-service/brigades/1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for range 3 {
+		if _, err = c.Do(rq); !errors.Is(err, context.Canceled) || errors.Is(err, ErrUnavailable) {
+			t.Fatalf("expected a cancelled request, got %v", err)
+		}
+	}
+
+	if b := c.breaker(rq.URL); b.state != breakerClosed || b.failures != 0 {
+		t.Fatalf("expected closed breaker without failures, got state %d with %d failures", b.state, b.failures)
+	}
+}
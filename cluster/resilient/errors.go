@@ -0,0 +1,19 @@
+package resilient
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound means the peer answered 404, retrying won't help.
+	ErrNotFound = errors.New("not found")
+	// ErrUnavailable means the peer is unreachable, overloaded or failing, the request may succeed later.
+	ErrUnavailable = errors.New("unavailable")
+	// ErrBadPayload means the peer answered with a body that can't be decoded.
+	ErrBadPayload = errors.New("bad payload")
+	// ErrUnexpectedStatus means the peer rejected the request, e.g. with 400.
+	ErrUnexpectedStatus = errors.New("unexpected status code")
+
+	ErrCircuitOpen = fmt.Errorf("%w: circuit breaker is open", ErrUnavailable)
+)
@@ -0,0 +1,46 @@
+package subscriber
+
+import (
+	"analytics-service/cluster/cache"
+	"context"
+	"strconv"
+
+	"github.com/sunshineOfficial/golib/goctx"
+)
+
+// CachedClient serves last contracts by object from cache and falls back to subscriber-service
+// on a miss or a cache error.
+type CachedClient struct {
+	client *Client
+	cache  cache.Cache[Contract]
+}
+
+func NewCachedClient(client *Client, c cache.Cache[Contract]) *CachedClient {
+	return &CachedClient{
+		client: client,
+		cache:  c,
+	}
+}
+
+func (c *CachedClient) GetLastContractByObjectID(ctx goctx.Context, objectID int) (Contract, error) {
+	key := strconv.Itoa(objectID)
+
+	if contract, ok, err := c.cache.Get(ctx, key); err == nil && ok {
+		return contract, nil
+	}
+
+	contract, err := c.client.GetLastContractByObjectID(ctx, objectID)
+	if err != nil {
+		return Contract{}, err
+	}
+
+	_ = c.cache.Set(ctx, key, contract)
+
+	return contract, nil
+}
+
+// Invalidate drops the cached contract of the object, it should be called when a contract, subscriber
+// or object changes.
+func (c *CachedClient) Invalidate(ctx context.Context, objectID int) error {
+	return c.cache.Delete(ctx, strconv.Itoa(objectID))
+}
@@ -1,19 +1,19 @@
 package subscriber
 
 import (
+	"analytics-service/cluster/resilient"
+	"context"
 	"fmt"
-	"net/http"
 
 	"github.com/sunshineOfficial/golib/goctx"
-	"github.com/sunshineOfficial/golib/gohttp"
 )
 
 type Client struct {
-	client  gohttp.Client
+	client  *resilient.Client
 	baseURL string
 }
 
-func NewClient(client gohttp.Client, baseURL string) *Client {
+func NewClient(client *resilient.Client, baseURL string) *Client {
 	return &Client{
 		client:  client,
 		baseURL: baseURL,
@@ -22,14 +22,14 @@ func NewClient(client gohttp.Client, baseURL string) *Client {
 
 func (c *Client) GetLastContractByObjectID(ctx goctx.Context, objectID int) (Contract, error) {
 	var response Contract
-	status, err := c.client.DoJson(ctx, http.MethodGet, fmt.Sprintf("%s/contracts/objects/%d/last", c.baseURL, objectID), nil, &response)
-	if err != nil {
-		return Contract{}, fmt.Errorf("c.client.DoJson: %w", err)
-	}
-
-	if status != http.StatusOK {
-		return Contract{}, fmt.Errorf("unexpected status code: %d", status)
+	if err := c.client.GetJson(ctx, fmt.Sprintf("%s/contracts/objects/%d/last", c.baseURL, objectID), &response); err != nil {
+		return Contract{}, fmt.Errorf("c.client.GetJson: %w", err)
 	}
 
 	return response, nil
 }
+
+// Invalidate does nothing, Client doesn't cache. It's there so Client and CachedClient are interchangeable.
+func (c *Client) Invalidate(context.Context, int) error {
+	return nil
+}
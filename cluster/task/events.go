@@ -12,6 +12,22 @@ const (
 	EventTypeAssign
 )
 
+// Name is used as a metrics label.
+func (t EventType) Name() string {
+	switch t {
+	case EventTypeAdd:
+		return "add"
+	case EventTypeStart:
+		return "start"
+	case EventTypeFinish:
+		return "finish"
+	case EventTypeAssign:
+		return "assign"
+	default:
+		return "unknown"
+	}
+}
+
 type Event struct {
 	Type   EventType `json:"Type"`
 	Date   time.Time `json:"Date"`
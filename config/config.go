@@ -35,5 +35,9 @@ func Get(log golog.Logger) (Settings, error) {
 		settings.Databases.Clickhouse.Database,
 	)
 
+	settings.Databases.Redis.Password = os.Getenv("REDIS_PASSWORD")
+
+	settings.Masking.HashSalt = os.Getenv("MASKING_HASH_SALT")
+
 	return settings, nil
 }
@@ -3,17 +3,38 @@ package config
 import "github.com/sunshineOfficial/golib/gotime"
 
 type Settings struct {
-	Port      int       `json:"port"`
-	Databases Databases `json:"databases"`
-	Cluster   Cluster   `json:"cluster"`
-	Templates Templates `json:"templates"`
-	Cron      Cron      `json:"cron"`
+	Port        int         `json:"port"`
+	Databases   Databases   `json:"databases"`
+	Cluster     Cluster     `json:"cluster"`
+	Templates   Templates   `json:"templates"`
+	Cron        Cron        `json:"cron"`
+	Masking     Masking     `json:"masking"`
+	Health      Health      `json:"health"`
+	Ingestion   Ingestion   `json:"ingestion"`
+	Tracing     Tracing     `json:"tracing"`
+	Punctuality Punctuality `json:"punctuality"`
+	Recidivism  Recidivism  `json:"recidivism"`
+	Anomaly     Anomaly     `json:"anomaly"`
+	Devices     Devices     `json:"devices"`
+	Forecast    Forecast    `json:"forecast"`
+	OLAP        OLAP        `json:"olap"`
+	Address     Address     `json:"address"`
+	Tenancy     Tenancy     `json:"tenancy"`
+	Retention   Retention   `json:"retention"`
 }
 
 type Databases struct {
 	Postgres   string     `json:"postgres"`
 	Clickhouse Clickhouse `json:"clickhouse"`
 	Kafka      Kafka      `json:"kafka"`
+	Redis      Redis      `json:"redis"`
+}
+
+// Redis is optional, it's not used if Address is empty.
+type Redis struct {
+	Address  string `json:"address"`
+	DB       int    `json:"db"`
+	Password string `json:"password"`
 }
 
 type Clickhouse struct {
@@ -25,9 +46,11 @@ type Clickhouse struct {
 	Password         string `json:"password"`
 }
 
+// Kafka offsets of handled messages are committed every CommitInterval, zero means every second.
 type Kafka struct {
-	Brokers []string `json:"brokers"`
-	Topics  Topics   `json:"topics"`
+	Brokers        []string        `json:"brokers"`
+	Topics         Topics          `json:"topics"`
+	CommitInterval gotime.Duration `json:"commitInterval"`
 }
 
 type Topics struct {
@@ -35,18 +58,180 @@ type Topics struct {
 }
 
 type Cluster struct {
-	BrigadeService    string `json:"brigadeService"`
-	FileService       string `json:"fileService"`
-	InspectionService string `json:"inspectionService"`
-	SubscriberService string `json:"subscriberService"`
-	TaskService       string `json:"taskService"`
+	BrigadeService    string     `json:"brigadeService"`
+	FileService       string     `json:"fileService"`
+	InspectionService string     `json:"inspectionService"`
+	SubscriberService string     `json:"subscriberService"`
+	TaskService       string     `json:"taskService"`
+	Resilience        Resilience `json:"resilience"`
+	Cache             Cache      `json:"cache"`
+}
+
+// Resilience configures cluster clients. GET requests are retried up to MaxAttempts times on unavailable errors.
+// A host's circuit breaker opens after BreakerFailures consecutive failures, 0 disables it.
+type Resilience struct {
+	MaxAttempts        int             `json:"maxAttempts"`
+	RetryBackoff       gotime.Duration `json:"retryBackoff"`
+	MaxRetryBackoff    gotime.Duration `json:"maxRetryBackoff"`
+	BreakerFailures    int             `json:"breakerFailures"`
+	BreakerOpenTimeout gotime.Duration `json:"breakerOpenTimeout"`
+}
+
+// Cache configures the enrichment cache in front of brigade and subscriber lookups. Size 0 disables it.
+type Cache struct {
+	Size int             `json:"size"`
+	TTL  gotime.Duration `json:"ttl"`
 }
 
 type Templates struct {
 	BasicReport string `json:"basicReport"`
 }
 
+// Cron schedules background jobs. The watch-list report is created on Mondays at WatchListReportTime and covers
+// WatchListLookback, anomaly rules are evaluated daily at AnomalyEvaluationTime.
 type Cron struct {
-	DailyReportTime string          `json:"dailyReportTime"`
-	TaskTimeout     gotime.Duration `json:"taskTimeout"`
+	DailyReportTime       string          `json:"dailyReportTime"`
+	TaskTimeout           gotime.Duration `json:"taskTimeout"`
+	KPIRefreshInterval    gotime.Duration `json:"kpiRefreshInterval"`
+	WatchListReportTime   string          `json:"watchListReportTime"`
+	WatchListLookback     gotime.Duration `json:"watchListLookback"`
+	AnomalyEvaluationTime string          `json:"anomalyEvaluationTime"`
+}
+
+type Masking struct {
+	DefaultPolicy string          `json:"defaultPolicy"`
+	HashSalt      string          `json:"hashSalt"`
+	Policies      []MaskingPolicy `json:"policies"`
+}
+
+type MaskingPolicy struct {
+	Scope  string `json:"scope"`
+	Role   string `json:"role"`
+	Policy string `json:"policy"`
+}
+
+type Health struct {
+	Timeout     gotime.Duration `json:"timeout"`
+	MaxKafkaLag int64           `json:"maxKafkaLag"`
+	ProbePeers  bool            `json:"probePeers"`
+}
+
+// Ingestion configures handling of task events. Concurrency is the number of events handled at once,
+// QueueSize is the number of consumed events waiting for each worker. Finished tasks are inserted into ClickHouse
+// once BatchSize of them are collected or the oldest one waits for BatchMaxAge. Zero EnrichmentTimeout means 30s.
+type Ingestion struct {
+	Concurrency       int             `json:"concurrency"`
+	QueueSize         int             `json:"queueSize"`
+	EnrichmentTimeout gotime.Duration `json:"enrichmentTimeout"`
+	BatchSize         int             `json:"batchSize"`
+	BatchMaxAge       gotime.Duration `json:"batchMaxAge"`
+}
+
+// Tracing exports spans to stdout, to a file or to an OTLP/HTTP collector at Endpoint, "none" disables tracing.
+// SampleRatio applies to traces started by this service, incoming traces keep the caller's decision.
+type Tracing struct {
+	Exporter    string  `json:"exporter"`
+	Endpoint    string  `json:"endpoint"`
+	FilePath    string  `json:"filePath"`
+	SampleRatio float64 `json:"sampleRatio"`
+}
+
+// Punctuality is the tolerance window around PlanVisitAt, visits started inside it are on time.
+type Punctuality struct {
+	EarlyTolerance gotime.Duration `json:"earlyTolerance"`
+	LateTolerance  gotime.Duration `json:"lateTolerance"`
+}
+
+// Recidivism configures the watch-list rules: objects visited more than MaxVisitsPerQuarter times in a calendar quarter,
+// subscribers with at least MinViolations detected violations and subscribers with at least
+// MinUnauthorizedAfterLimitation unauthorized connections after a limitation at the same object are flagged.
+type Recidivism struct {
+	MaxVisitsPerQuarter            int `json:"maxVisitsPerQuarter"`
+	MinViolations                  int `json:"minViolations"`
+	MinUnauthorizedAfterLimitation int `json:"minUnauthorizedAfterLimitation"`
+}
+
+// Anomaly configures the anomaly engine, rules themselves are stored in Postgres.
+// Each evaluation flags the last LookbackMonths months including the current one.
+type Anomaly struct {
+	LookbackMonths int `json:"lookbackMonths"`
+}
+
+// Devices configures implausible jump detection in meter readings. A reading is a jump if the daily delta since
+// the previous reading exceeds MaxDailyDelta or JumpFactor times the median daily delta of the earlier readings.
+// Zero disables a check.
+type Devices struct {
+	MaxDailyDelta float64 `json:"maxDailyDelta"`
+	JumpFactor    float64 `json:"jumpFactor"`
+}
+
+// Forecast configures workload forecasting. Models are fitted on the last HistoryDays days, Alpha is the smoothing
+// factor of the level from (0, 1], MaxDays limits the forecast and backtest horizon.
+type Forecast struct {
+	HistoryDays int     `json:"historyDays"`
+	Alpha       float64 `json:"alpha"`
+	MaxDays     int     `json:"maxDays"`
+}
+
+// OLAP limits ad hoc metrics queries: the number of returned rows, the queried period and the execution time.
+// Zero MaxPeriodDays means any period, zero MaxRows and Timeout mean 1000 rows and 30 seconds.
+type OLAP struct {
+	MaxRows       int             `json:"maxRows"`
+	MaxPeriodDays int             `json:"maxPeriodDays"`
+	Timeout       gotime.Duration `json:"timeout"`
+}
+
+// Address configures splitting of object addresses into parts. Markers are the abbreviations and words that classify
+// a comma separated part of an address, the dictionary maps known spellings of regions, cities and districts to their
+// canonical names. Both are matched case-insensitively.
+type Address struct {
+	Markers    AddressMarkers    `json:"markers"`
+	Dictionary AddressDictionary `json:"dictionary"`
+}
+
+type AddressMarkers struct {
+	Region   []string `json:"region"`
+	City     []string `json:"city"`
+	District []string `json:"district"`
+	Street   []string `json:"street"`
+	House    []string `json:"house"`
+	Flat     []string `json:"flat"`
+}
+
+type AddressDictionary struct {
+	Regions   []AddressEntry `json:"regions"`
+	Cities    []AddressEntry `json:"cities"`
+	Districts []AddressEntry `json:"districts"`
+}
+
+// AddressEntry is a canonical name with its other spellings.
+type AddressEntry struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+// Tenancy splits finished tasks and reports between branches. A task belongs to the tenant of its brigade, otherwise
+// to the first tenant matching the parsed address region, city or district, otherwise to DefaultTenant.
+type Tenancy struct {
+	DefaultTenant string   `json:"defaultTenant"`
+	Tenants       []Tenant `json:"tenants"`
+}
+
+// Tenant is a branch. DailyReportTime and WatchListReportTime override the Cron ones for the branch reports.
+type Tenant struct {
+	ID                  string   `json:"id"`
+	Name                string   `json:"name"`
+	BrigadeIDs          []int    `json:"brigadeIDs"`
+	Regions             []string `json:"regions"`
+	Cities              []string `json:"cities"`
+	Districts           []string `json:"districts"`
+	DailyReportTime     string   `json:"dailyReportTime"`
+	WatchListReportTime string   `json:"watchListReportTime"`
+}
+
+// Retention is the number of days ClickHouse keeps finished tasks and raw events, the TTL migrations are generated
+// from it on start. Zero keeps the TTL of the SQL migrations.
+type Retention struct {
+	FinishedTasksDays int `json:"finishedTasksDays"`
+	RawEventsDays     int `json:"rawEventsDays"`
 }
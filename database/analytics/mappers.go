@@ -4,7 +4,11 @@ import (
 	"analytics-service/cluster/file"
 	"analytics-service/cluster/inspection"
 	"analytics-service/cluster/subscriber"
+	"analytics-service/service/address"
 	"analytics-service/service/analytics"
+	"analytics-service/service/masking"
+	"encoding/json"
+	"fmt"
 )
 
 func MapFinishedTaskToDB(t analytics.FinishedTask) FinishedTask {
@@ -36,7 +40,16 @@ func MapFinishedTaskToDB(t analytics.FinishedTask) FinishedTask {
 		BrigadeInspectors:                 MapInspectorSliceToDB(t.Brigade.Inspectors),
 		ObjectID:                          int64(t.Object.ID),
 		ObjectAddress:                     t.Object.Address,
+		ObjectRegion:                      t.Object.AddressParts.Region,
+		ObjectCity:                        t.Object.AddressParts.City,
+		ObjectDistrict:                    t.Object.AddressParts.District,
+		ObjectStreet:                      t.Object.AddressParts.Street,
+		ObjectHouse:                       t.Object.AddressParts.House,
+		ObjectFlat:                        t.Object.AddressParts.Flat,
 		ObjectHaveAutomaton:               t.Object.HaveAutomaton,
+		ObjectDevices:                     MapObjectDeviceSliceToDB(t.Object.Devices),
+		ContractNumber:                    t.Contract.Number,
+		ContractSignDate:                  t.Contract.SignDate,
 		SubscriberID:                      int64(t.Subscriber.ID),
 		SubscriberAccountNumber:           t.Subscriber.AccountNumber,
 		SubscriberSurname:                 t.Subscriber.Surname,
@@ -47,9 +60,19 @@ func MapFinishedTaskToDB(t analytics.FinishedTask) FinishedTask {
 		SubscriberINN:                     t.Subscriber.INN,
 		SubscriberBirthDate:               t.Subscriber.BirthDate,
 		SubscriberStatus:                  int8(t.Subscriber.Status),
+		Tenant:                            t.Tenant,
 	}
 }
 
+func MapFinishedTaskSliceToDB(tasks []analytics.FinishedTask) []FinishedTask {
+	result := make([]FinishedTask, 0, len(tasks))
+	for _, t := range tasks {
+		result = append(result, MapFinishedTaskToDB(t))
+	}
+
+	return result
+}
+
 func MapInspectedDeviceToDB(device analytics.InspectedDevice) InspectedDevice {
 	return InspectedDevice{
 		ID:          int64(device.ID),
@@ -69,6 +92,43 @@ func MapInspectedDeviceSliceToDB(devices []analytics.InspectedDevice) []Inspecte
 	return result
 }
 
+func MapObjectDeviceToDB(device analytics.Device) ObjectDevice {
+	return ObjectDevice{
+		ID:               int64(device.ID),
+		Type:             device.Type,
+		Number:           device.Number,
+		PlaceType:        int8(device.PlaceType),
+		PlaceDescription: device.PlaceDescription,
+		Seals:            MapSealSliceToDB(device.Seals),
+	}
+}
+
+func MapObjectDeviceSliceToDB(devices []analytics.Device) []ObjectDevice {
+	result := make([]ObjectDevice, 0, len(devices))
+	for _, device := range devices {
+		result = append(result, MapObjectDeviceToDB(device))
+	}
+
+	return result
+}
+
+func MapSealToDB(seal analytics.Seal) Seal {
+	return Seal{
+		ID:     int64(seal.ID),
+		Number: seal.Number,
+		Place:  seal.Place,
+	}
+}
+
+func MapSealSliceToDB(seals []analytics.Seal) []Seal {
+	result := make([]Seal, 0, len(seals))
+	for _, seal := range seals {
+		result = append(result, MapSealToDB(seal))
+	}
+
+	return result
+}
+
 func MapInspectorToDB(i analytics.Inspector) Inspector {
 	return Inspector{
 		ID:          int64(i.ID),
@@ -125,6 +185,15 @@ func MapFinishedTaskFromDB(t FinishedTask) analytics.FinishedTask {
 			ID:            int(t.ObjectID),
 			Address:       t.ObjectAddress,
 			HaveAutomaton: t.ObjectHaveAutomaton,
+			Devices:       MapObjectDeviceSliceFromDB(t.ObjectDevices),
+			AddressParts: address.Address{
+				Region:   t.ObjectRegion,
+				City:     t.ObjectCity,
+				District: t.ObjectDistrict,
+				Street:   t.ObjectStreet,
+				House:    t.ObjectHouse,
+				Flat:     t.ObjectFlat,
+			},
 		},
 		Subscriber: analytics.Subscriber{
 			ID:            int(t.SubscriberID),
@@ -138,6 +207,11 @@ func MapFinishedTaskFromDB(t FinishedTask) analytics.FinishedTask {
 			BirthDate:     t.SubscriberBirthDate,
 			Status:        subscriber.Status(t.SubscriberStatus),
 		},
+		Contract: analytics.Contract{
+			Number:   t.ContractNumber,
+			SignDate: t.ContractSignDate,
+		},
+		Tenant: t.Tenant,
 	}
 }
 
@@ -169,6 +243,43 @@ func MapFinishedTaskSliceFromDB(tasks []FinishedTask) []analytics.FinishedTask {
 	return result
 }
 
+func MapObjectDeviceFromDB(device ObjectDevice) analytics.Device {
+	return analytics.Device{
+		ID:               int(device.ID),
+		Type:             device.Type,
+		Number:           device.Number,
+		PlaceType:        subscriber.DevicePlaceType(device.PlaceType),
+		PlaceDescription: device.PlaceDescription,
+		Seals:            MapSealSliceFromDB(device.Seals),
+	}
+}
+
+func MapObjectDeviceSliceFromDB(devices []ObjectDevice) []analytics.Device {
+	result := make([]analytics.Device, 0, len(devices))
+	for _, device := range devices {
+		result = append(result, MapObjectDeviceFromDB(device))
+	}
+
+	return result
+}
+
+func MapSealFromDB(seal Seal) analytics.Seal {
+	return analytics.Seal{
+		ID:     int(seal.ID),
+		Number: seal.Number,
+		Place:  seal.Place,
+	}
+}
+
+func MapSealSliceFromDB(seals []Seal) []analytics.Seal {
+	result := make([]analytics.Seal, 0, len(seals))
+	for _, seal := range seals {
+		result = append(result, MapSealFromDB(seal))
+	}
+
+	return result
+}
+
 func MapInspectorFromDB(i Inspector) analytics.Inspector {
 	return analytics.Inspector{
 		ID:          int(i.ID),
@@ -190,24 +301,68 @@ func MapInspectorSliceFromDB(inspectors []Inspector) []analytics.Inspector {
 	return result
 }
 
-func MapReportToDB(r analytics.Report) Report {
-	return Report{
-		ID:          r.ID,
-		Type:        int(r.Type),
-		PeriodStart: r.PeriodStart,
-		PeriodEnd:   r.PeriodEnd,
-		CreatedAt:   r.CreatedAt,
+func MapReportToDB(r analytics.Report) (Report, error) {
+	tenants, err := MapTenantsToDB(r.Tenants)
+	if err != nil {
+		return Report{}, err
 	}
+
+	return Report{
+		ID:            r.ID,
+		Type:          int(r.Type),
+		PeriodStart:   r.PeriodStart,
+		PeriodEnd:     r.PeriodEnd,
+		MaskingPolicy: string(r.MaskingPolicy),
+		Tenants:       tenants,
+		CreatedAt:     r.CreatedAt,
+	}, nil
 }
 
-func MapReportFromDB(r Report) analytics.Report {
+func MapReportFromDB(r Report) (analytics.Report, error) {
+	var tenants []string
+	if err := json.Unmarshal([]byte(r.Tenants), &tenants); err != nil {
+		return analytics.Report{}, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
 	return analytics.Report{
-		ID:          r.ID,
-		Type:        analytics.ReportType(r.Type),
-		PeriodStart: r.PeriodStart,
-		PeriodEnd:   r.PeriodEnd,
-		CreatedAt:   r.CreatedAt,
+		ID:            r.ID,
+		Type:          analytics.ReportType(r.Type),
+		PeriodStart:   r.PeriodStart,
+		PeriodEnd:     r.PeriodEnd,
+		MaskingPolicy: masking.Policy(r.MaskingPolicy),
+		Tenants:       tenants,
+		CreatedAt:     r.CreatedAt,
+	}, nil
+}
+
+// MapReportAccessToDB flattens the access into pairs of report types and masking policies.
+func MapReportAccessToDB(access analytics.ReportAccess) ([]int64, []string) {
+	var (
+		types    []int64
+		policies []string
+	)
+	for t, allowed := range access {
+		for _, p := range allowed {
+			types = append(types, int64(t))
+			policies = append(policies, string(p))
+		}
 	}
+
+	return types, policies
+}
+
+// MapTenantsToDB encodes tenants as a jsonb array, nil is encoded as an empty one.
+func MapTenantsToDB(tenants []string) (string, error) {
+	if tenants == nil {
+		tenants = []string{}
+	}
+
+	raw, err := json.Marshal(tenants)
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return string(raw), nil
 }
 
 func MapAttachmentToDB(f file.File, reportID int) Attachment {
@@ -240,3 +395,341 @@ func MapAttachmentSliceFromDB(attachments []Attachment) []file.File {
 
 	return result
 }
+
+func MapQuarantinedTaskToDB(t analytics.QuarantinedTask) (QuarantinedTask, error) {
+	missingFields := t.MissingFields
+	if missingFields == nil {
+		missingFields = []string{}
+	}
+
+	rawMissingFields, err := json.Marshal(missingFields)
+	if err != nil {
+		return QuarantinedTask{}, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return QuarantinedTask{
+		ID:            t.ID,
+		TaskID:        t.TaskID,
+		Payload:       string(t.Payload),
+		MissingFields: string(rawMissingFields),
+		Reason:        t.Reason,
+		CreatedAt:     t.CreatedAt,
+		RepairedAt:    t.RepairedAt,
+	}, nil
+}
+
+func MapQuarantinedTaskFromDB(t QuarantinedTask) (analytics.QuarantinedTask, error) {
+	var missingFields []string
+	if err := json.Unmarshal([]byte(t.MissingFields), &missingFields); err != nil {
+		return analytics.QuarantinedTask{}, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return analytics.QuarantinedTask{
+		ID:            t.ID,
+		TaskID:        t.TaskID,
+		Payload:       json.RawMessage(t.Payload),
+		MissingFields: missingFields,
+		Reason:        t.Reason,
+		CreatedAt:     t.CreatedAt,
+		RepairedAt:    t.RepairedAt,
+	}, nil
+}
+
+func MapQuarantinedTaskSliceFromDB(tasks []QuarantinedTask) ([]analytics.QuarantinedTask, error) {
+	result := make([]analytics.QuarantinedTask, 0, len(tasks))
+	for _, t := range tasks {
+		quarantinedTask, err := MapQuarantinedTaskFromDB(t)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, quarantinedTask)
+	}
+
+	return result, nil
+}
+
+func MapRawEventToDB(e analytics.RawEvent) RawEvent {
+	return RawEvent{
+		Topic:      e.Topic,
+		Partition:  int32(e.Partition),
+		Offset:     e.Offset,
+		Key:        string(e.Key),
+		Headers:    e.Headers,
+		Value:      string(e.Value),
+		ReceivedAt: e.ReceivedAt,
+	}
+}
+
+func MapRawEventSliceToDB(events []analytics.RawEvent) []RawEvent {
+	result := make([]RawEvent, 0, len(events))
+	for _, e := range events {
+		result = append(result, MapRawEventToDB(e))
+	}
+
+	return result
+}
+
+func MapRawEventFromDB(e RawEvent) analytics.RawEvent {
+	return analytics.RawEvent{
+		Topic:      e.Topic,
+		Partition:  int(e.Partition),
+		Offset:     e.Offset,
+		Key:        []byte(e.Key),
+		Headers:    e.Headers,
+		Value:      []byte(e.Value),
+		ReceivedAt: e.ReceivedAt,
+	}
+}
+
+func MapInspectorDayFromDB(d InspectorDay) analytics.InspectorDay {
+	return analytics.InspectorDay{
+		Day:                     d.Day,
+		InspectorID:             int(d.InspectorID),
+		InspectorFullName:       d.InspectorFullName,
+		BrigadeID:               int(d.BrigadeID),
+		TasksCount:              int(d.TasksCount),
+		ViolationsDetectedCount: int(d.ViolationsDetectedCount),
+		TotalDurationMinutes:    int(d.TotalDurationMinutes),
+	}
+}
+
+func MapInspectorDaySliceFromDB(days []InspectorDay) []analytics.InspectorDay {
+	result := make([]analytics.InspectorDay, 0, len(days))
+	for _, d := range days {
+		result = append(result, MapInspectorDayFromDB(d))
+	}
+
+	return result
+}
+
+func MapTasksDailyFromDB(d TasksDaily) analytics.TasksDaily {
+	return analytics.TasksDaily{
+		Day:                         d.Day,
+		TasksCount:                  int(d.TasksCount),
+		LimitationCount:             int(d.LimitationCount),
+		ResumptionCount:             int(d.ResumptionCount),
+		VerificationCount:           int(d.VerificationCount),
+		UnauthorizedConnectionCount: int(d.UnauthorizedConnectionCount),
+		ViolationsDetectedCount:     int(d.ViolationsDetectedCount),
+		UnauthorizedConsumersCount:  int(d.UnauthorizedConsumersCount),
+		AvgDurationMinutes:          d.AvgDurationMinutes,
+	}
+}
+
+func MapTasksDailySliceFromDB(days []TasksDaily) []analytics.TasksDaily {
+	result := make([]analytics.TasksDaily, 0, len(days))
+	for _, d := range days {
+		result = append(result, MapTasksDailyFromDB(d))
+	}
+
+	return result
+}
+
+func MapBrigadeDayFromDB(d BrigadeDay) analytics.BrigadeDay {
+	return analytics.BrigadeDay{
+		Day:                        d.Day,
+		BrigadeID:                  int(d.BrigadeID),
+		TasksCount:                 int(d.TasksCount),
+		AvgDurationMinutes:         d.AvgDurationMinutes,
+		SuccessfulLimitationsCount: int(d.SuccessfulLimitationsCount),
+		SuccessfulResumptionsCount: int(d.SuccessfulResumptionsCount),
+		ViolationsDetectedCount:    int(d.ViolationsDetectedCount),
+	}
+}
+
+func MapBrigadeDaySliceFromDB(days []BrigadeDay) []analytics.BrigadeDay {
+	result := make([]analytics.BrigadeDay, 0, len(days))
+	for _, d := range days {
+		result = append(result, MapBrigadeDayFromDB(d))
+	}
+
+	return result
+}
+
+func MapVisitDelayFromDB(d VisitDelay) analytics.VisitDelay {
+	inspectors := make([]analytics.VisitInspector, 0, len(d.InspectorIDs))
+	for i, id := range d.InspectorIDs {
+		var fullName string
+		if i < len(d.InspectorFullNames) {
+			fullName = d.InspectorFullNames[i]
+		}
+
+		inspectors = append(inspectors, analytics.VisitInspector{
+			ID:       int(id),
+			FullName: fullName,
+		})
+	}
+
+	return analytics.VisitDelay{
+		Day:          d.Day,
+		TaskID:       int(d.TaskID),
+		BrigadeID:    int(d.BrigadeID),
+		Inspectors:   inspectors,
+		DistrictName: d.DistrictName,
+		PlanVisitAt:  d.PlanVisitAt,
+		StartedAt:    d.StartedAt,
+		DelayMinutes: int(d.DelayMinutes),
+	}
+}
+
+func MapVisitDelaySliceFromDB(delays []VisitDelay) []analytics.VisitDelay {
+	result := make([]analytics.VisitDelay, 0, len(delays))
+	for _, d := range delays {
+		result = append(result, MapVisitDelayFromDB(d))
+	}
+
+	return result
+}
+
+func MapLimitationTurnaroundFromDB(t LimitationTurnaround) analytics.LimitationTurnaround {
+	var resumptionTaskID, disconnectedMinutes *int
+	if t.ResumptionTaskID != nil {
+		id := int(*t.ResumptionTaskID)
+		resumptionTaskID = &id
+	}
+	if t.DisconnectedMinutes != nil {
+		minutes := int(*t.DisconnectedMinutes)
+		disconnectedMinutes = &minutes
+	}
+
+	return analytics.LimitationTurnaround{
+		ObjectID:                int(t.ObjectID),
+		ObjectAddress:           t.ObjectAddress,
+		SubscriberAccountNumber: t.SubscriberAccountNumber,
+		LimitationTaskID:        int(t.LimitationTaskID),
+		LimitedAt:               t.LimitedAt,
+		ResumptionTaskID:        resumptionTaskID,
+		ResumedAt:               t.ResumedAt,
+		DisconnectedMinutes:     disconnectedMinutes,
+	}
+}
+
+func MapLimitationTurnaroundSliceFromDB(turnarounds []LimitationTurnaround) []analytics.LimitationTurnaround {
+	result := make([]analytics.LimitationTurnaround, 0, len(turnarounds))
+	for _, t := range turnarounds {
+		result = append(result, MapLimitationTurnaroundFromDB(t))
+	}
+
+	return result
+}
+
+func MapObjectRefusalsFromDB(r ObjectRefusals) analytics.ObjectRefusals {
+	return analytics.ObjectRefusals{
+		ObjectID:                int(r.ObjectID),
+		ObjectAddress:           r.ObjectAddress,
+		LimitationRefusalsCount: int(r.LimitationRefusalsCount),
+		ResumptionRefusalsCount: int(r.ResumptionRefusalsCount),
+	}
+}
+
+func MapObjectRefusalsSliceFromDB(refusals []ObjectRefusals) []analytics.ObjectRefusals {
+	result := make([]analytics.ObjectRefusals, 0, len(refusals))
+	for _, r := range refusals {
+		result = append(result, MapObjectRefusalsFromDB(r))
+	}
+
+	return result
+}
+
+func MapDeviceReadingFromDB(r DeviceReading) analytics.DeviceReading {
+	return analytics.DeviceReading{
+		DeviceID:                int(r.DeviceID),
+		InspectedDeviceID:       int(r.InspectedDeviceID),
+		ReadAt:                  r.ReadAt,
+		Value:                   r.Value,
+		ConsumptionKWh:          r.ConsumptionKWh,
+		DeviceType:              r.DeviceType,
+		DeviceNumber:            r.DeviceNumber,
+		SealNumbers:             r.SealNumbers,
+		TaskID:                  int(r.TaskID),
+		FinishedAt:              r.FinishedAt,
+		ObjectID:                int(r.ObjectID),
+		ObjectAddress:           r.ObjectAddress,
+		SubscriberID:            int(r.SubscriberID),
+		SubscriberAccountNumber: r.SubscriberAccountNumber,
+	}
+}
+
+func MapDeviceReadingSliceFromDB(readings []DeviceReading) []analytics.DeviceReading {
+	result := make([]analytics.DeviceReading, 0, len(readings))
+	for _, r := range readings {
+		result = append(result, MapDeviceReadingFromDB(r))
+	}
+
+	return result
+}
+
+func MapDeadLetterToDB(d analytics.DeadLetter) DeadLetter {
+	return DeadLetter{
+		ID:        d.ID,
+		Topic:     d.Topic,
+		Partition: d.Partition,
+		Offset:    d.Offset,
+		TaskID:    d.TaskID,
+		Payload:   d.Payload,
+		Reason:    d.Reason,
+		CreatedAt: d.CreatedAt,
+	}
+}
+
+func MapDeadLetterFromDB(d DeadLetter) analytics.DeadLetter {
+	return analytics.DeadLetter{
+		ID:        d.ID,
+		Topic:     d.Topic,
+		Partition: d.Partition,
+		Offset:    d.Offset,
+		TaskID:    d.TaskID,
+		Payload:   d.Payload,
+		Reason:    d.Reason,
+		CreatedAt: d.CreatedAt,
+	}
+}
+
+func MapDeadLetterSliceFromDB(deadLetters []DeadLetter) []analytics.DeadLetter {
+	result := make([]analytics.DeadLetter, 0, len(deadLetters))
+	for _, d := range deadLetters {
+		result = append(result, MapDeadLetterFromDB(d))
+	}
+
+	return result
+}
+
+func MapReplayJobToDB(j analytics.ReplayJob) ReplayJob {
+	return ReplayJob{
+		ID:          j.ID,
+		PeriodStart: j.PeriodStart,
+		PeriodEnd:   j.PeriodEnd,
+		Table:       j.Table,
+		Status:      string(j.Status),
+		Events:      j.Events,
+		Error:       j.Error,
+		RequestedBy: j.RequestedBy,
+		CreatedAt:   j.CreatedAt,
+		FinishedAt:  j.FinishedAt,
+	}
+}
+
+func MapReplayJobFromDB(j ReplayJob) analytics.ReplayJob {
+	return analytics.ReplayJob{
+		ID:          j.ID,
+		PeriodStart: j.PeriodStart,
+		PeriodEnd:   j.PeriodEnd,
+		Table:       j.Table,
+		Status:      analytics.ReplayStatus(j.Status),
+		Events:      j.Events,
+		Error:       j.Error,
+		RequestedBy: j.RequestedBy,
+		CreatedAt:   j.CreatedAt,
+		FinishedAt:  j.FinishedAt,
+	}
+}
+
+func MapReplayJobSliceFromDB(jobs []ReplayJob) []analytics.ReplayJob {
+	result := make([]analytics.ReplayJob, 0, len(jobs))
+	for _, j := range jobs {
+		result = append(result, MapReplayJobFromDB(j))
+	}
+
+	return result
+}
@@ -1,7 +1,9 @@
 package analytics
 
 import (
+	"analytics-service/cluster/subscriber"
 	service "analytics-service/service/analytics"
+	"reflect"
 	"testing"
 	"time"
 
@@ -47,3 +49,40 @@ func TestMapFinishedTaskToDBIncludesInspectionDeviceReadings(t *testing.T) {
 		t.Fatalf("unexpected created_at: %s", device.CreatedAt)
 	}
 }
+
+func TestMapFinishedTaskRoundTripKeepsObjectDevicesAndContract(t *testing.T) {
+	task := service.FinishedTask{
+		Object: service.Object{
+			ID: 3,
+			Devices: []service.Device{
+				{
+					ID:               77,
+					Type:             "Меркурий 201",
+					Number:           "0412345",
+					PlaceType:        subscriber.DevicePlaceStairLanding,
+					PlaceDescription: "щит на 2 этаже",
+					Seals:            []service.Seal{{ID: 9, Number: "А-100", Place: "клеммная крышка"}},
+				},
+			},
+		},
+		Contract: service.Contract{Number: "Д-15/2024", SignDate: "2024-03-01"},
+	}
+
+	dbTask := MapFinishedTaskToDB(task)
+
+	if len(dbTask.ObjectDevices) != 1 || len(dbTask.ObjectDevices[0].Seals) != 1 {
+		t.Fatalf("unexpected object devices: %+v", dbTask.ObjectDevices)
+	}
+	if dbTask.ContractNumber != "Д-15/2024" || dbTask.ContractSignDate != "2024-03-01" {
+		t.Fatalf("unexpected contract: %q %q", dbTask.ContractNumber, dbTask.ContractSignDate)
+	}
+
+	mapped := MapFinishedTaskFromDB(dbTask)
+
+	if !reflect.DeepEqual(mapped.Object.Devices, task.Object.Devices) {
+		t.Fatalf("expected devices %+v, got %+v", task.Object.Devices, mapped.Object.Devices)
+	}
+	if mapped.Contract != task.Contract {
+		t.Fatalf("expected contract %+v, got %+v", task.Contract, mapped.Contract)
+	}
+}
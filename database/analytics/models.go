@@ -7,11 +7,13 @@ import (
 )
 
 type Report struct {
-	ID          int       `db:"id"`
-	Type        int       `db:"type"`
-	PeriodStart time.Time `db:"period_start"`
-	PeriodEnd   time.Time `db:"period_end"`
-	CreatedAt   time.Time `db:"created_at"`
+	ID            int       `db:"id"`
+	Type          int       `db:"type"`
+	PeriodStart   time.Time `db:"period_start"`
+	PeriodEnd     time.Time `db:"period_end"`
+	MaskingPolicy string    `db:"masking_policy"`
+	Tenants       string    `db:"tenants"`
+	CreatedAt     time.Time `db:"created_at"`
 }
 
 type Attachment struct {
@@ -49,7 +51,16 @@ type FinishedTask struct {
 	BrigadeInspectors                 []Inspector       `ch:"brigade_inspectors"`
 	ObjectID                          int64             `ch:"object_id"`
 	ObjectAddress                     string            `ch:"object_address"`
+	ObjectRegion                      string            `ch:"object_region"`
+	ObjectCity                        string            `ch:"object_city"`
+	ObjectDistrict                    string            `ch:"object_district"`
+	ObjectStreet                      string            `ch:"object_street"`
+	ObjectHouse                       string            `ch:"object_house"`
+	ObjectFlat                        string            `ch:"object_flat"`
 	ObjectHaveAutomaton               bool              `ch:"object_have_automaton"`
+	ObjectDevices                     []ObjectDevice    `ch:"object_devices"`
+	ContractNumber                    string            `ch:"contract_number"`
+	ContractSignDate                  string            `ch:"contract_sign_date"`
 	SubscriberID                      int64             `ch:"subscriber_id"`
 	SubscriberAccountNumber           string            `ch:"subscriber_account_number"`
 	SubscriberSurname                 string            `ch:"subscriber_surname"`
@@ -60,6 +71,7 @@ type FinishedTask struct {
 	SubscriberINN                     string            `ch:"subscriber_inn"`
 	SubscriberBirthDate               time.Time         `ch:"subscriber_birth_date"`
 	SubscriberStatus                  int8              `ch:"subscriber_status"`
+	Tenant                            string            `ch:"tenant"`
 }
 
 type InspectedDevice struct {
@@ -70,6 +82,21 @@ type InspectedDevice struct {
 	CreatedAt   time.Time       `ch:"created_at"`
 }
 
+type ObjectDevice struct {
+	ID               int64  `ch:"id"`
+	Type             string `ch:"type"`
+	Number           string `ch:"number"`
+	PlaceType        int8   `ch:"place_type"`
+	PlaceDescription string `ch:"place_description"`
+	Seals            []Seal `ch:"seals"`
+}
+
+type Seal struct {
+	ID     int64  `ch:"id"`
+	Number string `ch:"number"`
+	Place  string `ch:"place"`
+}
+
 type Inspector struct {
 	ID          int64     `ch:"id"`
 	Surname     string    `ch:"surname"`
@@ -79,3 +106,126 @@ type Inspector struct {
 	Email       string    `ch:"email"`
 	AssignedAt  time.Time `ch:"assigned_at"`
 }
+
+type QuarantinedTask struct {
+	ID            int        `db:"id"`
+	TaskID        int        `db:"task_id"`
+	Payload       string     `db:"payload"`
+	MissingFields string     `db:"missing_fields"`
+	Reason        string     `db:"reason"`
+	CreatedAt     time.Time  `db:"created_at"`
+	RepairedAt    *time.Time `db:"repaired_at"`
+}
+
+type DeadLetter struct {
+	ID        int       `db:"id"`
+	Topic     string    `db:"topic"`
+	Partition int       `db:"partition"`
+	Offset    int64     `db:"offset"`
+	TaskID    int       `db:"task_id"`
+	Payload   []byte    `db:"payload"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type ReplayJob struct {
+	ID          int        `db:"id"`
+	PeriodStart time.Time  `db:"period_start"`
+	PeriodEnd   time.Time  `db:"period_end"`
+	Table       string     `db:"table_name"`
+	Status      string     `db:"status"`
+	Events      int        `db:"events"`
+	Error       string     `db:"error"`
+	RequestedBy int        `db:"requested_by"`
+	CreatedAt   time.Time  `db:"created_at"`
+	FinishedAt  *time.Time `db:"finished_at"`
+}
+
+type RawEvent struct {
+	Topic      string            `ch:"topic"`
+	Partition  int32             `ch:"partition"`
+	Offset     int64             `ch:"offset"`
+	Key        string            `ch:"key"`
+	Headers    map[string]string `ch:"headers"`
+	Value      string            `ch:"value"`
+	ReceivedAt time.Time         `ch:"received_at"`
+}
+
+type InspectorDay struct {
+	Day                     time.Time `ch:"day"`
+	InspectorID             int64     `ch:"inspector_id"`
+	InspectorFullName       string    `ch:"inspector_full_name"`
+	BrigadeID               int64     `ch:"brigade_id"`
+	TasksCount              uint64    `ch:"tasks_count"`
+	ViolationsDetectedCount uint64    `ch:"violations_detected_count"`
+	TotalDurationMinutes    int64     `ch:"total_duration_minutes"`
+}
+
+type TasksDaily struct {
+	Day                         time.Time `ch:"day"`
+	TasksCount                  uint64    `ch:"tasks_count"`
+	LimitationCount             uint64    `ch:"limitation_count"`
+	ResumptionCount             uint64    `ch:"resumption_count"`
+	VerificationCount           uint64    `ch:"verification_count"`
+	UnauthorizedConnectionCount uint64    `ch:"unauthorized_connection_count"`
+	ViolationsDetectedCount     uint64    `ch:"violations_detected_count"`
+	UnauthorizedConsumersCount  uint64    `ch:"unauthorized_consumers_count"`
+	AvgDurationMinutes          float64   `ch:"avg_duration_minutes"`
+}
+
+type BrigadeDay struct {
+	Day                        time.Time `ch:"day"`
+	BrigadeID                  int64     `ch:"brigade_id"`
+	TasksCount                 uint64    `ch:"tasks_count"`
+	AvgDurationMinutes         float64   `ch:"avg_duration_minutes"`
+	SuccessfulLimitationsCount uint64    `ch:"successful_limitations_count"`
+	SuccessfulResumptionsCount uint64    `ch:"successful_resumptions_count"`
+	ViolationsDetectedCount    uint64    `ch:"violations_detected_count"`
+}
+
+type VisitDelay struct {
+	Day                time.Time `ch:"day"`
+	TaskID             int64     `ch:"task_id"`
+	BrigadeID          int64     `ch:"brigade_id"`
+	InspectorIDs       []int64   `ch:"inspector_ids"`
+	InspectorFullNames []string  `ch:"inspector_full_names"`
+	DistrictName       string    `ch:"district_name"`
+	PlanVisitAt        time.Time `ch:"plan_visit_at"`
+	StartedAt          time.Time `ch:"started_at"`
+	DelayMinutes       int64     `ch:"delay_minutes"`
+}
+
+type LimitationTurnaround struct {
+	ObjectID                int64      `ch:"object_id"`
+	ObjectAddress           string     `ch:"object_address"`
+	SubscriberAccountNumber string     `ch:"subscriber_account_number"`
+	LimitationTaskID        int64      `ch:"limitation_task_id"`
+	LimitedAt               time.Time  `ch:"limited_at"`
+	ResumptionTaskID        *int64     `ch:"resumption_task_id"`
+	ResumedAt               *time.Time `ch:"resumed_at"`
+	DisconnectedMinutes     *int64     `ch:"disconnected_minutes"`
+}
+
+type ObjectRefusals struct {
+	ObjectID                int64  `ch:"object_id"`
+	ObjectAddress           string `ch:"object_address"`
+	LimitationRefusalsCount uint64 `ch:"limitation_refusals_count"`
+	ResumptionRefusalsCount uint64 `ch:"resumption_refusals_count"`
+}
+
+type DeviceReading struct {
+	DeviceID                int64           `ch:"device_id"`
+	InspectedDeviceID       int64           `ch:"inspected_device_id"`
+	ReadAt                  time.Time       `ch:"read_at"`
+	Value                   decimal.Decimal `ch:"value"`
+	ConsumptionKWh          decimal.Decimal `ch:"consumption_kwh"`
+	DeviceType              string          `ch:"device_type"`
+	DeviceNumber            string          `ch:"device_number"`
+	SealNumbers             []string        `ch:"seal_numbers"`
+	TaskID                  int64           `ch:"task_id"`
+	FinishedAt              time.Time       `ch:"finished_at"`
+	ObjectID                int64           `ch:"object_id"`
+	ObjectAddress           string          `ch:"object_address"`
+	SubscriberID            int64           `ch:"subscriber_id"`
+	SubscriberAccountNumber string          `ch:"subscriber_account_number"`
+}
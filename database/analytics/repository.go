@@ -2,19 +2,25 @@ package analytics
 
 import (
 	"analytics-service/service/analytics"
+	"analytics-service/service/auth"
 	"context"
 	"database/sql"
 	_ "embed"
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/jmoiron/sqlx"
 	"github.com/sunshineOfficial/golib/db"
 	"github.com/sunshineOfficial/golib/pagination"
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("analytics-service/database/analytics")
+
 var (
 	//go:embed sql/add_attachment.sql
 	addAttachmentSQL string
@@ -22,19 +28,94 @@ var (
 	//go:embed sql/add_finished_task.sql
 	addFinishedTaskSQL string
 
+	//go:embed sql/add_quarantined_task.sql
+	addQuarantinedTaskSQL string
+
+	//go:embed sql/add_dead_letter.sql
+	addDeadLetterSQL string
+
+	//go:embed sql/add_raw_events.sql
+	addRawEventsSQL string
+
+	//go:embed sql/add_replay_job.sql
+	addReplayJobSQL string
+
 	//go:embed sql/add_report.sql
 	addReportSQL string
 
+	//go:embed sql/create_replay_table.sql
+	createReplayTableSQL string
+
+	//go:embed sql/drop_replay_table.sql
+	dropReplayTableSQL string
+
 	//go:embed sql/get_all_reports.sql
 	getAllReportsSQL string
 
 	//go:embed sql/get_attachments_by_reports.sql
 	getAttachmentsByReportSQL string
 
+	//go:embed sql/get_report_file.sql
+	getReportFileSQL string
+
+	//go:embed sql/get_brigade_days_by_period.sql
+	getBrigadeDaysByPeriodSQL string
+
+	//go:embed sql/get_device_readings.sql
+	getDeviceReadingsSQL string
+
+	//go:embed sql/get_existing_task_ids.sql
+	getExistingTaskIDsSQL string
+
+	//go:embed sql/get_expired_replay_jobs.sql
+	getExpiredReplayJobsSQL string
+
 	//go:embed sql/get_finished_tasks_by_period.sql
 	getFinishedTasksByPeriodSQL string
+
+	//go:embed sql/get_dead_letters.sql
+	getDeadLettersSQL string
+
+	//go:embed sql/get_raw_events_by_period.sql
+	getRawEventsByPeriodSQL string
+
+	//go:embed sql/get_inspector_days_by_period.sql
+	getInspectorDaysByPeriodSQL string
+
+	//go:embed sql/get_limitation_turnarounds_by_period.sql
+	getLimitationTurnaroundsByPeriodSQL string
+
+	//go:embed sql/get_open_limitations.sql
+	getOpenLimitationsSQL string
+
+	//go:embed sql/get_object_refusals_by_period.sql
+	getObjectRefusalsByPeriodSQL string
+
+	//go:embed sql/get_quarantined_tasks.sql
+	getQuarantinedTasksSQL string
+
+	//go:embed sql/get_replay_jobs.sql
+	getReplayJobsSQL string
+
+	//go:embed sql/get_tasks_daily_by_period.sql
+	getTasksDailyByPeriodSQL string
+
+	//go:embed sql/get_visit_delays_by_period.sql
+	getVisitDelaysByPeriodSQL string
+
+	//go:embed sql/is_subscriber_erased.sql
+	isSubscriberErasedSQL string
+
+	//go:embed sql/update_quarantined_task.sql
+	updateQuarantinedTaskSQL string
+
+	//go:embed sql/update_replay_job.sql
+	updateReplayJobSQL string
 )
 
+// replayTableName protects queries that take the replay table name as is.
+var replayTableName = regexp.MustCompile(`^finished_tasks_replay_[0-9]+$`)
+
 type Repository struct {
 	postgres   *sqlx.DB
 	clickhouse driver.Conn
@@ -47,10 +128,27 @@ func NewRepository(postgres *sqlx.DB, clickhouse driver.Conn) *Repository {
 	}
 }
 
-func (r *Repository) AddFinishedTask(ctx context.Context, t analytics.FinishedTask) error {
-	dbTask := MapFinishedTaskToDB(t)
+func (r *Repository) AddFinishedTasks(ctx context.Context, tasks []analytics.FinishedTask) error {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.AddFinishedTasks")
+	defer span.End()
+
+	return r.addFinishedTasks(ctx, strings.ReplaceAll(addFinishedTaskSQL, "{table}", "finished_tasks"), tasks)
+}
+
+// AddFinishedTasksTo inserts finished tasks into a replay table created by CreateReplayTable.
+func (r *Repository) AddFinishedTasksTo(ctx context.Context, table string, tasks []analytics.FinishedTask) error {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.AddFinishedTasksTo")
+	defer span.End()
+
+	if !replayTableName.MatchString(table) {
+		return fmt.Errorf("invalid replay table name: %q", table)
+	}
+
+	return r.addFinishedTasks(ctx, strings.ReplaceAll(addFinishedTaskSQL, "{table}", table), tasks)
+}
 
-	batch, err := r.clickhouse.PrepareBatch(ctx, addFinishedTaskSQL)
+func (r *Repository) addFinishedTasks(ctx context.Context, query string, tasks []analytics.FinishedTask) error {
+	batch, err := r.clickhouse.PrepareBatch(ctx, query)
 	if err != nil {
 		return fmt.Errorf("r.clickhouse.PrepareBatch: %w", err)
 	}
@@ -58,10 +156,12 @@ func (r *Repository) AddFinishedTask(ctx context.Context, t analytics.FinishedTa
 		err = errors.Join(err, batch.Close())
 	}()
 
-	err = batch.AppendStruct(&dbTask)
-	if err != nil {
-		err = fmt.Errorf("batch.AppendStruct: %w", err)
-		return err
+	for _, dbTask := range MapFinishedTaskSliceToDB(tasks) {
+		err = batch.AppendStruct(&dbTask)
+		if err != nil {
+			err = fmt.Errorf("batch.AppendStruct: %w", err)
+			return err
+		}
 	}
 
 	err = batch.Send()
@@ -73,9 +173,35 @@ func (r *Repository) AddFinishedTask(ctx context.Context, t analytics.FinishedTa
 	return err
 }
 
-func (r *Repository) GetFinishedTasksByPeriod(ctx context.Context, periodStart, periodEnd time.Time) ([]analytics.FinishedTask, error) {
+// GetExistingTaskIDs returns the IDs that are already stored in finished_tasks.
+func (r *Repository) GetExistingTaskIDs(ctx context.Context, ids []int) ([]int, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetExistingTaskIDs")
+	defer span.End()
+
+	taskIDs := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		taskIDs = append(taskIDs, int64(id))
+	}
+
+	var existing []int64
+	if err := r.clickhouse.Select(ctx, &existing, getExistingTaskIDsSQL, taskIDs); err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	result := make([]int, 0, len(existing))
+	for _, id := range existing {
+		result = append(result, int(id))
+	}
+
+	return result, nil
+}
+
+func (r *Repository) GetFinishedTasksByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]analytics.FinishedTask, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetFinishedTasksByPeriod")
+	defer span.End()
+
 	var tasks []FinishedTask
-	err := r.clickhouse.Select(ctx, &tasks, getFinishedTasksByPeriodSQL, periodStart, periodEnd)
+	err := r.clickhouse.Select(ctx, &tasks, getFinishedTasksByPeriodSQL, periodStart, periodEnd, scope.All, scope.Tenants)
 	if err != nil {
 		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
 	}
@@ -83,7 +209,113 @@ func (r *Repository) GetFinishedTasksByPeriod(ctx context.Context, periodStart,
 	return MapFinishedTaskSliceFromDB(tasks), nil
 }
 
+func (r *Repository) GetInspectorDaysByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]analytics.InspectorDay, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetInspectorDaysByPeriod")
+	defer span.End()
+
+	var days []InspectorDay
+	err := r.clickhouse.Select(ctx, &days, getInspectorDaysByPeriodSQL, periodStart, periodEnd, scope.All, scope.Tenants)
+	if err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	return MapInspectorDaySliceFromDB(days), nil
+}
+
+func (r *Repository) GetTasksDailyByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]analytics.TasksDaily, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetTasksDailyByPeriod")
+	defer span.End()
+
+	var days []TasksDaily
+	err := r.clickhouse.Select(ctx, &days, getTasksDailyByPeriodSQL, periodStart, periodEnd, scope.All, scope.Tenants)
+	if err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	return MapTasksDailySliceFromDB(days), nil
+}
+
+func (r *Repository) GetBrigadeDaysByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]analytics.BrigadeDay, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetBrigadeDaysByPeriod")
+	defer span.End()
+
+	var days []BrigadeDay
+	err := r.clickhouse.Select(ctx, &days, getBrigadeDaysByPeriodSQL, periodStart, periodEnd, scope.All, scope.Tenants)
+	if err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	return MapBrigadeDaySliceFromDB(days), nil
+}
+
+func (r *Repository) GetVisitDelaysByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]analytics.VisitDelay, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetVisitDelaysByPeriod")
+	defer span.End()
+
+	var delays []VisitDelay
+	err := r.clickhouse.Select(ctx, &delays, getVisitDelaysByPeriodSQL, periodStart, periodEnd, scope.All, scope.Tenants)
+	if err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	return MapVisitDelaySliceFromDB(delays), nil
+}
+
+func (r *Repository) GetLimitationTurnaroundsByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]analytics.LimitationTurnaround, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetLimitationTurnaroundsByPeriod")
+	defer span.End()
+
+	var turnarounds []LimitationTurnaround
+	err := r.clickhouse.Select(ctx, &turnarounds, getLimitationTurnaroundsByPeriodSQL, periodStart, periodEnd, scope.All, scope.Tenants)
+	if err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	return MapLimitationTurnaroundSliceFromDB(turnarounds), nil
+}
+
+func (r *Repository) GetOpenLimitations(ctx context.Context, scope auth.TenantScope, limitedBefore time.Time) ([]analytics.LimitationTurnaround, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetOpenLimitations")
+	defer span.End()
+
+	var turnarounds []LimitationTurnaround
+	err := r.clickhouse.Select(ctx, &turnarounds, getOpenLimitationsSQL, limitedBefore, scope.All, scope.Tenants)
+	if err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	return MapLimitationTurnaroundSliceFromDB(turnarounds), nil
+}
+
+func (r *Repository) GetObjectRefusalsByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]analytics.ObjectRefusals, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetObjectRefusalsByPeriod")
+	defer span.End()
+
+	var refusals []ObjectRefusals
+	err := r.clickhouse.Select(ctx, &refusals, getObjectRefusalsByPeriodSQL, periodStart, periodEnd, scope.All, scope.Tenants)
+	if err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	return MapObjectRefusalsSliceFromDB(refusals), nil
+}
+
+func (r *Repository) GetDeviceReadings(ctx context.Context, scope auth.TenantScope, deviceID int) ([]analytics.DeviceReading, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetDeviceReadings")
+	defer span.End()
+
+	var readings []DeviceReading
+	if err := r.clickhouse.Select(ctx, &readings, getDeviceReadingsSQL, deviceID, scope.All, scope.Tenants); err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	return MapDeviceReadingSliceFromDB(readings), nil
+}
+
 func (r *Repository) AddReport(ctx context.Context, report analytics.Report) (analytics.Report, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.AddReport")
+	defer span.End()
+
 	tx, err := r.postgres.BeginTxx(ctx, nil)
 	if err != nil {
 		return analytics.Report{}, fmt.Errorf("r.postgres.BeginTxx: %w", err)
@@ -94,13 +326,22 @@ func (r *Repository) AddReport(ctx context.Context, report analytics.Report) (an
 		}
 	}()
 
-	var dbReport Report
-	if err = db.NamedGet(tx, &dbReport, addReportSQL, MapReportToDB(report)); err != nil {
+	dbReport, err := MapReportToDB(report)
+	if err != nil {
+		err = fmt.Errorf("MapReportToDB: %w", err)
+		return analytics.Report{}, err
+	}
+
+	if err = db.NamedGet(tx, &dbReport, addReportSQL, dbReport); err != nil {
 		err = fmt.Errorf("db.NamedGet: %w", err)
 		return analytics.Report{}, err
 	}
 
-	newReport := MapReportFromDB(dbReport)
+	newReport, err := MapReportFromDB(dbReport)
+	if err != nil {
+		err = fmt.Errorf("MapReportFromDB: %w", err)
+		return analytics.Report{}, err
+	}
 	newReport.Files = report.Files
 
 	_, err = tx.NamedExecContext(ctx, addAttachmentSQL, MapAttachmentSliceToDB(report.Files, newReport.ID))
@@ -117,7 +358,37 @@ func (r *Repository) AddReport(ctx context.Context, report analytics.Report) (an
 	return newReport, err
 }
 
-func (r *Repository) GetAllReports(ctx context.Context, page pagination.Pagination) ([]analytics.Report, error) {
+// HasReportFile reports whether the file is attached to a report of the scope tenants the access allows.
+func (r *Repository) HasReportFile(ctx context.Context, scope auth.TenantScope, access analytics.ReportAccess, reportID, fileID int) (bool, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.HasReportFile")
+	defer span.End()
+
+	tenants, err := MapTenantsToDB(scope.Tenants)
+	if err != nil {
+		return false, fmt.Errorf("MapTenantsToDB: %w", err)
+	}
+
+	types, policies := MapReportAccessToDB(access)
+
+	var exists bool
+	if err = r.postgres.GetContext(ctx, &exists, getReportFileSQL, reportID, fileID, scope.All, tenants, types, policies); err != nil {
+		return false, fmt.Errorf("r.postgres.GetContext: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *Repository) GetAllReports(ctx context.Context, scope auth.TenantScope, access analytics.ReportAccess, page pagination.Pagination) ([]analytics.Report, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetAllReports")
+	defer span.End()
+
+	tenants, err := MapTenantsToDB(scope.Tenants)
+	if err != nil {
+		return nil, fmt.Errorf("MapTenantsToDB: %w", err)
+	}
+
+	types, policies := MapReportAccessToDB(access)
+
 	tx, err := r.postgres.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
 		return nil, fmt.Errorf("r.postgres.BeginTxx: %w", err)
@@ -129,7 +400,7 @@ func (r *Repository) GetAllReports(ctx context.Context, page pagination.Paginati
 	}()
 
 	var dbReports []Report
-	if err = tx.SelectContext(ctx, &dbReports, getAllReportsSQL, page.LimitArg(), page.Offset); err != nil {
+	if err = tx.SelectContext(ctx, &dbReports, getAllReportsSQL, page.LimitArg(), page.Offset, scope.All, tenants, types, policies); err != nil {
 		err = fmt.Errorf("tx.SelectContext: %w", err)
 		return nil, err
 	}
@@ -165,7 +436,12 @@ func (r *Repository) GetAllReports(ctx context.Context, page pagination.Paginati
 			return nil, err
 		}
 
-		report := MapReportFromDB(dbReport)
+		var report analytics.Report
+		report, err = MapReportFromDB(dbReport)
+		if err != nil {
+			err = fmt.Errorf("MapReportFromDB: %w", err)
+			return nil, err
+		}
 		report.Files = MapAttachmentSliceFromDB(attachments)
 
 		reports = append(reports, report)
@@ -178,3 +454,231 @@ func (r *Repository) GetAllReports(ctx context.Context, page pagination.Paginati
 
 	return reports, err
 }
+
+func (r *Repository) AddQuarantinedTask(ctx context.Context, t analytics.QuarantinedTask) error {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.AddQuarantinedTask")
+	defer span.End()
+
+	dbTask, err := MapQuarantinedTaskToDB(t)
+	if err != nil {
+		return fmt.Errorf("MapQuarantinedTaskToDB: %w", err)
+	}
+
+	if _, err = r.postgres.NamedExecContext(ctx, addQuarantinedTaskSQL, dbTask); err != nil {
+		return fmt.Errorf("r.postgres.NamedExecContext: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) AddDeadLetter(ctx context.Context, d analytics.DeadLetter) error {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.AddDeadLetter")
+	defer span.End()
+
+	if _, err := r.postgres.NamedExecContext(ctx, addDeadLetterSQL, MapDeadLetterToDB(d)); err != nil {
+		return fmt.Errorf("r.postgres.NamedExecContext: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetDeadLetters(ctx context.Context, page pagination.Pagination) ([]analytics.DeadLetter, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetDeadLetters")
+	defer span.End()
+
+	var dbDeadLetters []DeadLetter
+	if err := r.postgres.SelectContext(ctx, &dbDeadLetters, getDeadLettersSQL, page.LimitArg(), page.Offset); err != nil {
+		return nil, fmt.Errorf("r.postgres.SelectContext: %w", err)
+	}
+
+	return MapDeadLetterSliceFromDB(dbDeadLetters), nil
+}
+
+// IsSubscriberErased reports whether the subscriber's data was requested to be erased in the tenant. Pending requests
+// count too, their anonymization may still be running.
+func (r *Repository) IsSubscriberErased(ctx context.Context, subscriberID int, tenant string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.IsSubscriberErased")
+	defer span.End()
+
+	var erased bool
+	if err := r.postgres.GetContext(ctx, &erased, isSubscriberErasedSQL, subscriberID, tenant); err != nil {
+		return false, fmt.Errorf("r.postgres.GetContext: %w", err)
+	}
+
+	return erased, nil
+}
+
+// GetQuarantinedTasks returns tasks that are not repaired yet, id = 0 means any task.
+func (r *Repository) GetQuarantinedTasks(ctx context.Context, id int, page pagination.Pagination) ([]analytics.QuarantinedTask, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetQuarantinedTasks")
+	defer span.End()
+
+	var dbTasks []QuarantinedTask
+	if err := r.postgres.SelectContext(ctx, &dbTasks, getQuarantinedTasksSQL, id, page.LimitArg(), page.Offset); err != nil {
+		return nil, fmt.Errorf("r.postgres.SelectContext: %w", err)
+	}
+
+	tasks, err := MapQuarantinedTaskSliceFromDB(dbTasks)
+	if err != nil {
+		return nil, fmt.Errorf("MapQuarantinedTaskSliceFromDB: %w", err)
+	}
+
+	return tasks, nil
+}
+
+func (r *Repository) UpdateQuarantinedTask(ctx context.Context, t analytics.QuarantinedTask) error {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.UpdateQuarantinedTask")
+	defer span.End()
+
+	dbTask, err := MapQuarantinedTaskToDB(t)
+	if err != nil {
+		return fmt.Errorf("MapQuarantinedTaskToDB: %w", err)
+	}
+
+	if _, err = r.postgres.NamedExecContext(ctx, updateQuarantinedTaskSQL, dbTask); err != nil {
+		return fmt.Errorf("r.postgres.NamedExecContext: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) AddReplayJob(ctx context.Context, j analytics.ReplayJob) (analytics.ReplayJob, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.AddReplayJob")
+	defer span.End()
+
+	dbJob := MapReplayJobToDB(j)
+	if err := db.NamedGet(r.postgres, &dbJob, addReplayJobSQL, dbJob); err != nil {
+		return analytics.ReplayJob{}, fmt.Errorf("db.NamedGet: %w", err)
+	}
+
+	return MapReplayJobFromDB(dbJob), nil
+}
+
+func (r *Repository) UpdateReplayJob(ctx context.Context, j analytics.ReplayJob) error {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.UpdateReplayJob")
+	defer span.End()
+
+	if _, err := r.postgres.NamedExecContext(ctx, updateReplayJobSQL, MapReplayJobToDB(j)); err != nil {
+		return fmt.Errorf("r.postgres.NamedExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// GetReplayJobs returns the latest replay jobs first, id = 0 means any job.
+func (r *Repository) GetReplayJobs(ctx context.Context, id int, page pagination.Pagination) ([]analytics.ReplayJob, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetReplayJobs")
+	defer span.End()
+
+	var dbJobs []ReplayJob
+	if err := r.postgres.SelectContext(ctx, &dbJobs, getReplayJobsSQL, id, page.LimitArg(), page.Offset); err != nil {
+		return nil, fmt.Errorf("r.postgres.SelectContext: %w", err)
+	}
+
+	return MapReplayJobSliceFromDB(dbJobs), nil
+}
+
+// GetExpiredReplayJobs returns finished replay jobs with a table, which finished before finishedBefore.
+func (r *Repository) GetExpiredReplayJobs(ctx context.Context, finishedBefore time.Time) ([]analytics.ReplayJob, error) {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.GetExpiredReplayJobs")
+	defer span.End()
+
+	var dbJobs []ReplayJob
+	if err := r.postgres.SelectContext(ctx, &dbJobs, getExpiredReplayJobsSQL, finishedBefore); err != nil {
+		return nil, fmt.Errorf("r.postgres.SelectContext: %w", err)
+	}
+
+	return MapReplayJobSliceFromDB(dbJobs), nil
+}
+
+func (r *Repository) AddRawEvents(ctx context.Context, events []analytics.RawEvent) error {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.AddRawEvents")
+	defer span.End()
+
+	batch, err := r.clickhouse.PrepareBatch(ctx, addRawEventsSQL)
+	if err != nil {
+		return fmt.Errorf("r.clickhouse.PrepareBatch: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, batch.Close())
+	}()
+
+	for _, dbEvent := range MapRawEventSliceToDB(events) {
+		err = batch.AppendStruct(&dbEvent)
+		if err != nil {
+			err = fmt.Errorf("batch.AppendStruct: %w", err)
+			return err
+		}
+	}
+
+	err = batch.Send()
+	if err != nil {
+		err = fmt.Errorf("batch.Send: %w", err)
+		return err
+	}
+
+	return err
+}
+
+// ForEachRawEvent streams archived events of the period in the order they were received.
+func (r *Repository) ForEachRawEvent(ctx context.Context, from, to time.Time, fn func(e analytics.RawEvent) error) error {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.ForEachRawEvent")
+	defer span.End()
+
+	rows, err := r.clickhouse.Query(ctx, getRawEventsByPeriodSQL, from, to)
+	if err != nil {
+		return fmt.Errorf("r.clickhouse.Query: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	for rows.Next() {
+		var dbEvent RawEvent
+		if err = rows.ScanStruct(&dbEvent); err != nil {
+			err = fmt.Errorf("rows.ScanStruct: %w", err)
+			return err
+		}
+
+		if err = fn(MapRawEventFromDB(dbEvent)); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		err = fmt.Errorf("rows.Err: %w", err)
+		return err
+	}
+
+	return err
+}
+
+func (r *Repository) CreateReplayTable(ctx context.Context, table string) error {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.CreateReplayTable")
+	defer span.End()
+
+	if !replayTableName.MatchString(table) {
+		return fmt.Errorf("invalid replay table name: %q", table)
+	}
+
+	if err := r.clickhouse.Exec(ctx, strings.ReplaceAll(createReplayTableSQL, "{table}", table)); err != nil {
+		return fmt.Errorf("r.clickhouse.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DropReplayTable(ctx context.Context, table string) error {
+	ctx, span := tracer.Start(ctx, "analytics.Repository.DropReplayTable")
+	defer span.End()
+
+	if !replayTableName.MatchString(table) {
+		return fmt.Errorf("invalid replay table name: %q", table)
+	}
+
+	if err := r.clickhouse.Exec(ctx, strings.ReplaceAll(dropReplayTableSQL, "{table}", table)); err != nil {
+		return fmt.Errorf("r.clickhouse.Exec: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,2 @@
+insert into dead_letters (topic, partition, "offset", task_id, payload, reason)
+values (:topic, :partition, :offset, :task_id, :payload, :reason);
@@ -1,4 +1,4 @@
-insert into finished_tasks
+insert into {table}
 (
     task_id,
     comment,
@@ -27,7 +27,16 @@ insert into finished_tasks
     brigade_inspectors,
     object_id,
     object_address,
+    object_region,
+    object_city,
+    object_district,
+    object_street,
+    object_house,
+    object_flat,
     object_have_automaton,
+    object_devices,
+    contract_number,
+    contract_sign_date,
     subscriber_id,
     subscriber_account_number,
     subscriber_surname,
@@ -37,5 +46,6 @@ insert into finished_tasks
     subscriber_email,
     subscriber_inn,
     subscriber_birth_date,
-    subscriber_status
+    subscriber_status,
+    tenant
 )
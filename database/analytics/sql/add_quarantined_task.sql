@@ -0,0 +1,2 @@
+insert into quarantined_tasks (task_id, payload, missing_fields, reason)
+values (:task_id, cast(:payload as jsonb), cast(:missing_fields as jsonb), :reason);
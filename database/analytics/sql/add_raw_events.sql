@@ -0,0 +1,10 @@
+insert into raw_events
+(
+    topic,
+    partition,
+    offset,
+    key,
+    headers,
+    value,
+    received_at
+)
@@ -0,0 +1,3 @@
+insert into replay_jobs (period_start, period_end, table_name, status, requested_by)
+values (:period_start, :period_end, :table_name, :status, :requested_by)
+returning id, period_start, period_end, table_name, status, events, error, requested_by, created_at, finished_at;
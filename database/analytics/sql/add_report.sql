@@ -1,3 +1,3 @@
-insert into reports (type, period_start, period_end)
-values (:type, :period_start, :period_end)
-returning id, type, period_start, period_end, created_at;
+insert into reports (type, period_start, period_end, masking_policy, tenants)
+values (:type, :period_start, :period_end, :masking_policy, cast(:tenants as jsonb))
+returning id, type, period_start, period_end, masking_policy, tenants::text as tenants, created_at;
@@ -0,0 +1 @@
+create table if not exists {table} as finished_tasks;
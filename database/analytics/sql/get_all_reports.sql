@@ -1,4 +1,6 @@
-select id, type, period_start, period_end, created_at
+select id, type, period_start, period_end, masking_policy, tenants::text as tenants, created_at
 from reports
+where ($3 or (tenants <> '[]' and tenants <@ cast($4 as jsonb)))
+  and (type, masking_policy) in (select * from unnest(cast($5 as int[]), cast($6 as text[])))
 order by id
 limit $1 offset $2;
@@ -0,0 +1,13 @@
+select day,
+       brigade_id,
+       sum(tasks_count)                                                   as tasks_count,
+       round(sum(avg_duration_minutes * tasks_count) / sum(tasks_count), 2) as avg_duration_minutes,
+       sum(successful_limitations_count)                                  as successful_limitations_count,
+       sum(successful_resumptions_count)                                  as successful_resumptions_count,
+       sum(violations_detected_count)                                     as violations_detected_count
+from v_bi_brigade_performance
+where toDate($1) <= day
+  and day < toDate($2)
+  and ($3 or has($4, tenant))
+group by day, brigade_id
+order by day, brigade_id;
@@ -0,0 +1,4 @@
+select id, topic, partition, "offset", task_id, payload, reason, created_at
+from dead_letters
+order by id desc
+limit $1 offset $2;
@@ -0,0 +1,18 @@
+select device_id,
+       inspected_device_id,
+       read_at,
+       value,
+       consumption_kwh,
+       device_type,
+       device_number,
+       seal_numbers,
+       task_id,
+       finished_at,
+       object_id,
+       object_address,
+       subscriber_id,
+       subscriber_account_number
+from v_bi_device_readings
+where device_id = $1
+  and ($2 or has($3, tenant))
+order by read_at, inspected_device_id;
@@ -0,0 +1,3 @@
+select distinct task_id
+from finished_tasks
+where has($1, task_id);
@@ -0,0 +1,6 @@
+select id, period_start, period_end, table_name, status, events, error, requested_by, created_at, finished_at
+from replay_jobs
+where status in ('done', 'failed')
+  and table_name <> ''
+  and finished_at < $1
+order by id;
@@ -25,7 +25,16 @@ select task_id,
        brigade_inspectors,
        object_id,
        object_address,
+       object_region,
+       object_city,
+       object_district,
+       object_street,
+       object_house,
+       object_flat,
        object_have_automaton,
+       object_devices,
+       contract_number,
+       contract_sign_date,
        subscriber_id,
        subscriber_account_number,
        subscriber_surname,
@@ -35,8 +44,10 @@ select task_id,
        subscriber_email,
        subscriber_inn,
        subscriber_birth_date,
-       CAST(subscriber_status, 'Int8')      as subscriber_status
+       CAST(subscriber_status, 'Int8')      as subscriber_status,
+       tenant
 from finished_tasks
 where $1 <= finished_at
   and finished_at < $2
+  and ($3 or has($4, tenant))
 order by finished_at;
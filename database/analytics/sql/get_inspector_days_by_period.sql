@@ -0,0 +1,13 @@
+select day,
+       inspector_id,
+       any(inspector_full_name)       as inspector_full_name,
+       brigade_id,
+       sum(tasks_count)               as tasks_count,
+       sum(violations_detected_count) as violations_detected_count,
+       sum(total_duration_minutes)    as total_duration_minutes
+from v_bi_inspector_daily
+where toDate($1) <= day
+  and day < toDate($2)
+  and ($3 or has($4, tenant))
+group by day, inspector_id, brigade_id
+order by inspector_id, day, brigade_id;
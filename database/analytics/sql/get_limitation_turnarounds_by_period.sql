@@ -0,0 +1,13 @@
+select object_id,
+       object_address,
+       subscriber_account_number,
+       limitation_task_id,
+       limited_at,
+       resumption_task_id,
+       resumed_at,
+       disconnected_minutes
+from v_bi_limitation_turnaround
+where toDate($1) <= toDate(limited_at)
+  and toDate(limited_at) < toDate($2)
+  and ($3 or has($4, tenant))
+order by object_id, limited_at;
@@ -0,0 +1,10 @@
+select object_id,
+       argMax(object_address, day)    as object_address,
+       sum(limitation_refusals_count) as limitation_refusals_count,
+       sum(resumption_refusals_count) as resumption_refusals_count
+from v_bi_object_access_refusals
+where toDate($1) <= day
+  and day < toDate($2)
+  and ($3 or has($4, tenant))
+group by object_id
+order by object_id;
@@ -0,0 +1,13 @@
+select object_id,
+       object_address,
+       subscriber_account_number,
+       limitation_task_id,
+       limited_at,
+       resumption_task_id,
+       resumed_at,
+       disconnected_minutes
+from v_bi_limitation_turnaround
+where resumption_task_id is null
+  and limited_at < $1
+  and ($2 or has($3, tenant))
+order by limited_at, object_id;
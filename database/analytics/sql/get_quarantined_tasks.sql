@@ -0,0 +1,12 @@
+select id,
+       task_id,
+       payload::text        as payload,
+       missing_fields::text as missing_fields,
+       reason,
+       created_at,
+       repaired_at
+from quarantined_tasks
+where repaired_at is null
+  and ($1 = 0 or id = $1)
+order by id
+limit $2 offset $3;
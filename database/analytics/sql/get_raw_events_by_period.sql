@@ -0,0 +1,11 @@
+select topic,
+       partition,
+       offset,
+       key,
+       headers,
+       value,
+       received_at
+from raw_events
+where received_at >= ?
+  and received_at < ?
+order by received_at, partition, offset;
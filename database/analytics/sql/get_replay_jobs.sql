@@ -0,0 +1,5 @@
+select id, period_start, period_end, table_name, status, events, error, requested_by, created_at, finished_at
+from replay_jobs
+where $1 = 0 or id = $1
+order by id desc
+limit $2 offset $3;
@@ -0,0 +1,7 @@
+select exists(select 1
+              from attachments a
+                       join reports r on r.id = a.report_id
+              where a.report_id = $1
+                and a.file_id = $2
+                and ($3 or (r.tenants <> '[]' and r.tenants <@ cast($4 as jsonb)))
+                and (r.type, r.masking_policy) in (select * from unnest(cast($5 as int[]), cast($6 as text[]))));
@@ -0,0 +1,15 @@
+select day,
+       sum(tasks_count)                                                   as tasks_count,
+       sum(limitation_count)                                              as limitation_count,
+       sum(resumption_count)                                              as resumption_count,
+       sum(verification_count)                                            as verification_count,
+       sum(unauthorized_connection_count)                                 as unauthorized_connection_count,
+       sum(violations_detected_count)                                     as violations_detected_count,
+       sum(unauthorized_consumers_count)                                  as unauthorized_consumers_count,
+       round(sum(avg_duration_minutes * tasks_count) / sum(tasks_count), 2) as avg_duration_minutes
+from v_bi_tasks_daily
+where toDate($1) <= day
+  and day < toDate($2)
+  and ($3 or has($4, tenant))
+group by day
+order by day;
@@ -0,0 +1,14 @@
+select day,
+       task_id,
+       brigade_id,
+       inspector_ids,
+       inspector_full_names,
+       district_name,
+       plan_visit_at,
+       started_at,
+       delay_minutes
+from v_bi_visit_punctuality
+where toDate($1) <= day
+  and day < toDate($2)
+  and ($3 or has($4, tenant))
+order by day, task_id;
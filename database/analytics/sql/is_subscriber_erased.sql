@@ -0,0 +1,4 @@
+select exists(select 1
+              from erasure_requests
+              where subscriber_id = $1
+                and (tenants = '[]' or tenants @> jsonb_build_array(cast($2 as text))));
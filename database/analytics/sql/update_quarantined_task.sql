@@ -0,0 +1,5 @@
+update quarantined_tasks
+set missing_fields = cast(:missing_fields as jsonb),
+    reason         = :reason,
+    repaired_at    = :repaired_at
+where id = :id;
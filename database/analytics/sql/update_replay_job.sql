@@ -0,0 +1,7 @@
+update replay_jobs
+set table_name  = :table_name,
+    status      = :status,
+    events      = :events,
+    error       = :error,
+    finished_at = :finished_at
+where id = :id;
@@ -0,0 +1,127 @@
+package anomaly
+
+import (
+	"analytics-service/service/anomaly"
+	"encoding/json"
+	"fmt"
+)
+
+func MapRuleFromDB(r Rule) (anomaly.Rule, error) {
+	var params anomaly.RuleParams
+	if err := json.Unmarshal([]byte(r.Params), &params); err != nil {
+		return anomaly.Rule{}, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return anomaly.Rule{
+		ID:        r.ID,
+		Name:      r.Name,
+		Kind:      anomaly.RuleKind(r.Kind),
+		Params:    params,
+		Enabled:   r.Enabled,
+		UpdatedAt: r.UpdatedAt,
+	}, nil
+}
+
+func MapRuleSliceFromDB(rules []Rule) ([]anomaly.Rule, error) {
+	result := make([]anomaly.Rule, 0, len(rules))
+	for _, r := range rules {
+		rule, err := MapRuleFromDB(r)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", r.ID, err)
+		}
+
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+func MapMonthlyConsumptionFromDB(c MonthlyConsumption) anomaly.MonthlyConsumption {
+	return anomaly.MonthlyConsumption{
+		Month:                   c.Month,
+		SubscriberID:            int(c.SubscriberID),
+		SubscriberAccountNumber: c.SubscriberAccountNumber,
+		ObjectID:                int(c.ObjectID),
+		ObjectAddress:           c.ObjectAddress,
+		DistrictName:            c.DistrictName,
+		Tenant:                  c.Tenant,
+		ConsumptionKWh:          c.ConsumptionKWh,
+	}
+}
+
+func MapMonthlyConsumptionSliceFromDB(consumption []MonthlyConsumption) []anomaly.MonthlyConsumption {
+	result := make([]anomaly.MonthlyConsumption, 0, len(consumption))
+	for _, c := range consumption {
+		result = append(result, MapMonthlyConsumptionFromDB(c))
+	}
+
+	return result
+}
+
+func MapAnomalyToDB(a anomaly.Anomaly) Anomaly {
+	return Anomaly{
+		ID:                      a.ID,
+		RuleID:                  a.RuleID,
+		RuleName:                a.RuleName,
+		RuleKind:                string(a.RuleKind),
+		Month:                   a.Month,
+		SubscriberID:            a.SubscriberID,
+		SubscriberAccountNumber: a.SubscriberAccountNumber,
+		ObjectID:                a.ObjectID,
+		ObjectAddress:           a.ObjectAddress,
+		DistrictName:            a.DistrictName,
+		Tenant:                  a.Tenant,
+		ConsumptionKWh:          a.ConsumptionKWh,
+		ExpectedKWh:             a.ExpectedKWh,
+		Score:                   a.Score,
+		Status:                  string(a.Status),
+		Comment:                 a.Comment,
+		TriagedBy:               a.TriagedBy,
+		TriagedAt:               a.TriagedAt,
+		CreatedAt:               a.CreatedAt,
+		UpdatedAt:               a.UpdatedAt,
+	}
+}
+
+func MapAnomalySliceToDB(anomalies []anomaly.Anomaly) []Anomaly {
+	result := make([]Anomaly, 0, len(anomalies))
+	for _, a := range anomalies {
+		result = append(result, MapAnomalyToDB(a))
+	}
+
+	return result
+}
+
+func MapAnomalyFromDB(a Anomaly) anomaly.Anomaly {
+	return anomaly.Anomaly{
+		ID:                      a.ID,
+		RuleID:                  a.RuleID,
+		RuleName:                a.RuleName,
+		RuleKind:                anomaly.RuleKind(a.RuleKind),
+		Month:                   a.Month,
+		SubscriberID:            a.SubscriberID,
+		SubscriberAccountNumber: a.SubscriberAccountNumber,
+		ObjectID:                a.ObjectID,
+		ObjectAddress:           a.ObjectAddress,
+		DistrictName:            a.DistrictName,
+		Tenant:                  a.Tenant,
+		ConsumptionKWh:          a.ConsumptionKWh,
+		ExpectedKWh:             a.ExpectedKWh,
+		Score:                   a.Score,
+		Status:                  anomaly.Status(a.Status),
+		Comment:                 a.Comment,
+		TriagedBy:               a.TriagedBy,
+		TriagedAt:               a.TriagedAt,
+		CreatedAt:               a.CreatedAt,
+		UpdatedAt:               a.UpdatedAt,
+	}
+}
+
+func MapAnomalySliceFromDB(anomalies []Anomaly) []anomaly.Anomaly {
+	result := make([]anomaly.Anomaly, 0, len(anomalies))
+	for _, a := range anomalies {
+		result = append(result, MapAnomalyFromDB(a))
+	}
+
+	return result
+}
@@ -0,0 +1,46 @@
+package anomaly
+
+import "time"
+
+type Rule struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	Kind      string    `db:"kind"`
+	Params    string    `db:"params"`
+	Enabled   bool      `db:"enabled"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type MonthlyConsumption struct {
+	Month                   time.Time `ch:"month"`
+	SubscriberID            int64     `ch:"subscriber_id"`
+	SubscriberAccountNumber string    `ch:"subscriber_account_number"`
+	ObjectID                int64     `ch:"object_id"`
+	ObjectAddress           string    `ch:"object_address"`
+	DistrictName            string    `ch:"district_name"`
+	Tenant                  string    `ch:"tenant"`
+	ConsumptionKWh          float64   `ch:"consumption_kwh"`
+}
+
+type Anomaly struct {
+	ID                      int        `db:"id"`
+	RuleID                  int        `db:"rule_id"`
+	RuleName                string     `db:"rule_name"`
+	RuleKind                string     `db:"rule_kind"`
+	Month                   time.Time  `db:"month"`
+	SubscriberID            int        `db:"subscriber_id"`
+	SubscriberAccountNumber string     `db:"subscriber_account_number"`
+	ObjectID                int        `db:"object_id"`
+	ObjectAddress           string     `db:"object_address"`
+	DistrictName            string     `db:"district_name"`
+	Tenant                  string     `db:"tenant"`
+	ConsumptionKWh          float64    `db:"consumption_kwh"`
+	ExpectedKWh             *float64   `db:"expected_kwh"`
+	Score                   float64    `db:"score"`
+	Status                  string     `db:"status"`
+	Comment                 string     `db:"comment"`
+	TriagedBy               *int       `db:"triaged_by"`
+	TriagedAt               *time.Time `db:"triaged_at"`
+	CreatedAt               time.Time  `db:"created_at"`
+	UpdatedAt               time.Time  `db:"updated_at"`
+}
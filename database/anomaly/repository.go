@@ -0,0 +1,200 @@
+package anomaly
+
+import (
+	"analytics-service/service/anomaly"
+	"analytics-service/service/auth"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/jmoiron/sqlx"
+	"github.com/sunshineOfficial/golib/pagination"
+	"go.opentelemetry.io/otel"
+)
+
+// upsertBatchSize keeps the number of bind parameters of a single insert far below the Postgres limit.
+const upsertBatchSize = 1000
+
+var tracer = otel.Tracer("analytics-service/database/anomaly")
+
+var (
+	//go:embed sql/delete_stale_anomalies.sql
+	deleteStaleAnomaliesSQL string
+
+	//go:embed sql/get_anomalies.sql
+	getAnomaliesSQL string
+
+	//go:embed sql/get_monthly_consumption.sql
+	getMonthlyConsumptionSQL string
+
+	//go:embed sql/get_rules.sql
+	getRulesSQL string
+
+	//go:embed sql/get_untenanted_anomalies.sql
+	getUntenantedAnomaliesSQL string
+
+	//go:embed sql/update_anomaly_tenants.sql
+	updateAnomalyTenantsSQL string
+
+	//go:embed sql/update_anomaly_status.sql
+	updateAnomalyStatusSQL string
+
+	//go:embed sql/upsert_anomalies.sql
+	upsertAnomaliesSQL string
+)
+
+type Repository struct {
+	postgres   *sqlx.DB
+	clickhouse driver.Conn
+}
+
+func NewRepository(postgres *sqlx.DB, clickhouse driver.Conn) *Repository {
+	return &Repository{
+		postgres:   postgres,
+		clickhouse: clickhouse,
+	}
+}
+
+func (r *Repository) GetRules(ctx context.Context) ([]anomaly.Rule, error) {
+	ctx, span := tracer.Start(ctx, "anomaly.Repository.GetRules")
+	defer span.End()
+
+	var dbRules []Rule
+	if err := r.postgres.SelectContext(ctx, &dbRules, getRulesSQL); err != nil {
+		return nil, fmt.Errorf("r.postgres.SelectContext: %w", err)
+	}
+
+	rules, err := MapRuleSliceFromDB(dbRules)
+	if err != nil {
+		return nil, fmt.Errorf("MapRuleSliceFromDB: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *Repository) GetMonthlyConsumption(ctx context.Context, before time.Time) ([]anomaly.MonthlyConsumption, error) {
+	ctx, span := tracer.Start(ctx, "anomaly.Repository.GetMonthlyConsumption")
+	defer span.End()
+
+	var consumption []MonthlyConsumption
+	if err := r.clickhouse.Select(ctx, &consumption, getMonthlyConsumptionSQL, before); err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	return MapMonthlyConsumptionSliceFromDB(consumption), nil
+}
+
+// UpsertAnomalies stores the anomalies of an evaluation and deletes new anomalies from the month on that the evaluation
+// didn't flag again, in one transaction. Triaged anomalies are kept. It returns the number of deleted anomalies.
+func (r *Repository) UpsertAnomalies(ctx context.Context, from time.Time, anomalies []anomaly.Anomaly) (_ int, err error) {
+	ctx, span := tracer.Start(ctx, "anomaly.Repository.UpsertAnomalies")
+	defer span.End()
+
+	tx, err := r.postgres.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("r.postgres.BeginTxx: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	dbAnomalies := MapAnomalySliceToDB(anomalies)
+	for start := 0; start < len(dbAnomalies); start += upsertBatchSize {
+		batch := dbAnomalies[start:min(start+upsertBatchSize, len(dbAnomalies))]
+		if _, err = tx.NamedExecContext(ctx, upsertAnomaliesSQL, batch); err != nil {
+			err = fmt.Errorf("tx.NamedExecContext: %w", err)
+			return 0, err
+		}
+	}
+
+	// now() is the start of the transaction, so every anomaly upserted above has it as updated_at.
+	result, err := tx.ExecContext(ctx, deleteStaleAnomaliesSQL, from)
+	if err != nil {
+		err = fmt.Errorf("tx.ExecContext: %w", err)
+		return 0, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		err = fmt.Errorf("result.RowsAffected: %w", err)
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		err = fmt.Errorf("tx.Commit: %w", err)
+		return 0, err
+	}
+
+	return int(deleted), nil
+}
+
+func (r *Repository) GetAnomalies(ctx context.Context, scope auth.TenantScope, filter anomaly.Filter, page pagination.Pagination) ([]anomaly.Anomaly, error) {
+	ctx, span := tracer.Start(ctx, "anomaly.Repository.GetAnomalies")
+	defer span.End()
+
+	var anomalies []Anomaly
+	err := r.postgres.SelectContext(ctx, &anomalies, getAnomaliesSQL,
+		filter.ID, string(filter.Status), filter.RuleID, filter.ObjectID, page.LimitArg(), page.Offset, scope.All, scope.Tenants)
+	if err != nil {
+		return nil, fmt.Errorf("r.postgres.SelectContext: %w", err)
+	}
+
+	return MapAnomalySliceFromDB(anomalies), nil
+}
+
+// GetUntenantedAnomalies returns the anomalies stored before tenancy, they have no tenant.
+func (r *Repository) GetUntenantedAnomalies(ctx context.Context) ([]anomaly.Anomaly, error) {
+	ctx, span := tracer.Start(ctx, "anomaly.Repository.GetUntenantedAnomalies")
+	defer span.End()
+
+	var anomalies []Anomaly
+	if err := r.postgres.SelectContext(ctx, &anomalies, getUntenantedAnomaliesSQL); err != nil {
+		return nil, fmt.Errorf("r.postgres.SelectContext: %w", err)
+	}
+
+	return MapAnomalySliceFromDB(anomalies), nil
+}
+
+// UpdateAnomalyTenants sets the tenants of anomalies that have none, other fields are left as they are.
+func (r *Repository) UpdateAnomalyTenants(ctx context.Context, anomalies []anomaly.Anomaly) error {
+	ctx, span := tracer.Start(ctx, "anomaly.Repository.UpdateAnomalyTenants")
+	defer span.End()
+
+	ids := make([]int64, 0, len(anomalies))
+	tenants := make([]string, 0, len(anomalies))
+	for _, a := range anomalies {
+		ids = append(ids, int64(a.ID))
+		tenants = append(tenants, a.Tenant)
+	}
+
+	if _, err := r.postgres.ExecContext(ctx, updateAnomalyTenantsSQL, ids, tenants); err != nil {
+		return fmt.Errorf("r.postgres.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) UpdateAnomalyStatus(ctx context.Context, scope auth.TenantScope, id int, status anomaly.Status, comment string, triagedBy int) error {
+	ctx, span := tracer.Start(ctx, "anomaly.Repository.UpdateAnomalyStatus")
+	defer span.End()
+
+	result, err := r.postgres.ExecContext(ctx, updateAnomalyStatusSQL, id, string(status), comment, triagedBy, scope.All, scope.Tenants)
+	if err != nil {
+		return fmt.Errorf("r.postgres.ExecContext: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("anomaly %d not found", id)
+	}
+
+	return nil
+}
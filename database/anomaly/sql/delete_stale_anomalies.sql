@@ -0,0 +1,5 @@
+delete
+from anomalies
+where status = 'new'
+  and month >= $1
+  and updated_at < now();
@@ -0,0 +1,29 @@
+select a.id,
+       a.rule_id,
+       r.name as rule_name,
+       r.kind as rule_kind,
+       a.month,
+       a.subscriber_id,
+       a.subscriber_account_number,
+       a.object_id,
+       a.object_address,
+       a.district_name,
+       a.tenant,
+       a.consumption_kwh,
+       a.expected_kwh,
+       a.score,
+       a.status,
+       a.comment,
+       a.triaged_by,
+       a.triaged_at,
+       a.created_at,
+       a.updated_at
+from anomalies a
+         join anomaly_rules r on r.id = a.rule_id
+where ($1 = 0 or a.id = $1)
+  and ($2 = '' or a.status = $2)
+  and ($3 = 0 or a.rule_id = $3)
+  and ($4 = 0 or a.object_id = $4)
+  and ($7 or a.tenant = any ($8))
+order by a.month desc, a.score desc, a.id
+limit $5 offset $6;
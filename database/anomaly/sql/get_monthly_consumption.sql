@@ -0,0 +1,11 @@
+select month,
+       subscriber_id,
+       subscriber_account_number,
+       object_id,
+       object_address,
+       district_name,
+       tenant,
+       toFloat64(monthly_consumption_kwh) as consumption_kwh
+from v_bi_consumption_monthly
+where month < toDate($1)
+order by subscriber_id, object_id, month;
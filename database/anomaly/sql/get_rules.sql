@@ -0,0 +1,8 @@
+select id,
+       name,
+       kind,
+       params::text as params,
+       enabled,
+       updated_at
+from anomaly_rules
+order by id;
@@ -0,0 +1,24 @@
+select a.id,
+       a.rule_id,
+       r.name as rule_name,
+       r.kind as rule_kind,
+       a.month,
+       a.subscriber_id,
+       a.subscriber_account_number,
+       a.object_id,
+       a.object_address,
+       a.district_name,
+       a.tenant,
+       a.consumption_kwh,
+       a.expected_kwh,
+       a.score,
+       a.status,
+       a.comment,
+       a.triaged_by,
+       a.triaged_at,
+       a.created_at,
+       a.updated_at
+from anomalies a
+         join anomaly_rules r on r.id = a.rule_id
+where a.tenant = ''
+order by a.id;
@@ -0,0 +1,8 @@
+update anomalies
+set status     = $2,
+    comment    = $3,
+    triaged_by = $4,
+    triaged_at = now(),
+    updated_at = now()
+where id = $1
+  and ($5 or tenant = any ($6));
@@ -0,0 +1,6 @@
+update anomalies a
+set tenant     = t.tenant,
+    updated_at = now()
+from unnest(cast($1 as int[]), cast($2 as text[])) as t (id, tenant)
+where a.id = t.id
+  and a.tenant = '';
@@ -0,0 +1,13 @@
+insert into anomalies (rule_id, month, subscriber_id, subscriber_account_number, object_id, object_address, district_name,
+                       tenant, consumption_kwh, expected_kwh, score)
+values (:rule_id, :month, :subscriber_id, :subscriber_account_number, :object_id, :object_address, :district_name,
+        :tenant, :consumption_kwh, :expected_kwh, :score)
+on conflict (rule_id, subscriber_id, object_id, month) do update
+    set tenant          = excluded.tenant,
+        consumption_kwh = case when anomalies.status = 'new' then excluded.consumption_kwh else anomalies.consumption_kwh end,
+        expected_kwh    = case when anomalies.status = 'new' then excluded.expected_kwh else anomalies.expected_kwh end,
+        score           = case when anomalies.status = 'new' then excluded.score else anomalies.score end,
+        updated_at      = now()
+-- Triaged anomalies keep the values they were triaged on, only their tenant follows the finished tasks.
+where anomalies.status = 'new'
+   or anomalies.tenant <> excluded.tenant;
@@ -0,0 +1,77 @@
+package audit
+
+import (
+	"analytics-service/service/audit"
+	"analytics-service/service/auth"
+	"encoding/json"
+	"fmt"
+)
+
+func MapRecordToDB(r audit.Record) (Record, error) {
+	parameters := r.Parameters
+	if parameters == nil {
+		parameters = map[string]string{}
+	}
+
+	rawParameters, err := json.Marshal(parameters)
+	if err != nil {
+		return Record{}, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return Record{
+		ID:         r.ID,
+		ActorID:    r.ActorID,
+		ActorRole:  string(r.ActorRole),
+		Action:     string(r.Action),
+		ReportID:   r.ReportID,
+		Parameters: string(rawParameters),
+		ClientIP:   r.ClientIP,
+		CreatedAt:  r.CreatedAt,
+	}, nil
+}
+
+func MapRecordSliceToDB(records []audit.Record) ([]Record, error) {
+	result := make([]Record, 0, len(records))
+	for _, r := range records {
+		dbRecord, err := MapRecordToDB(r)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, dbRecord)
+	}
+
+	return result, nil
+}
+
+func MapRecordFromDB(r Record) (audit.Record, error) {
+	var parameters map[string]string
+	if err := json.Unmarshal([]byte(r.Parameters), &parameters); err != nil {
+		return audit.Record{}, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return audit.Record{
+		ID:         r.ID,
+		ActorID:    r.ActorID,
+		ActorRole:  auth.Role(r.ActorRole),
+		Action:     audit.Action(r.Action),
+		ReportID:   r.ReportID,
+		Parameters: parameters,
+		ClientIP:   r.ClientIP,
+		CreatedAt:  r.CreatedAt,
+	}, nil
+}
+
+func MapRecordSliceFromDB(records []Record) ([]audit.Record, error) {
+	result := make([]audit.Record, 0, len(records))
+	for _, r := range records {
+		record, err := MapRecordFromDB(r)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, record)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,14 @@
+package audit
+
+import "time"
+
+type Record struct {
+	ID         int       `db:"id"`
+	ActorID    int       `db:"actor_id"`
+	ActorRole  string    `db:"actor_role"`
+	Action     string    `db:"action"`
+	ReportID   *int      `db:"report_id"`
+	Parameters string    `db:"parameters"`
+	ClientIP   string    `db:"client_ip"`
+	CreatedAt  time.Time `db:"created_at"`
+}
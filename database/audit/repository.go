@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"analytics-service/service/audit"
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sunshineOfficial/golib/pagination"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("analytics-service/database/audit")
+
+var (
+	//go:embed sql/add_records.sql
+	addRecordsSQL string
+
+	//go:embed sql/get_records.sql
+	getRecordsSQL string
+)
+
+type Repository struct {
+	postgres *sqlx.DB
+}
+
+func NewRepository(postgres *sqlx.DB) *Repository {
+	return &Repository{
+		postgres: postgres,
+	}
+}
+
+func (r *Repository) AddRecords(ctx context.Context, records []audit.Record) error {
+	ctx, span := tracer.Start(ctx, "audit.Repository.AddRecords")
+	defer span.End()
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	dbRecords, err := MapRecordSliceToDB(records)
+	if err != nil {
+		return fmt.Errorf("MapRecordSliceToDB: %w", err)
+	}
+
+	if _, err = r.postgres.NamedExecContext(ctx, addRecordsSQL, dbRecords); err != nil {
+		return fmt.Errorf("r.postgres.NamedExecContext: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetRecords(ctx context.Context, filter audit.Filter, page pagination.Pagination) ([]audit.Record, error) {
+	ctx, span := tracer.Start(ctx, "audit.Repository.GetRecords")
+	defer span.End()
+
+	var dbRecords []Record
+	err := r.postgres.SelectContext(ctx, &dbRecords, getRecordsSQL,
+		filter.ActorID, string(filter.Action), filter.ReportID, filter.From, filter.To, page.LimitArg(), page.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("r.postgres.SelectContext: %w", err)
+	}
+
+	records, err := MapRecordSliceFromDB(dbRecords)
+	if err != nil {
+		return nil, fmt.Errorf("MapRecordSliceFromDB: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,2 @@
+insert into audit_log (actor_id, actor_role, action, report_id, parameters, client_ip)
+values (:actor_id, :actor_role, :action, :report_id, cast(:parameters as jsonb), :client_ip);
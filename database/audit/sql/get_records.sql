@@ -0,0 +1,16 @@
+select id,
+       actor_id,
+       actor_role,
+       action,
+       report_id,
+       parameters::text as parameters,
+       client_ip,
+       created_at
+from audit_log
+where ($1 = 0 or actor_id = $1)
+  and ($2 = '' or action = $2)
+  and ($3 = 0 or report_id = $3)
+  and ($4::timestamptz is null or created_at >= $4)
+  and ($5::timestamptz is null or created_at < $5)
+order by id desc
+limit $6 offset $7;
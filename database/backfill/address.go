@@ -0,0 +1,128 @@
+package backfill
+
+import (
+	"analytics-service/config"
+	"analytics-service/service/address"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/sunshineOfficial/golib/golog"
+)
+
+var (
+	//go:embed sql/add_address_parts.sql
+	addAddressPartsSQL string
+
+	//go:embed sql/count_pending_mutations.sql
+	countPendingMutationsSQL string
+
+	//go:embed sql/create_address_parts.sql
+	createAddressPartsSQL string
+
+	//go:embed sql/get_unparsed_addresses.sql
+	getUnparsedAddressesSQL string
+
+	//go:embed sql/update_address_parts.sql
+	updateAddressPartsSQL string
+)
+
+const (
+	addressPartsTable    = "object_address_parts"
+	truncateAddressParts = "truncate table object_address_parts"
+)
+
+// AddressParts is a parsed object address, keyed by the address it's parsed from.
+type AddressParts struct {
+	Address string
+	Parts   address.Address
+}
+
+// ParseAddresses parses the addresses with the configured markers and dictionary. Addresses without a region, city,
+// district or street are left out, the views fall back to the address for them anyway.
+func ParseAddresses(settings config.Address, addresses []string) []AddressParts {
+	parser := address.NewParser(settings)
+
+	parts := make([]AddressParts, 0, len(addresses))
+	for _, a := range addresses {
+		parsed := parser.Parse(a)
+		if parsed.Region == "" && parsed.City == "" && parsed.District == "" && parsed.Street == "" {
+			continue
+		}
+
+		parts = append(parts, AddressParts{Address: a, Parts: parsed})
+	}
+
+	return parts
+}
+
+// Addresses fills the address parts of finished tasks stored before they were parsed on ingestion, so the views
+// group them with the new tasks of the same district. The addresses are parsed here, written to a Join table and
+// applied by a single mutation, which ClickHouse runs in the background. The backfill is skipped while the previous
+// mutation is still running, a finished one leaves nothing to parse.
+func Addresses(ctx context.Context, log golog.Logger, conn driver.Conn, settings config.Address) error {
+	var pending uint64
+	if err := conn.QueryRow(ctx, countPendingMutationsSQL, addressPartsTable).Scan(&pending); err != nil {
+		return fmt.Errorf("count pending mutations: %w", err)
+	}
+	if pending > 0 {
+		log.Debugf("address parts backfill is still running")
+		return nil
+	}
+
+	var addresses []string
+	if err := conn.Select(ctx, &addresses, getUnparsedAddressesSQL); err != nil {
+		return fmt.Errorf("get unparsed addresses: %w", err)
+	}
+
+	parts := ParseAddresses(settings, addresses)
+	if len(parts) == 0 {
+		return nil
+	}
+
+	if err := conn.Exec(ctx, createAddressPartsSQL); err != nil {
+		return fmt.Errorf("create address parts: %w", err)
+	}
+	if err := conn.Exec(ctx, truncateAddressParts); err != nil {
+		return fmt.Errorf("truncate address parts: %w", err)
+	}
+	if err := addAddressParts(ctx, conn, parts); err != nil {
+		return fmt.Errorf("add address parts: %w", err)
+	}
+
+	// joinGet reads another table, ClickHouse treats such mutations as nondeterministic.
+	ctx = clickhouse.Context(ctx, clickhouse.WithSettings(clickhouse.Settings{"allow_nondeterministic_mutations": 1}))
+	if err := conn.Exec(ctx, updateAddressPartsSQL); err != nil {
+		return fmt.Errorf("update address parts: %w", err)
+	}
+
+	log.Debugf("address parts backfill is started for %d addresses", len(parts))
+
+	return nil
+}
+
+func addAddressParts(ctx context.Context, conn driver.Conn, parts []AddressParts) (err error) {
+	batch, err := conn.PrepareBatch(ctx, addAddressPartsSQL)
+	if err != nil {
+		return fmt.Errorf("conn.PrepareBatch: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, batch.Close())
+	}()
+
+	for _, p := range parts {
+		err = batch.Append(p.Address, p.Parts.Region, p.Parts.City, p.Parts.District, p.Parts.Street, p.Parts.House, p.Parts.Flat)
+		if err != nil {
+			return fmt.Errorf("batch.Append: %w", err)
+		}
+	}
+
+	if err = batch.Send(); err != nil {
+		return fmt.Errorf("batch.Send: %w", err)
+	}
+
+	return nil
+}
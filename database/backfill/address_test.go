@@ -0,0 +1,29 @@
+package backfill
+
+import (
+	"analytics-service/config"
+	"analytics-service/service/address"
+	"testing"
+)
+
+func TestParseAddressesSkipUnparsed(t *testing.T) {
+	settings := config.Address{
+		Markers: config.AddressMarkers{
+			District: []string{"р-н", "район"},
+			Street:   []string{"ул."},
+		},
+		Dictionary: config.AddressDictionary{
+			Districts: []config.AddressEntry{{Name: "Кировский р-н", Aliases: []string{"Кировский район"}}},
+		},
+	}
+
+	parts := ParseAddresses(settings, []string{"Кировский район, ул. Ленина, 1", ""})
+	if len(parts) != 1 {
+		t.Fatalf("expected only the parsed address, got %+v", parts)
+	}
+
+	want := address.Address{District: "Кировский р-н", Street: "ул. Ленина", House: "1"}
+	if parts[0].Address != "Кировский район, ул. Ленина, 1" || parts[0].Parts != want {
+		t.Fatalf("expected %+v, got %+v", want, parts[0])
+	}
+}
@@ -0,0 +1 @@
+insert into object_address_parts (object_address, region, city, district, street, house, flat);
@@ -0,0 +1,6 @@
+select count()
+from system.mutations
+where database = currentDatabase()
+  and table = 'finished_tasks'
+  and not is_done
+  and positionCaseInsensitive(command, $1) > 0;
@@ -0,0 +1,4 @@
+select count()
+from finished_tasks
+where empty(tenant)
+  and notEmpty({tenant});
@@ -0,0 +1,11 @@
+create table if not exists object_address_parts
+(
+    object_address String,
+    region         String,
+    city           String,
+    district       String,
+    street         String,
+    house          String,
+    flat           String
+)
+    engine = Join(any, left, object_address);
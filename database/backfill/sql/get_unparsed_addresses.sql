@@ -0,0 +1,7 @@
+select distinct object_address
+from finished_tasks
+where empty(object_region)
+  and empty(object_city)
+  and empty(object_district)
+  and empty(object_street)
+  and notEmpty(object_address);
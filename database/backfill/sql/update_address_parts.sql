@@ -0,0 +1,13 @@
+-- Only tasks stored before the address parts were parsed on ingestion have all the parts empty.
+alter table finished_tasks
+    update object_region = joinGet('object_address_parts', 'region', object_address),
+           object_city = joinGet('object_address_parts', 'city', object_address),
+           object_district = joinGet('object_address_parts', 'district', object_address),
+           object_street = joinGet('object_address_parts', 'street', object_address),
+           object_house = joinGet('object_address_parts', 'house', object_address),
+           object_flat = joinGet('object_address_parts', 'flat', object_address)
+    where empty(object_region)
+      and empty(object_city)
+      and empty(object_district)
+      and empty(object_street)
+      and joinHas('object_address_parts', object_address);
@@ -0,0 +1,8 @@
+-- Reports created before the tenants migration was applied have the default empty list.
+update reports
+set tenants = jsonb_build_array(cast($1 as text))
+where tenants = '[]'
+  and created_at < (select min(tstamp)
+                    from goose_db_version
+                    where version_id = 6
+                      and is_applied);
@@ -0,0 +1,5 @@
+-- Tasks stored before tenancy have no tenant, they are resolved the way ingestion resolves new tasks.
+alter table finished_tasks
+    update tenant = {tenant}
+    where empty(tenant)
+      and notEmpty({tenant});
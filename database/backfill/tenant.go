@@ -0,0 +1,140 @@
+package backfill
+
+import (
+	"analytics-service/config"
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/jmoiron/sqlx"
+	"github.com/sunshineOfficial/golib/golog"
+)
+
+var (
+	//go:embed sql/count_untenanted_tasks.sql
+	countUntenantedTasksSQL string
+
+	//go:embed sql/update_report_tenants.sql
+	updateReportTenantsSQL string
+
+	//go:embed sql/update_tenants.sql
+	updateTenantsSQL string
+)
+
+// tenantMutationMarker is how ClickHouse lists the tenant backfill in system.mutations.
+const tenantMutationMarker = "update tenant ="
+
+// TenantExpression resolves the tenant of a finished task in ClickHouse the way tenant.Resolver does on ingestion:
+// by brigade first, then by the parsed region, city or district, otherwise it's the default tenant.
+// The values are bound as $N arguments.
+func TenantExpression(settings config.Tenancy) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	bind := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	for _, t := range settings.Tenants {
+		if len(t.BrigadeIDs) == 0 {
+			continue
+		}
+
+		brigadeIDs := make([]int64, 0, len(t.BrigadeIDs))
+		for _, id := range t.BrigadeIDs {
+			brigadeIDs = append(brigadeIDs, int64(id))
+		}
+
+		conditions = append(conditions, fmt.Sprintf("has(%s, brigade_id), %s", bind(brigadeIDs), bind(t.ID)))
+	}
+
+	for _, t := range settings.Tenants {
+		var matches []string
+		for _, area := range []struct {
+			column string
+			names  []string
+		}{
+			{column: "object_region", names: t.Regions},
+			{column: "object_city", names: t.Cities},
+			{column: "object_district", names: t.Districts},
+		} {
+			if names := lowerNames(area.names); len(names) > 0 {
+				matches = append(matches, fmt.Sprintf("has(%s, lowerUTF8(%s))", bind(names), area.column))
+			}
+		}
+
+		if len(matches) > 0 {
+			conditions = append(conditions, fmt.Sprintf("%s, %s", strings.Join(matches, " or "), bind(t.ID)))
+		}
+	}
+
+	defaultTenant := bind(settings.DefaultTenant)
+	if len(conditions) == 0 {
+		return defaultTenant, args
+	}
+
+	return fmt.Sprintf("multiIf(%s, %s)", strings.Join(conditions, ", "), defaultTenant), args
+}
+
+// lowerNames drops empty names, the resolver never matches an empty address part.
+func lowerNames(names []string) []string {
+	var result []string
+	for _, name := range names {
+		if name != "" {
+			result = append(result, strings.ToLower(name))
+		}
+	}
+
+	return result
+}
+
+// Tenants assigns tenants to finished tasks stored before tenancy. The mutation is submitted after the address parts
+// backfill, ClickHouse runs the mutations of a table in order, so the tenants are resolved by the parsed parts.
+func Tenants(ctx context.Context, log golog.Logger, conn driver.Conn, settings config.Tenancy) error {
+	var pending uint64
+	if err := conn.QueryRow(ctx, countPendingMutationsSQL, tenantMutationMarker).Scan(&pending); err != nil {
+		return fmt.Errorf("count pending mutations: %w", err)
+	}
+	if pending > 0 {
+		log.Debugf("tenants backfill is still running")
+		return nil
+	}
+
+	expression, args := TenantExpression(settings)
+
+	var untenanted uint64
+	err := conn.QueryRow(ctx, strings.ReplaceAll(countUntenantedTasksSQL, "{tenant}", expression), args...).Scan(&untenanted)
+	if err != nil {
+		return fmt.Errorf("count untenanted tasks: %w", err)
+	}
+	if untenanted == 0 {
+		return nil
+	}
+
+	if err = conn.Exec(ctx, strings.ReplaceAll(updateTenantsSQL, "{tenant}", expression), args...); err != nil {
+		return fmt.Errorf("update tenants: %w", err)
+	}
+
+	log.Debugf("tenants backfill is started for %d finished tasks", untenanted)
+
+	return nil
+}
+
+// ReportTenants assigns reports created before tenancy to the default tenant, the way tasks without a matching tenant
+// are assigned. Reports created later by callers of all tenants keep the empty list.
+func ReportTenants(ctx context.Context, postgres *sqlx.DB, settings config.Tenancy) error {
+	if settings.DefaultTenant == "" {
+		return nil
+	}
+
+	if _, err := postgres.ExecContext(ctx, updateReportTenantsSQL, settings.DefaultTenant); err != nil {
+		return fmt.Errorf("update report tenants: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package backfill
+
+import (
+	"analytics-service/config"
+	"reflect"
+	"testing"
+)
+
+func TestTenantExpression(t *testing.T) {
+	expression, args := TenantExpression(config.Tenancy{
+		DefaultTenant: "ekb",
+		Tenants: []config.Tenant{
+			{ID: "ekb", Cities: []string{"Екатеринбург", ""}},
+			{ID: "region", BrigadeIDs: []int{7}, Regions: []string{"Свердловская область"}, Districts: []string{"Кировский р-н"}},
+		},
+	})
+
+	want := "multiIf(has($1, brigade_id), $2, has($3, lowerUTF8(object_city)), $4, " +
+		"has($5, lowerUTF8(object_region)) or has($6, lowerUTF8(object_district)), $7, $8)"
+	if expression != want {
+		t.Fatalf("expected %q, got %q", want, expression)
+	}
+
+	wantArgs := []any{
+		[]int64{7}, "region",
+		[]string{"екатеринбург"}, "ekb",
+		[]string{"свердловская область"}, []string{"кировский р-н"}, "region",
+		"ekb",
+	}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("expected %v, got %v", wantArgs, args)
+	}
+}
+
+func TestTenantExpressionWithoutTenants(t *testing.T) {
+	expression, args := TenantExpression(config.Tenancy{DefaultTenant: "ekb"})
+	if expression != "$1" || !reflect.DeepEqual(args, []any{"ekb"}) {
+		t.Fatalf("expected the default tenant, got %q %v", expression, args)
+	}
+}
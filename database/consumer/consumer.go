@@ -0,0 +1,166 @@
+package consumer
+
+import (
+	"analytics-service/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sunshineOfficial/golib/golog"
+)
+
+const (
+	// defaultCommitInterval is used when the commit interval isn't configured.
+	defaultCommitInterval = time.Second
+
+	// A failed fetch is retried after fetchBackoff, doubled on every consecutive failure up to maxFetchBackoff,
+	// so an unreachable broker isn't hammered and the log isn't flooded.
+	fetchBackoff    = 100 * time.Millisecond
+	maxFetchBackoff = 10 * time.Second
+)
+
+// reader is the part of kafka.Reader used by Consumer.
+type reader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, messages ...kafka.Message) error
+	Close() error
+}
+
+// Handler must call ack once the message is durably handled. Messages are committed in order, so an unacknowledged
+// message holds back commits of its partition.
+type Handler func(message kafka.Message, ack func())
+
+// Consumer reads a topic in a consumer group and commits offsets only for acknowledged messages.
+type Consumer struct {
+	reader         reader
+	log            golog.Logger
+	offsets        *offsets
+	commitInterval time.Duration
+	backoff        time.Duration
+	maxBackoff     time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewConsumer(settings config.Kafka, topic, group string, log golog.Logger) *Consumer {
+	commitInterval := time.Duration(settings.CommitInterval)
+	if commitInterval <= 0 {
+		commitInterval = defaultCommitInterval
+	}
+
+	return &Consumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: settings.Brokers,
+			Topic:   topic,
+			GroupID: group,
+		}),
+		log:            log,
+		offsets:        newOffsets(),
+		commitInterval: commitInterval,
+		backoff:        fetchBackoff,
+		maxBackoff:     maxFetchBackoff,
+	}
+}
+
+// Subscribe starts fetching messages in the background, handler is called from a single goroutine.
+func (c *Consumer) Subscribe(ctx context.Context, handler Handler) {
+	ctx, c.cancel = context.WithCancel(ctx)
+
+	c.wg.Go(func() {
+		c.fetch(ctx, handler)
+	})
+
+	c.wg.Go(func() {
+		ticker := time.NewTicker(c.commitInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.commit(ctx); err != nil {
+					c.log.Errorf("failed to commit offsets: %v", err)
+				}
+			}
+		}
+	})
+}
+
+func (c *Consumer) fetch(ctx context.Context, handler Handler) {
+	failures := 0
+	for {
+		message, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return
+			}
+
+			failures++
+			c.log.Errorf("failed to fetch message: %v", err)
+
+			timer := time.NewTimer(c.fetchBackoff(failures))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			continue
+		}
+
+		failures = 0
+
+		c.offsets.Fetched(message)
+		observeLag(message)
+		handler(message, func() {
+			c.offsets.Acked(message)
+		})
+	}
+}
+
+// fetchBackoff grows exponentially with consecutive failures and is capped by maxBackoff.
+func (c *Consumer) fetchBackoff(failures int) time.Duration {
+	backoff := c.backoff << (failures - 1)
+	if backoff > c.maxBackoff || backoff <= 0 {
+		backoff = c.maxBackoff
+	}
+
+	return backoff
+}
+
+// Stop stops fetching new messages. Messages already passed to the handler can still be acknowledged
+// and are committed by Close.
+func (c *Consumer) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+	}
+
+	c.wg.Wait()
+}
+
+// Close commits acknowledged offsets and closes the reader. Stop must be called first.
+func (c *Consumer) Close(ctx context.Context) error {
+	return errors.Join(c.commit(ctx), c.reader.Close())
+}
+
+func (c *Consumer) commit(ctx context.Context) error {
+	messages := c.offsets.Committable()
+	if len(messages) == 0 {
+		return nil
+	}
+
+	if err := c.reader.CommitMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("c.reader.CommitMessages: %w", err)
+	}
+
+	c.offsets.Committed(messages)
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package consumer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type fakeReader struct {
+	messages chan kafka.Message
+
+	mu        sync.Mutex
+	committed []kafka.Message
+}
+
+func newFakeReader(messages ...kafka.Message) *fakeReader {
+	r := &fakeReader{messages: make(chan kafka.Message, len(messages))}
+	for _, message := range messages {
+		r.messages <- message
+	}
+
+	return r
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	select {
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	case message := <-r.messages:
+		return message, nil
+	}
+}
+
+func (r *fakeReader) CommitMessages(_ context.Context, messages ...kafka.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.committed = append(r.committed, messages...)
+	return nil
+}
+
+func (r *fakeReader) Close() error {
+	return nil
+}
+
+func (r *fakeReader) Committed() []kafka.Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]kafka.Message(nil), r.committed...)
+}
+
+func subscribe(t *testing.T, r *fakeReader, commitInterval time.Duration, count int) (*Consumer, []func()) {
+	t.Helper()
+
+	c := &Consumer{reader: r, offsets: newOffsets(), commitInterval: commitInterval}
+
+	acks := make(chan func(), count)
+	c.Subscribe(context.Background(), func(_ kafka.Message, ack func()) {
+		acks <- ack
+	})
+
+	result := make([]func(), 0, count)
+	for range count {
+		select {
+		case ack := <-acks:
+			result = append(result, ack)
+		case <-time.After(time.Second):
+			t.Fatal("expected the message to be handled")
+		}
+	}
+
+	return c, result
+}
+
+func TestConsumerCommitsAckedMessagesOnClose(t *testing.T) {
+	r := newFakeReader(
+		kafka.Message{Topic: "tasks", Partition: 0, Offset: 10},
+		kafka.Message{Topic: "tasks", Partition: 0, Offset: 11},
+		kafka.Message{Topic: "tasks", Partition: 0, Offset: 12},
+	)
+
+	c, acks := subscribe(t, r, time.Hour, 3)
+	acks[0]()
+	acks[2]()
+
+	c.Stop()
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	committed := r.Committed()
+	if len(committed) != 1 || committed[0].Offset != 10 {
+		t.Fatalf("expected only offset 10 to be committed while 11 is pending, got %v", committed)
+	}
+}
+
+func TestConsumerCommitsPeriodically(t *testing.T) {
+	r := newFakeReader(kafka.Message{Topic: "tasks", Partition: 0, Offset: 5})
+
+	c, acks := subscribe(t, r, 10*time.Millisecond, 1)
+	defer c.Stop()
+
+	acks[0]()
+
+	deadline := time.After(time.Second)
+	for len(r.Committed()) == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("expected the acked message to be committed by the ticker")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestConsumerFetchBackoffIsCapped(t *testing.T) {
+	c := &Consumer{backoff: fetchBackoff, maxBackoff: maxFetchBackoff}
+
+	for failures, expected := range map[int]time.Duration{
+		1:   100 * time.Millisecond,
+		2:   200 * time.Millisecond,
+		4:   800 * time.Millisecond,
+		7:   6400 * time.Millisecond,
+		8:   maxFetchBackoff,
+		100: maxFetchBackoff,
+	} {
+		if got := c.fetchBackoff(failures); got != expected {
+			t.Fatalf("expected backoff %v after %d failures, got %v", expected, failures, got)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package consumer
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/segmentio/kafka-go"
+)
+
+var lag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "analytics",
+	Subsystem: "kafka_consumer",
+	Name:      "lag",
+	Help:      "Messages left in the partition after the last fetched one by topic and partition.",
+}, []string{"topic", "partition"})
+
+func observeLag(message kafka.Message) {
+	lag.WithLabelValues(message.Topic, strconv.Itoa(message.Partition)).Set(float64(max(message.HighWaterMark-message.Offset-1, 0)))
+}
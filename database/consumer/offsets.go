@@ -0,0 +1,101 @@
+package consumer
+
+import (
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// offsets tracks fetched messages per partition. A message can be committed only when it and all messages fetched
+// before it from the same partition are acknowledged, so a crash never skips an unhandled message.
+type offsets struct {
+	mu         sync.Mutex
+	partitions map[partitionKey]*partitionOffsets
+}
+
+type partitionKey struct {
+	topic     string
+	partition int
+}
+
+type partitionOffsets struct {
+	fetched   []int64
+	acked     map[int64]struct{}
+	completed int64
+	committed int64
+}
+
+func newOffsets() *offsets {
+	return &offsets{
+		partitions: make(map[partitionKey]*partitionOffsets),
+	}
+}
+
+func (o *offsets) Fetched(message kafka.Message) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	key := partitionKey{topic: message.Topic, partition: message.Partition}
+
+	p, ok := o.partitions[key]
+	if !ok {
+		p = &partitionOffsets{
+			acked:     make(map[int64]struct{}),
+			completed: -1,
+			committed: -1,
+		}
+		o.partitions[key] = p
+	}
+
+	p.fetched = append(p.fetched, message.Offset)
+}
+
+func (o *offsets) Acked(message kafka.Message) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	p, ok := o.partitions[partitionKey{topic: message.Topic, partition: message.Partition}]
+	if !ok {
+		return
+	}
+
+	p.acked[message.Offset] = struct{}{}
+
+	for len(p.fetched) > 0 {
+		offset := p.fetched[0]
+		if _, ok = p.acked[offset]; !ok {
+			break
+		}
+
+		delete(p.acked, offset)
+		p.fetched = p.fetched[1:]
+		p.completed = offset
+	}
+}
+
+// Committable returns the last completed message of every partition that has progressed since the last commit.
+func (o *offsets) Committable() []kafka.Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var messages []kafka.Message
+	for key, p := range o.partitions {
+		if p.completed > p.committed {
+			messages = append(messages, kafka.Message{Topic: key.topic, Partition: key.partition, Offset: p.completed})
+		}
+	}
+
+	return messages
+}
+
+func (o *offsets) Committed(messages []kafka.Message) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, message := range messages {
+		p, ok := o.partitions[partitionKey{topic: message.Topic, partition: message.Partition}]
+		if ok && message.Offset > p.committed {
+			p.committed = message.Offset
+		}
+	}
+}
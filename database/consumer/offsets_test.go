@@ -0,0 +1,41 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestOffsetsCommitOnlyContiguousAcks(t *testing.T) {
+	o := newOffsets()
+
+	messages := make([]kafka.Message, 0, 4)
+	for offset := int64(10); offset < 14; offset++ {
+		message := kafka.Message{Topic: "tasks", Partition: 1, Offset: offset}
+		messages = append(messages, message)
+		o.Fetched(message)
+	}
+
+	o.Acked(messages[1])
+	o.Acked(messages[2])
+	if committable := o.Committable(); len(committable) != 0 {
+		t.Fatalf("expected nothing to commit while offset 10 is pending, got %v", committable)
+	}
+
+	o.Acked(messages[0])
+	committable := o.Committable()
+	if len(committable) != 1 || committable[0].Offset != 12 {
+		t.Fatalf("expected offset 12 to be committable, got %v", committable)
+	}
+
+	o.Committed(committable)
+	if committable = o.Committable(); len(committable) != 0 {
+		t.Fatalf("expected nothing to commit after commit, got %v", committable)
+	}
+
+	o.Acked(messages[3])
+	committable = o.Committable()
+	if len(committable) != 1 || committable[0].Offset != 13 {
+		t.Fatalf("expected offset 13 to be committable, got %v", committable)
+	}
+}
@@ -0,0 +1,71 @@
+package erasure
+
+import (
+	"analytics-service/service/erasure"
+	"encoding/json"
+	"fmt"
+)
+
+func MapRequestToDB(r erasure.Request) (Request, error) {
+	tenants, err := MapTenantsToDB(r.Tenants)
+	if err != nil {
+		return Request{}, err
+	}
+
+	return Request{
+		ID:           r.ID,
+		SubscriberID: r.SubscriberID,
+		Reason:       r.Reason,
+		RequestedBy:  r.RequestedBy,
+		Tenants:      tenants,
+		Status:       string(r.Status),
+		Anomalies:    r.Anomalies,
+		CreatedAt:    r.CreatedAt,
+	}, nil
+}
+
+func MapRequestFromDB(r Request) (erasure.Request, error) {
+	var tenants []string
+	if err := json.Unmarshal([]byte(r.Tenants), &tenants); err != nil {
+		return erasure.Request{}, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return erasure.Request{
+		ID:           r.ID,
+		SubscriberID: r.SubscriberID,
+		Reason:       r.Reason,
+		RequestedBy:  r.RequestedBy,
+		Tenants:      tenants,
+		Status:       erasure.RequestStatus(r.Status),
+		Anomalies:    r.Anomalies,
+		CreatedAt:    r.CreatedAt,
+	}, nil
+}
+
+func MapRequestSliceFromDB(requests []Request) ([]erasure.Request, error) {
+	result := make([]erasure.Request, 0, len(requests))
+	for _, r := range requests {
+		request, err := MapRequestFromDB(r)
+		if err != nil {
+			return nil, fmt.Errorf("request %d: %w", r.ID, err)
+		}
+
+		result = append(result, request)
+	}
+
+	return result, nil
+}
+
+// MapTenantsToDB encodes tenants as a jsonb array, nil is encoded as an empty one.
+func MapTenantsToDB(tenants []string) (string, error) {
+	if tenants == nil {
+		tenants = []string{}
+	}
+
+	raw, err := json.Marshal(tenants)
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return string(raw), nil
+}
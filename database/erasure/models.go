@@ -0,0 +1,14 @@
+package erasure
+
+import "time"
+
+type Request struct {
+	ID           int       `db:"id"`
+	SubscriberID int       `db:"subscriber_id"`
+	Reason       string    `db:"reason"`
+	RequestedBy  int       `db:"requested_by"`
+	Tenants      string    `db:"tenants"`
+	Status       string    `db:"status"`
+	Anomalies    int       `db:"anomalies"`
+	CreatedAt    time.Time `db:"created_at"`
+}
@@ -0,0 +1,168 @@
+package erasure
+
+import (
+	"analytics-service/service/auth"
+	"analytics-service/service/erasure"
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/jmoiron/sqlx"
+	"github.com/sunshineOfficial/golib/db"
+	"github.com/sunshineOfficial/golib/pagination"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("analytics-service/database/erasure")
+
+var (
+	//go:embed sql/add_request.sql
+	addRequestSQL string
+
+	//go:embed sql/anonymize_anomalies.sql
+	anonymizeAnomaliesSQL string
+
+	//go:embed sql/anonymize_finished_tasks.sql
+	anonymizeFinishedTasksSQL string
+
+	//go:embed sql/get_object_ids.sql
+	getObjectIDsSQL string
+
+	//go:embed sql/get_replay_tables.sql
+	getReplayTablesSQL string
+
+	//go:embed sql/get_requests.sql
+	getRequestsSQL string
+
+	//go:embed sql/update_request.sql
+	updateRequestSQL string
+)
+
+type Repository struct {
+	postgres   *sqlx.DB
+	clickhouse driver.Conn
+}
+
+func NewRepository(postgres *sqlx.DB, clickhouse driver.Conn) *Repository {
+	return &Repository{
+		postgres:   postgres,
+		clickhouse: clickhouse,
+	}
+}
+
+// GetObjectIDs returns the objects of the subscriber's finished tasks in the scope tenants.
+func (r *Repository) GetObjectIDs(ctx context.Context, scope auth.TenantScope, subscriberID int) ([]int, error) {
+	ctx, span := tracer.Start(ctx, "erasure.Repository.GetObjectIDs")
+	defer span.End()
+
+	var objectIDs []int64
+	if err := r.clickhouse.Select(ctx, &objectIDs, getObjectIDsSQL, subscriberID, scope.All, scope.Tenants); err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	ids := make([]int, 0, len(objectIDs))
+	for _, id := range objectIDs {
+		ids = append(ids, int(id))
+	}
+
+	return ids, nil
+}
+
+// AnonymizeFinishedTasks starts mutations of finished_tasks and of the replay tables, which are copies of it, and returns
+// without waiting for the parts to be rewritten.
+func (r *Repository) AnonymizeFinishedTasks(ctx context.Context, scope auth.TenantScope, subscriberID int) error {
+	ctx, span := tracer.Start(ctx, "erasure.Repository.AnonymizeFinishedTasks")
+	defer span.End()
+
+	var replayTables []string
+	if err := r.clickhouse.Select(ctx, &replayTables, getReplayTablesSQL); err != nil {
+		return fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	for _, table := range append([]string{"finished_tasks"}, replayTables...) {
+		query := strings.ReplaceAll(anonymizeFinishedTasksSQL, "{table}", table)
+		if err := r.clickhouse.Exec(ctx, query, subscriberID, scope.All, scope.Tenants); err != nil {
+			return fmt.Errorf("r.clickhouse.Exec: table %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) AnonymizeAnomalies(ctx context.Context, scope auth.TenantScope, subscriberID int) (int, error) {
+	ctx, span := tracer.Start(ctx, "erasure.Repository.AnonymizeAnomalies")
+	defer span.End()
+
+	result, err := r.postgres.ExecContext(ctx, anonymizeAnomaliesSQL, subscriberID, scope.All, scope.Tenants)
+	if err != nil {
+		return 0, fmt.Errorf("r.postgres.ExecContext: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	return int(affected), nil
+}
+
+func (r *Repository) AddRequest(ctx context.Context, request erasure.Request) (erasure.Request, error) {
+	ctx, span := tracer.Start(ctx, "erasure.Repository.AddRequest")
+	defer span.End()
+
+	dbRequest, err := MapRequestToDB(request)
+	if err != nil {
+		return erasure.Request{}, fmt.Errorf("MapRequestToDB: %w", err)
+	}
+
+	if err = db.NamedGet(r.postgres, &dbRequest, addRequestSQL, dbRequest); err != nil {
+		return erasure.Request{}, fmt.Errorf("db.NamedGet: %w", err)
+	}
+
+	newRequest, err := MapRequestFromDB(dbRequest)
+	if err != nil {
+		return erasure.Request{}, fmt.Errorf("MapRequestFromDB: %w", err)
+	}
+
+	return newRequest, nil
+}
+
+func (r *Repository) UpdateRequest(ctx context.Context, request erasure.Request) error {
+	ctx, span := tracer.Start(ctx, "erasure.Repository.UpdateRequest")
+	defer span.End()
+
+	dbRequest, err := MapRequestToDB(request)
+	if err != nil {
+		return fmt.Errorf("MapRequestToDB: %w", err)
+	}
+
+	if _, err = r.postgres.NamedExecContext(ctx, updateRequestSQL, dbRequest); err != nil {
+		return fmt.Errorf("r.postgres.NamedExecContext: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetRequests(ctx context.Context, scope auth.TenantScope, page pagination.Pagination) ([]erasure.Request, error) {
+	ctx, span := tracer.Start(ctx, "erasure.Repository.GetRequests")
+	defer span.End()
+
+	tenants, err := MapTenantsToDB(scope.Tenants)
+	if err != nil {
+		return nil, fmt.Errorf("MapTenantsToDB: %w", err)
+	}
+
+	var dbRequests []Request
+	if err = r.postgres.SelectContext(ctx, &dbRequests, getRequestsSQL, page.LimitArg(), page.Offset, scope.All, tenants); err != nil {
+		return nil, fmt.Errorf("r.postgres.SelectContext: %w", err)
+	}
+
+	requests, err := MapRequestSliceFromDB(dbRequests)
+	if err != nil {
+		return nil, fmt.Errorf("MapRequestSliceFromDB: %w", err)
+	}
+
+	return requests, nil
+}
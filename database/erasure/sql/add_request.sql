@@ -0,0 +1,3 @@
+insert into erasure_requests (subscriber_id, reason, requested_by, tenants, status, anomalies)
+values (:subscriber_id, :reason, :requested_by, cast(:tenants as jsonb), :status, :anomalies)
+returning id, subscriber_id, reason, requested_by, tenants::text as tenants, status, anomalies, created_at;
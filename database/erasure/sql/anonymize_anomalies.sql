@@ -0,0 +1,6 @@
+update anomalies
+set subscriber_account_number = '',
+    object_address            = '',
+    updated_at                = now()
+where subscriber_id = $1
+  and ($2 or tenant = any ($3));
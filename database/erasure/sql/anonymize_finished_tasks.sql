@@ -0,0 +1,20 @@
+-- Expressions read the values before the update, so the district of an unparsed address survives it.
+alter table {table}
+    update subscriber_account_number = '',
+           subscriber_surname = '',
+           subscriber_name = '',
+           subscriber_patronymic = '',
+           subscriber_phone_number = '',
+           subscriber_email = '',
+           subscriber_inn = '',
+           subscriber_birth_date = toDate(0),
+           contract_number = '',
+           contract_sign_date = '',
+           object_district = if(empty(object_district) and empty(object_street),
+                                replaceRegexpOne(object_address, ',.*$', ''), object_district),
+           object_address = '',
+           object_street = '',
+           object_house = '',
+           object_flat = ''
+    where subscriber_id = $1
+      and ($2 or has($3, tenant));
@@ -0,0 +1,4 @@
+select distinct object_id
+from finished_tasks
+where subscriber_id = $1
+  and ($2 or has($3, tenant));
@@ -0,0 +1,4 @@
+select name
+from system.tables
+where database = currentDatabase()
+  and name like 'finished\_tasks\_replay\_%';
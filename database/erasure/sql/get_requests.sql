@@ -0,0 +1,5 @@
+select id, subscriber_id, reason, requested_by, tenants::text as tenants, status, anomalies, created_at
+from erasure_requests
+where $3 or (tenants <> '[]' and tenants <@ cast($4 as jsonb))
+order by id desc
+limit $1 offset $2;
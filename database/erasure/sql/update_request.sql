@@ -0,0 +1,4 @@
+update erasure_requests
+set status    = :status,
+    anomalies = :anomalies
+where id = :id;
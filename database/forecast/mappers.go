@@ -0,0 +1,22 @@
+package forecast
+
+import "analytics-service/service/forecast"
+
+func MapTasksDailyFromDB(t TasksDaily) forecast.TasksDaily {
+	return forecast.TasksDaily{
+		Day:                         t.Day,
+		LimitationCount:             int(t.LimitationCount),
+		ResumptionCount:             int(t.ResumptionCount),
+		VerificationCount:           int(t.VerificationCount),
+		UnauthorizedConnectionCount: int(t.UnauthorizedConnectionCount),
+	}
+}
+
+func MapTasksDailySliceFromDB(days []TasksDaily) []forecast.TasksDaily {
+	result := make([]forecast.TasksDaily, 0, len(days))
+	for _, d := range days {
+		result = append(result, MapTasksDailyFromDB(d))
+	}
+
+	return result
+}
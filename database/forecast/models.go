@@ -0,0 +1,11 @@
+package forecast
+
+import "time"
+
+type TasksDaily struct {
+	Day                         time.Time `ch:"day"`
+	LimitationCount             uint64    `ch:"limitation_count"`
+	ResumptionCount             uint64    `ch:"resumption_count"`
+	VerificationCount           uint64    `ch:"verification_count"`
+	UnauthorizedConnectionCount uint64    `ch:"unauthorized_connection_count"`
+}
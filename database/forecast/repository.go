@@ -0,0 +1,42 @@
+package forecast
+
+import (
+	"analytics-service/service/auth"
+	"analytics-service/service/forecast"
+	"context"
+	_ "embed"
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("analytics-service/database/forecast")
+
+var (
+	//go:embed sql/get_tasks_daily.sql
+	getTasksDailySQL string
+)
+
+type Repository struct {
+	clickhouse driver.Conn
+}
+
+func NewRepository(clickhouse driver.Conn) *Repository {
+	return &Repository{
+		clickhouse: clickhouse,
+	}
+}
+
+func (r *Repository) GetTasksDaily(ctx context.Context, scope auth.TenantScope, from, to time.Time) ([]forecast.TasksDaily, error) {
+	ctx, span := tracer.Start(ctx, "forecast.Repository.GetTasksDaily")
+	defer span.End()
+
+	var days []TasksDaily
+	if err := r.clickhouse.Select(ctx, &days, getTasksDailySQL, from, to, scope.All, scope.Tenants); err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	return MapTasksDailySliceFromDB(days), nil
+}
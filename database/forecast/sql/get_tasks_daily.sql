@@ -0,0 +1,11 @@
+select day,
+       sum(limitation_count)              as limitation_count,
+       sum(resumption_count)              as resumption_count,
+       sum(verification_count)            as verification_count,
+       sum(unauthorized_connection_count) as unauthorized_connection_count
+from v_bi_tasks_daily
+where $1 <= day
+  and day < $2
+  and ($3 or has($4, tenant))
+group by day
+order by day;
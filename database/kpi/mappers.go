@@ -0,0 +1,60 @@
+package kpi
+
+import "analytics-service/service/kpi"
+
+func MapTasksDailyFromDB(t TasksDaily) kpi.TasksDaily {
+	return kpi.TasksDaily{
+		Tenant:                      t.Tenant,
+		TasksCount:                  int(t.TasksCount),
+		LimitationCount:             int(t.LimitationCount),
+		ResumptionCount:             int(t.ResumptionCount),
+		VerificationCount:           int(t.VerificationCount),
+		UnauthorizedConnectionCount: int(t.UnauthorizedConnectionCount),
+		ViolationsDetectedCount:     int(t.ViolationsDetectedCount),
+		AvgDurationMinutes:          t.AvgDurationMinutes,
+	}
+}
+
+func MapTasksDailySliceFromDB(days []TasksDaily) []kpi.TasksDaily {
+	result := make([]kpi.TasksDaily, 0, len(days))
+	for _, t := range days {
+		result = append(result, MapTasksDailyFromDB(t))
+	}
+
+	return result
+}
+
+func MapBrigadeDailyFromDB(b BrigadeDaily) kpi.BrigadeDaily {
+	return kpi.BrigadeDaily{
+		Tenant:                  b.Tenant,
+		BrigadeID:               int(b.BrigadeID),
+		TasksCount:              int(b.TasksCount),
+		AvgDurationMinutes:      b.AvgDurationMinutes,
+		ViolationsDetectedCount: int(b.ViolationsDetectedCount),
+	}
+}
+
+func MapBrigadeDailySliceFromDB(brigades []BrigadeDaily) []kpi.BrigadeDaily {
+	result := make([]kpi.BrigadeDaily, 0, len(brigades))
+	for _, b := range brigades {
+		result = append(result, MapBrigadeDailyFromDB(b))
+	}
+
+	return result
+}
+
+func MapAnomaliesCountFromDB(c AnomaliesCount) kpi.AnomaliesCount {
+	return kpi.AnomaliesCount{
+		Tenant:         c.Tenant,
+		AnomaliesCount: c.AnomaliesCount,
+	}
+}
+
+func MapAnomaliesCountSliceFromDB(counts []AnomaliesCount) []kpi.AnomaliesCount {
+	result := make([]kpi.AnomaliesCount, 0, len(counts))
+	for _, c := range counts {
+		result = append(result, MapAnomaliesCountFromDB(c))
+	}
+
+	return result
+}
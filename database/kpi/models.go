@@ -0,0 +1,25 @@
+package kpi
+
+type TasksDaily struct {
+	Tenant                      string  `ch:"tenant"`
+	TasksCount                  uint64  `ch:"tasks_count"`
+	LimitationCount             uint64  `ch:"limitation_count"`
+	ResumptionCount             uint64  `ch:"resumption_count"`
+	VerificationCount           uint64  `ch:"verification_count"`
+	UnauthorizedConnectionCount uint64  `ch:"unauthorized_connection_count"`
+	ViolationsDetectedCount     uint64  `ch:"violations_detected_count"`
+	AvgDurationMinutes          float64 `ch:"avg_duration_minutes"`
+}
+
+type BrigadeDaily struct {
+	Tenant                  string  `ch:"tenant"`
+	BrigadeID               int64   `ch:"brigade_id"`
+	TasksCount              uint64  `ch:"tasks_count"`
+	AvgDurationMinutes      float64 `ch:"avg_duration_minutes"`
+	ViolationsDetectedCount uint64  `ch:"violations_detected_count"`
+}
+
+type AnomaliesCount struct {
+	Tenant         string `db:"tenant"`
+	AnomaliesCount int    `db:"anomalies_count"`
+}
@@ -0,0 +1,73 @@
+package kpi
+
+import (
+	"analytics-service/service/kpi"
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("analytics-service/database/kpi")
+
+var (
+	//go:embed sql/get_anomalies_this_month.sql
+	getAnomaliesThisMonthSQL string
+
+	//go:embed sql/get_brigades_today.sql
+	getBrigadesTodaySQL string
+
+	//go:embed sql/get_tasks_today.sql
+	getTasksTodaySQL string
+)
+
+type Repository struct {
+	postgres   *sqlx.DB
+	clickhouse driver.Conn
+}
+
+func NewRepository(postgres *sqlx.DB, clickhouse driver.Conn) *Repository {
+	return &Repository{
+		postgres:   postgres,
+		clickhouse: clickhouse,
+	}
+}
+
+func (r *Repository) GetTasksToday(ctx context.Context) ([]kpi.TasksDaily, error) {
+	ctx, span := tracer.Start(ctx, "kpi.Repository.GetTasksToday")
+	defer span.End()
+
+	var days []TasksDaily
+	if err := r.clickhouse.Select(ctx, &days, getTasksTodaySQL); err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	return MapTasksDailySliceFromDB(days), nil
+}
+
+func (r *Repository) GetBrigadesToday(ctx context.Context) ([]kpi.BrigadeDaily, error) {
+	ctx, span := tracer.Start(ctx, "kpi.Repository.GetBrigadesToday")
+	defer span.End()
+
+	var brigades []BrigadeDaily
+	if err := r.clickhouse.Select(ctx, &brigades, getBrigadesTodaySQL); err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Select: %w", err)
+	}
+
+	return MapBrigadeDailySliceFromDB(brigades), nil
+}
+
+func (r *Repository) GetAnomaliesThisMonth(ctx context.Context) ([]kpi.AnomaliesCount, error) {
+	ctx, span := tracer.Start(ctx, "kpi.Repository.GetAnomaliesThisMonth")
+	defer span.End()
+
+	var counts []AnomaliesCount
+	if err := r.postgres.SelectContext(ctx, &counts, getAnomaliesThisMonthSQL); err != nil {
+		return nil, fmt.Errorf("r.postgres.SelectContext: %w", err)
+	}
+
+	return MapAnomaliesCountSliceFromDB(counts), nil
+}
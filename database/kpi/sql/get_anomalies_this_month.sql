@@ -0,0 +1,5 @@
+select tenant, count(*) as anomalies_count
+from anomalies
+where month = date_trunc('month', now() at time zone 'Europe/Moscow')::date
+  and status <> 'dismissed'
+group by tenant;
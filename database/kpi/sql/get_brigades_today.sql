@@ -0,0 +1,8 @@
+select tenant,
+       brigade_id,
+       tasks_count,
+       avg_duration_minutes,
+       violations_detected_count
+from v_bi_brigade_performance
+where day = toDate(now(), 'UTC')
+settings max_threads = 2;
@@ -0,0 +1,11 @@
+select tenant,
+       tasks_count,
+       limitation_count,
+       resumption_count,
+       verification_count,
+       unauthorized_connection_count,
+       violations_detected_count,
+       avg_duration_minutes
+from v_bi_tasks_daily
+where day = toDate(now(), 'UTC')
+settings max_threads = 2;
@@ -0,0 +1,19 @@
+-- +goose Up
+create table if not exists raw_events
+(
+    topic       LowCardinality(String),
+    partition   Int32,
+    offset      Int64,
+    key         String codec(ZSTD(3)),
+    headers     Map(LowCardinality(String), String) codec(ZSTD(3)),
+    value       String codec(ZSTD(3)),
+    received_at DateTime64(3, 'UTC') codec(Delta, ZSTD)
+)
+    engine = MergeTree()
+        order by (topic, received_at, partition, offset)
+        partition by toYYYYMM(received_at)
+        ttl toDateTime(received_at) + interval 180 day delete
+        settings index_granularity = 8192, merge_with_ttl_timeout = 86400;
+
+-- +goose Down
+drop table if exists raw_events;
@@ -0,0 +1,17 @@
+-- +goose Up
+create view if not exists v_bi_inspector_daily as
+select
+    toDate(finished_at) as day,
+    inspector.1 as inspector_id,
+    argMax(concat(inspector.2, ' ', inspector.3, ' ', inspector.4), inspector.7) as inspector_full_name,
+    brigade_id,
+    count() as tasks_count,
+    countIf(inspection_is_violation_detected) as violations_detected_count,
+    sum(dateDiff('minute', started_at, finished_at)) as total_duration_minutes,
+    round(avg(dateDiff('minute', started_at, finished_at)), 2) as avg_duration_minutes
+from finished_tasks
+array join brigade_inspectors as inspector
+group by day, inspector_id, brigade_id;
+
+-- +goose Down
+drop view if exists v_bi_inspector_daily;
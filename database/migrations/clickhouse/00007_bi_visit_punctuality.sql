@@ -0,0 +1,17 @@
+-- +goose Up
+create view if not exists v_bi_visit_punctuality as
+select
+    toDate(plan_visit_at) as day,
+    task_id,
+    brigade_id,
+    arrayMap(i -> i.1, brigade_inspectors) as inspector_ids,
+    arrayMap(i -> concat(i.2, ' ', i.3, ' ', i.4), brigade_inspectors) as inspector_full_names,
+    replaceRegexpOne(object_address, ',.*$', '') as district_name,
+    assumeNotNull(plan_visit_at) as plan_visit_at,
+    started_at,
+    dateDiff('minute', assumeNotNull(plan_visit_at), started_at) as delay_minutes
+from finished_tasks
+where plan_visit_at is not null;
+
+-- +goose Down
+drop view if exists v_bi_visit_punctuality;
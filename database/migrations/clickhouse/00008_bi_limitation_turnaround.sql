@@ -0,0 +1,42 @@
+-- +goose Up
+create view if not exists v_bi_limitation_turnaround as
+select
+    l.object_id as object_id,
+    l.object_address as object_address,
+    l.subscriber_account_number as subscriber_account_number,
+    l.task_id as limitation_task_id,
+    l.finished_at as limited_at,
+    if(r.task_id = 0, null, r.task_id) as resumption_task_id,
+    if(r.task_id = 0, null, r.finished_at) as resumed_at,
+    if(r.task_id = 0, null, dateDiff('minute', l.finished_at, r.finished_at)) as disconnected_minutes
+from
+(
+    select object_id, object_address, subscriber_account_number, task_id, finished_at
+    from finished_tasks
+    where inspection_type = 'limitation'
+      and inspection_resolution = 'limited'
+) as l
+asof left join
+(
+    select object_id, task_id, finished_at
+    from finished_tasks
+    where inspection_type = 'resumption'
+      and inspection_resolution = 'resumed'
+) as r
+on l.object_id = r.object_id and l.finished_at < r.finished_at;
+
+create view if not exists v_bi_object_access_refusals as
+select
+    toDate(finished_at) as day,
+    object_id,
+    argMax(object_address, finished_at) as object_address,
+    countIf(inspection_type = 'limitation') as limitation_refusals_count,
+    countIf(inspection_type = 'resumption') as resumption_refusals_count
+from finished_tasks
+where (inspection_type = 'limitation' and inspection_resolution != 'limited')
+   or (inspection_type = 'resumption' and inspection_resolution != 'resumed')
+group by day, object_id;
+
+-- +goose Down
+drop view if exists v_bi_object_access_refusals;
+drop view if exists v_bi_limitation_turnaround;
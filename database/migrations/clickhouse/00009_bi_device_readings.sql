@@ -0,0 +1,19 @@
+-- +goose Up
+create view if not exists v_bi_device_readings as
+select
+    device_reading.2 as device_id,
+    device_reading.1 as inspected_device_id,
+    device_reading.5 as read_at,
+    device_reading.3 as value,
+    device_reading.4 as consumption_kwh,
+    task_id,
+    finished_at,
+    object_id,
+    object_address,
+    subscriber_id,
+    subscriber_account_number
+from finished_tasks
+array join inspected_devices as device_reading;
+
+-- +goose Down
+drop view if exists v_bi_device_readings;
@@ -0,0 +1,62 @@
+-- +goose Up
+alter table finished_tasks
+    add column if not exists object_devices Array(Tuple(
+        id Int64,
+        type String,
+        number String,
+        place_type Int8,
+        place_description String,
+        seals Array(Tuple(
+            id Int64,
+            number String,
+            place String
+        ))
+    )) default [],
+    add column if not exists contract_number String default '',
+    add column if not exists contract_sign_date String default '';
+
+drop view if exists v_bi_device_readings;
+
+create view if not exists v_bi_device_readings as
+select
+    device_reading.2 as device_id,
+    device_reading.1 as inspected_device_id,
+    device_reading.5 as read_at,
+    device_reading.3 as value,
+    device_reading.4 as consumption_kwh,
+    arrayFirst(d -> d.1 = device_reading.2, object_devices) as object_device,
+    object_device.2 as device_type,
+    object_device.3 as device_number,
+    arrayMap(s -> s.2, object_device.6) as seal_numbers,
+    task_id,
+    finished_at,
+    object_id,
+    object_address,
+    subscriber_id,
+    subscriber_account_number
+from finished_tasks
+array join inspected_devices as device_reading;
+
+-- +goose Down
+drop view if exists v_bi_device_readings;
+
+create view if not exists v_bi_device_readings as
+select
+    device_reading.2 as device_id,
+    device_reading.1 as inspected_device_id,
+    device_reading.5 as read_at,
+    device_reading.3 as value,
+    device_reading.4 as consumption_kwh,
+    task_id,
+    finished_at,
+    object_id,
+    object_address,
+    subscriber_id,
+    subscriber_account_number
+from finished_tasks
+array join inspected_devices as device_reading;
+
+alter table finished_tasks
+    drop column if exists contract_sign_date,
+    drop column if exists contract_number,
+    drop column if exists object_devices;
@@ -0,0 +1,106 @@
+-- +goose Up
+alter table finished_tasks
+    add column if not exists object_region String default '',
+    add column if not exists object_city String default '',
+    add column if not exists object_district String default '',
+    add column if not exists object_street String default '',
+    add column if not exists object_house String default '',
+    add column if not exists object_flat String default '';
+
+-- Tasks stored before addresses were parsed have no parts, their district is still the first part of the address.
+create or replace view v_bi_consumption_monthly as
+select
+    toStartOfMonth(finished_at) as month,
+    subscriber_id,
+    subscriber_account_number,
+    concat(subscriber_surname, ' ', subscriber_name, ' ', subscriber_patronymic) as subscriber_full_name,
+    object_id,
+    object_address,
+    if(
+        empty(object_district) and empty(object_street),
+        replaceRegexpOne(object_address, ',.*$', ''),
+        object_district
+    ) as district_name,
+    groupUniqArray(toString(device_reading.1)) as inspected_device_ids,
+    groupUniqArray(toString(device_reading.2)) as device_ids,
+    sum(toDecimal64(device_reading.4, 2)) as monthly_consumption_kwh,
+    count() as readings_count,
+    max(finished_at) as last_reading_at
+from finished_tasks
+array join inspected_devices as device_reading
+where toDecimal64(device_reading.4, 2) > 0
+group by
+    month,
+    subscriber_id,
+    subscriber_account_number,
+    subscriber_full_name,
+    object_id,
+    object_address,
+    district_name;
+
+create or replace view v_bi_visit_punctuality as
+select
+    toDate(plan_visit_at) as day,
+    task_id,
+    brigade_id,
+    arrayMap(i -> i.1, brigade_inspectors) as inspector_ids,
+    arrayMap(i -> concat(i.2, ' ', i.3, ' ', i.4), brigade_inspectors) as inspector_full_names,
+    if(
+        empty(object_district) and empty(object_street),
+        replaceRegexpOne(object_address, ',.*$', ''),
+        object_district
+    ) as district_name,
+    assumeNotNull(plan_visit_at) as plan_visit_at,
+    started_at,
+    dateDiff('minute', assumeNotNull(plan_visit_at), started_at) as delay_minutes
+from finished_tasks
+where plan_visit_at is not null;
+
+-- +goose Down
+create or replace view v_bi_consumption_monthly as
+select
+    toStartOfMonth(finished_at) as month,
+    subscriber_id,
+    subscriber_account_number,
+    concat(subscriber_surname, ' ', subscriber_name, ' ', subscriber_patronymic) as subscriber_full_name,
+    object_id,
+    object_address,
+    replaceRegexpOne(object_address, ',.*$', '') as district_name,
+    groupUniqArray(toString(device_reading.1)) as inspected_device_ids,
+    groupUniqArray(toString(device_reading.2)) as device_ids,
+    sum(toDecimal64(device_reading.4, 2)) as monthly_consumption_kwh,
+    count() as readings_count,
+    max(finished_at) as last_reading_at
+from finished_tasks
+array join inspected_devices as device_reading
+where toDecimal64(device_reading.4, 2) > 0
+group by
+    month,
+    subscriber_id,
+    subscriber_account_number,
+    subscriber_full_name,
+    object_id,
+    object_address,
+    district_name;
+
+create or replace view v_bi_visit_punctuality as
+select
+    toDate(plan_visit_at) as day,
+    task_id,
+    brigade_id,
+    arrayMap(i -> i.1, brigade_inspectors) as inspector_ids,
+    arrayMap(i -> concat(i.2, ' ', i.3, ' ', i.4), brigade_inspectors) as inspector_full_names,
+    replaceRegexpOne(object_address, ',.*$', '') as district_name,
+    assumeNotNull(plan_visit_at) as plan_visit_at,
+    started_at,
+    dateDiff('minute', assumeNotNull(plan_visit_at), started_at) as delay_minutes
+from finished_tasks
+where plan_visit_at is not null;
+
+alter table finished_tasks
+    drop column if exists object_flat,
+    drop column if exists object_house,
+    drop column if exists object_street,
+    drop column if exists object_district,
+    drop column if exists object_city,
+    drop column if exists object_region;
@@ -0,0 +1,679 @@
+-- +goose Up
+alter table finished_tasks
+    add column if not exists tenant LowCardinality(String) default '';
+
+-- Aggregated views are split by tenant, readers sum them up over the tenants they may access.
+create or replace view v_bi_tasks_daily as
+select
+    toDate(finished_at) as day,
+    tenant,
+    count() as tasks_count,
+    countIf(inspection_type = 'limitation') as limitation_count,
+    countIf(inspection_type = 'resumption') as resumption_count,
+    countIf(inspection_type = 'verification') as verification_count,
+    countIf(inspection_type = 'unauthorized_connection') as unauthorized_connection_count,
+    countIf(inspection_is_violation_detected) as violations_detected_count,
+    countIf(inspection_is_unauthorized_consumers) as unauthorized_consumers_count,
+    round(avg(dateDiff('minute', started_at, finished_at)), 2) as avg_duration_minutes
+from finished_tasks
+group by day, tenant;
+
+create or replace view v_bi_brigade_performance as
+select
+    toDate(finished_at) as day,
+    tenant,
+    brigade_id,
+    count() as tasks_count,
+    round(avg(dateDiff('minute', started_at, finished_at)), 2) as avg_duration_minutes,
+    countIf(inspection_type = 'limitation' and inspection_resolution = 'limited') as successful_limitations_count,
+    countIf(inspection_type = 'resumption' and inspection_resolution = 'resumed') as successful_resumptions_count,
+    countIf(inspection_is_violation_detected) as violations_detected_count
+from finished_tasks
+group by day, tenant, brigade_id;
+
+create or replace view v_bi_inspection_results as
+select
+    day,
+    tenant,
+    inspection_type_ru,
+    inspection_result_ru,
+    subscriber_status_ru,
+    tasks_count,
+    round(tasks_count / sum(tasks_count) over (partition by tenant, day), 6) as day_tasks_share_ratio
+from
+(
+    select
+        toDate(finished_at) as day,
+        tenant,
+        multiIf(
+            inspection_type = 'limitation', 'Ограничение',
+            inspection_type = 'resumption', 'Возобновление',
+            inspection_type = 'verification', 'Контроль ограничения',
+            inspection_type = 'unauthorized_connection', 'Несанкционированное подключение',
+            'Неизвестно'
+        ) as inspection_type_ru,
+        multiIf(
+            inspection_type = 'limitation' and inspection_resolution = 'limited', 'Ограничение введено',
+            inspection_type = 'limitation', 'Недопуск',
+            inspection_type = 'resumption' and inspection_resolution = 'resumed', 'Возобновление выполнено',
+            inspection_type = 'resumption', 'Недопуск',
+            inspection_is_violation_detected, 'Нарушение выявлено',
+            'Нарушение не выявлено'
+        ) as inspection_result_ru,
+        multiIf(
+            subscriber_status = 'active', 'Активен',
+            subscriber_status = 'violator', 'Нарушитель',
+            subscriber_status = 'archived', 'Архивный',
+            'Неизвестно'
+        ) as subscriber_status_ru,
+        count() as tasks_count
+    from finished_tasks
+    group by day, tenant, inspection_type_ru, inspection_result_ru, subscriber_status_ru
+);
+
+create or replace view v_bi_subscriber_object_profile as
+select
+    subscriber_id,
+    subscriber_account_number,
+    multiIf(
+        subscriber_status = 'active', 'Активен',
+        subscriber_status = 'violator', 'Нарушитель',
+        subscriber_status = 'archived', 'Архивный',
+        'Неизвестно'
+    ) as subscriber_status_ru,
+    object_id,
+    object_address,
+    object_have_automaton,
+    if(object_have_automaton, 'Есть автомат', 'Нет автомата') as automaton_state_ru,
+    tenant,
+    last_task_day,
+    total_tasks_count,
+    violations_detected_count,
+    unauthorized_consumers_count
+from
+(
+    select
+        subscriber_id,
+        object_id,
+        argMax(subscriber_account_number, finished_at) as subscriber_account_number,
+        argMax(subscriber_status, finished_at) as subscriber_status,
+        argMax(object_address, finished_at) as object_address,
+        argMax(object_have_automaton, finished_at) as object_have_automaton,
+        argMax(tenant, finished_at) as tenant,
+        max(toDate(finished_at)) as last_task_day,
+        count() as total_tasks_count,
+        countIf(inspection_is_violation_detected) as violations_detected_count,
+        countIf(inspection_is_unauthorized_consumers) as unauthorized_consumers_count
+    from finished_tasks
+    group by subscriber_id, object_id
+);
+
+create or replace view v_bi_consumption_monthly as
+select
+    toStartOfMonth(finished_at) as month,
+    subscriber_id,
+    subscriber_account_number,
+    concat(subscriber_surname, ' ', subscriber_name, ' ', subscriber_patronymic) as subscriber_full_name,
+    object_id,
+    object_address,
+    if(
+        empty(object_district) and empty(object_street),
+        replaceRegexpOne(object_address, ',.*$', ''),
+        object_district
+    ) as district_name,
+    argMax(tenant, finished_at) as tenant,
+    groupUniqArray(toString(device_reading.1)) as inspected_device_ids,
+    groupUniqArray(toString(device_reading.2)) as device_ids,
+    sum(toDecimal64(device_reading.4, 2)) as monthly_consumption_kwh,
+    count() as readings_count,
+    max(finished_at) as last_reading_at
+from finished_tasks
+array join inspected_devices as device_reading
+where toDecimal64(device_reading.4, 2) > 0
+group by
+    month,
+    subscriber_id,
+    subscriber_account_number,
+    subscriber_full_name,
+    object_id,
+    object_address,
+    district_name;
+
+create or replace view v_bi_consumption_anomalies as
+with scored as
+(
+    select
+        month,
+        subscriber_id,
+        subscriber_account_number,
+        subscriber_full_name,
+        object_id,
+        object_address,
+        district_name,
+        tenant,
+        device_ids,
+        monthly_consumption_kwh,
+        readings_count,
+        last_reading_at,
+        subscriber_avg_consumption_kwh,
+        subscriber_months_count,
+        district_avg_consumption_kwh,
+        if(
+            subscriber_avg_consumption_kwh > 0,
+            (monthly_consumption_kwh - subscriber_avg_consumption_kwh) / subscriber_avg_consumption_kwh,
+            0
+        ) as subscriber_deviation_ratio,
+        if(
+            district_avg_consumption_kwh > 0,
+            (monthly_consumption_kwh - district_avg_consumption_kwh) / district_avg_consumption_kwh,
+            0
+        ) as district_deviation_ratio
+    from
+    (
+        select
+            month,
+            subscriber_id,
+            subscriber_account_number,
+            subscriber_full_name,
+            object_id,
+            object_address,
+            district_name,
+            tenant,
+            device_ids,
+            toFloat64(monthly_consumption_kwh) as monthly_consumption_kwh,
+            readings_count,
+            last_reading_at,
+            ifNull(
+                sum(toFloat64(monthly_consumption_kwh)) over (
+                    partition by subscriber_id, object_id
+                    order by month
+                    rows between unbounded preceding and 1 preceding
+                ) / nullIf(
+                    count() over (
+                        partition by subscriber_id, object_id
+                        order by month
+                        rows between unbounded preceding and 1 preceding
+                    ),
+                    0
+                ),
+                0
+            ) as subscriber_avg_consumption_kwh,
+            count() over (
+                partition by subscriber_id, object_id
+                order by month
+                rows between unbounded preceding and 1 preceding
+            ) as subscriber_months_count,
+            ifNull(
+                (
+                    sum(toFloat64(monthly_consumption_kwh)) over (partition by tenant, district_name, month)
+                    - toFloat64(monthly_consumption_kwh)
+                ) / nullIf(count() over (partition by tenant, district_name, month) - 1, 0),
+                0
+            ) as district_avg_consumption_kwh
+        from v_bi_consumption_monthly
+    )
+)
+select
+    month,
+    subscriber_id,
+    subscriber_account_number,
+    subscriber_full_name,
+    object_id,
+    object_address,
+    district_name,
+    tenant,
+    device_ids,
+    monthly_consumption_kwh,
+    round(subscriber_avg_consumption_kwh, 2) as subscriber_avg_consumption_kwh,
+    subscriber_months_count,
+    round(district_avg_consumption_kwh, 2) as district_avg_consumption_kwh,
+    round(subscriber_deviation_ratio * 100, 2) as subscriber_deviation_percent,
+    round(district_deviation_ratio * 100, 2) as district_deviation_percent,
+    multiIf(
+        subscriber_months_count >= 3
+            and subscriber_deviation_ratio >= 0.5,
+        'Скачок относительно истории абонента',
+        subscriber_months_count >= 3
+            and subscriber_deviation_ratio <= -0.5,
+        'Провал относительно истории абонента',
+        district_deviation_ratio >= 1.5,
+        'Выше среднего по району',
+        district_deviation_ratio <= -0.6,
+        'Ниже среднего по району',
+        'Норма'
+    ) as anomaly_reason,
+    greatest(abs(subscriber_deviation_ratio), abs(district_deviation_ratio)) as severity_score,
+    readings_count,
+    last_reading_at
+from scored
+where
+    (subscriber_months_count >= 3 and abs(subscriber_deviation_ratio) >= 0.5)
+    or district_deviation_ratio >= 1.5
+    or district_deviation_ratio <= -0.6;
+
+create or replace view v_bi_inspector_daily as
+select
+    toDate(finished_at) as day,
+    tenant,
+    inspector.1 as inspector_id,
+    argMax(concat(inspector.2, ' ', inspector.3, ' ', inspector.4), inspector.7) as inspector_full_name,
+    brigade_id,
+    count() as tasks_count,
+    countIf(inspection_is_violation_detected) as violations_detected_count,
+    sum(dateDiff('minute', started_at, finished_at)) as total_duration_minutes,
+    round(avg(dateDiff('minute', started_at, finished_at)), 2) as avg_duration_minutes
+from finished_tasks
+array join brigade_inspectors as inspector
+group by day, tenant, inspector_id, brigade_id;
+
+create or replace view v_bi_visit_punctuality as
+select
+    toDate(plan_visit_at) as day,
+    task_id,
+    brigade_id,
+    tenant,
+    arrayMap(i -> i.1, brigade_inspectors) as inspector_ids,
+    arrayMap(i -> concat(i.2, ' ', i.3, ' ', i.4), brigade_inspectors) as inspector_full_names,
+    if(
+        empty(object_district) and empty(object_street),
+        replaceRegexpOne(object_address, ',.*$', ''),
+        object_district
+    ) as district_name,
+    assumeNotNull(plan_visit_at) as plan_visit_at,
+    started_at,
+    dateDiff('minute', assumeNotNull(plan_visit_at), started_at) as delay_minutes
+from finished_tasks
+where plan_visit_at is not null;
+
+create or replace view v_bi_limitation_turnaround as
+select
+    l.object_id as object_id,
+    l.object_address as object_address,
+    l.subscriber_account_number as subscriber_account_number,
+    l.tenant as tenant,
+    l.task_id as limitation_task_id,
+    l.finished_at as limited_at,
+    if(r.task_id = 0, null, r.task_id) as resumption_task_id,
+    if(r.task_id = 0, null, r.finished_at) as resumed_at,
+    if(r.task_id = 0, null, dateDiff('minute', l.finished_at, r.finished_at)) as disconnected_minutes
+from
+(
+    select object_id, object_address, subscriber_account_number, tenant, task_id, finished_at
+    from finished_tasks
+    where inspection_type = 'limitation'
+      and inspection_resolution = 'limited'
+) as l
+asof left join
+(
+    select object_id, task_id, finished_at
+    from finished_tasks
+    where inspection_type = 'resumption'
+      and inspection_resolution = 'resumed'
+) as r
+on l.object_id = r.object_id and l.finished_at < r.finished_at;
+
+create or replace view v_bi_object_access_refusals as
+select
+    toDate(finished_at) as day,
+    tenant,
+    object_id,
+    argMax(object_address, finished_at) as object_address,
+    countIf(inspection_type = 'limitation') as limitation_refusals_count,
+    countIf(inspection_type = 'resumption') as resumption_refusals_count
+from finished_tasks
+where (inspection_type = 'limitation' and inspection_resolution != 'limited')
+   or (inspection_type = 'resumption' and inspection_resolution != 'resumed')
+group by day, tenant, object_id;
+
+create or replace view v_bi_device_readings as
+select
+    device_reading.2 as device_id,
+    device_reading.1 as inspected_device_id,
+    device_reading.5 as read_at,
+    device_reading.3 as value,
+    device_reading.4 as consumption_kwh,
+    arrayFirst(d -> d.1 = device_reading.2, object_devices) as object_device,
+    object_device.2 as device_type,
+    object_device.3 as device_number,
+    arrayMap(s -> s.2, object_device.6) as seal_numbers,
+    task_id,
+    finished_at,
+    object_id,
+    object_address,
+    subscriber_id,
+    subscriber_account_number,
+    tenant
+from finished_tasks
+array join inspected_devices as device_reading;
+
+-- +goose Down
+create or replace view v_bi_tasks_daily as
+select
+    toDate(finished_at) as day,
+    count() as tasks_count,
+    countIf(inspection_type = 'limitation') as limitation_count,
+    countIf(inspection_type = 'resumption') as resumption_count,
+    countIf(inspection_type = 'verification') as verification_count,
+    countIf(inspection_type = 'unauthorized_connection') as unauthorized_connection_count,
+    countIf(inspection_is_violation_detected) as violations_detected_count,
+    countIf(inspection_is_unauthorized_consumers) as unauthorized_consumers_count,
+    round(avg(dateDiff('minute', started_at, finished_at)), 2) as avg_duration_minutes
+from finished_tasks
+group by day;
+
+create or replace view v_bi_brigade_performance as
+select
+    toDate(finished_at) as day,
+    brigade_id,
+    count() as tasks_count,
+    round(avg(dateDiff('minute', started_at, finished_at)), 2) as avg_duration_minutes,
+    countIf(inspection_type = 'limitation' and inspection_resolution = 'limited') as successful_limitations_count,
+    countIf(inspection_type = 'resumption' and inspection_resolution = 'resumed') as successful_resumptions_count,
+    countIf(inspection_is_violation_detected) as violations_detected_count
+from finished_tasks
+group by day, brigade_id;
+
+create or replace view v_bi_inspection_results as
+select
+    day,
+    inspection_type_ru,
+    inspection_result_ru,
+    subscriber_status_ru,
+    tasks_count,
+    round(tasks_count / sum(tasks_count) over (partition by day), 6) as day_tasks_share_ratio
+from
+(
+    select
+        toDate(finished_at) as day,
+        multiIf(
+            inspection_type = 'limitation', 'Ограничение',
+            inspection_type = 'resumption', 'Возобновление',
+            inspection_type = 'verification', 'Контроль ограничения',
+            inspection_type = 'unauthorized_connection', 'Несанкционированное подключение',
+            'Неизвестно'
+        ) as inspection_type_ru,
+        multiIf(
+            inspection_type = 'limitation' and inspection_resolution = 'limited', 'Ограничение введено',
+            inspection_type = 'limitation', 'Недопуск',
+            inspection_type = 'resumption' and inspection_resolution = 'resumed', 'Возобновление выполнено',
+            inspection_type = 'resumption', 'Недопуск',
+            inspection_is_violation_detected, 'Нарушение выявлено',
+            'Нарушение не выявлено'
+        ) as inspection_result_ru,
+        multiIf(
+            subscriber_status = 'active', 'Активен',
+            subscriber_status = 'violator', 'Нарушитель',
+            subscriber_status = 'archived', 'Архивный',
+            'Неизвестно'
+        ) as subscriber_status_ru,
+        count() as tasks_count
+    from finished_tasks
+    group by day, inspection_type_ru, inspection_result_ru, subscriber_status_ru
+);
+
+create or replace view v_bi_subscriber_object_profile as
+select
+    subscriber_id,
+    subscriber_account_number,
+    multiIf(
+        subscriber_status = 'active', 'Активен',
+        subscriber_status = 'violator', 'Нарушитель',
+        subscriber_status = 'archived', 'Архивный',
+        'Неизвестно'
+    ) as subscriber_status_ru,
+    object_id,
+    object_address,
+    object_have_automaton,
+    if(object_have_automaton, 'Есть автомат', 'Нет автомата') as automaton_state_ru,
+    last_task_day,
+    total_tasks_count,
+    violations_detected_count,
+    unauthorized_consumers_count
+from
+(
+    select
+        subscriber_id,
+        object_id,
+        argMax(subscriber_account_number, finished_at) as subscriber_account_number,
+        argMax(subscriber_status, finished_at) as subscriber_status,
+        argMax(object_address, finished_at) as object_address,
+        argMax(object_have_automaton, finished_at) as object_have_automaton,
+        max(toDate(finished_at)) as last_task_day,
+        count() as total_tasks_count,
+        countIf(inspection_is_violation_detected) as violations_detected_count,
+        countIf(inspection_is_unauthorized_consumers) as unauthorized_consumers_count
+    from finished_tasks
+    group by subscriber_id, object_id
+);
+
+create or replace view v_bi_consumption_anomalies as
+with scored as
+(
+    select
+        month,
+        subscriber_id,
+        subscriber_account_number,
+        subscriber_full_name,
+        object_id,
+        object_address,
+        district_name,
+        device_ids,
+        monthly_consumption_kwh,
+        readings_count,
+        last_reading_at,
+        subscriber_avg_consumption_kwh,
+        subscriber_months_count,
+        district_avg_consumption_kwh,
+        if(
+            subscriber_avg_consumption_kwh > 0,
+            (monthly_consumption_kwh - subscriber_avg_consumption_kwh) / subscriber_avg_consumption_kwh,
+            0
+        ) as subscriber_deviation_ratio,
+        if(
+            district_avg_consumption_kwh > 0,
+            (monthly_consumption_kwh - district_avg_consumption_kwh) / district_avg_consumption_kwh,
+            0
+        ) as district_deviation_ratio
+    from
+    (
+        select
+            month,
+            subscriber_id,
+            subscriber_account_number,
+            subscriber_full_name,
+            object_id,
+            object_address,
+            district_name,
+            device_ids,
+            toFloat64(monthly_consumption_kwh) as monthly_consumption_kwh,
+            readings_count,
+            last_reading_at,
+            ifNull(
+                sum(toFloat64(monthly_consumption_kwh)) over (
+                    partition by subscriber_id, object_id
+                    order by month
+                    rows between unbounded preceding and 1 preceding
+                ) / nullIf(
+                    count() over (
+                        partition by subscriber_id, object_id
+                        order by month
+                        rows between unbounded preceding and 1 preceding
+                    ),
+                    0
+                ),
+                0
+            ) as subscriber_avg_consumption_kwh,
+            count() over (
+                partition by subscriber_id, object_id
+                order by month
+                rows between unbounded preceding and 1 preceding
+            ) as subscriber_months_count,
+            ifNull(
+                (
+                    sum(toFloat64(monthly_consumption_kwh)) over (partition by district_name, month)
+                    - toFloat64(monthly_consumption_kwh)
+                ) / nullIf(count() over (partition by district_name, month) - 1, 0),
+                0
+            ) as district_avg_consumption_kwh
+        from v_bi_consumption_monthly
+    )
+)
+select
+    month,
+    subscriber_id,
+    subscriber_account_number,
+    subscriber_full_name,
+    object_id,
+    object_address,
+    district_name,
+    device_ids,
+    monthly_consumption_kwh,
+    round(subscriber_avg_consumption_kwh, 2) as subscriber_avg_consumption_kwh,
+    subscriber_months_count,
+    round(district_avg_consumption_kwh, 2) as district_avg_consumption_kwh,
+    round(subscriber_deviation_ratio * 100, 2) as subscriber_deviation_percent,
+    round(district_deviation_ratio * 100, 2) as district_deviation_percent,
+    multiIf(
+        subscriber_months_count >= 3
+            and subscriber_deviation_ratio >= 0.5,
+        'Скачок относительно истории абонента',
+        subscriber_months_count >= 3
+            and subscriber_deviation_ratio <= -0.5,
+        'Провал относительно истории абонента',
+        district_deviation_ratio >= 1.5,
+        'Выше среднего по району',
+        district_deviation_ratio <= -0.6,
+        'Ниже среднего по району',
+        'Норма'
+    ) as anomaly_reason,
+    greatest(abs(subscriber_deviation_ratio), abs(district_deviation_ratio)) as severity_score,
+    readings_count,
+    last_reading_at
+from scored
+where
+    (subscriber_months_count >= 3 and abs(subscriber_deviation_ratio) >= 0.5)
+    or district_deviation_ratio >= 1.5
+    or district_deviation_ratio <= -0.6;
+
+create or replace view v_bi_consumption_monthly as
+select
+    toStartOfMonth(finished_at) as month,
+    subscriber_id,
+    subscriber_account_number,
+    concat(subscriber_surname, ' ', subscriber_name, ' ', subscriber_patronymic) as subscriber_full_name,
+    object_id,
+    object_address,
+    if(
+        empty(object_district) and empty(object_street),
+        replaceRegexpOne(object_address, ',.*$', ''),
+        object_district
+    ) as district_name,
+    groupUniqArray(toString(device_reading.1)) as inspected_device_ids,
+    groupUniqArray(toString(device_reading.2)) as device_ids,
+    sum(toDecimal64(device_reading.4, 2)) as monthly_consumption_kwh,
+    count() as readings_count,
+    max(finished_at) as last_reading_at
+from finished_tasks
+array join inspected_devices as device_reading
+where toDecimal64(device_reading.4, 2) > 0
+group by
+    month,
+    subscriber_id,
+    subscriber_account_number,
+    subscriber_full_name,
+    object_id,
+    object_address,
+    district_name;
+
+create or replace view v_bi_inspector_daily as
+select
+    toDate(finished_at) as day,
+    inspector.1 as inspector_id,
+    argMax(concat(inspector.2, ' ', inspector.3, ' ', inspector.4), inspector.7) as inspector_full_name,
+    brigade_id,
+    count() as tasks_count,
+    countIf(inspection_is_violation_detected) as violations_detected_count,
+    sum(dateDiff('minute', started_at, finished_at)) as total_duration_minutes,
+    round(avg(dateDiff('minute', started_at, finished_at)), 2) as avg_duration_minutes
+from finished_tasks
+array join brigade_inspectors as inspector
+group by day, inspector_id, brigade_id;
+
+create or replace view v_bi_visit_punctuality as
+select
+    toDate(plan_visit_at) as day,
+    task_id,
+    brigade_id,
+    arrayMap(i -> i.1, brigade_inspectors) as inspector_ids,
+    arrayMap(i -> concat(i.2, ' ', i.3, ' ', i.4), brigade_inspectors) as inspector_full_names,
+    if(
+        empty(object_district) and empty(object_street),
+        replaceRegexpOne(object_address, ',.*$', ''),
+        object_district
+    ) as district_name,
+    assumeNotNull(plan_visit_at) as plan_visit_at,
+    started_at,
+    dateDiff('minute', assumeNotNull(plan_visit_at), started_at) as delay_minutes
+from finished_tasks
+where plan_visit_at is not null;
+
+create or replace view v_bi_limitation_turnaround as
+select
+    l.object_id as object_id,
+    l.object_address as object_address,
+    l.subscriber_account_number as subscriber_account_number,
+    l.task_id as limitation_task_id,
+    l.finished_at as limited_at,
+    if(r.task_id = 0, null, r.task_id) as resumption_task_id,
+    if(r.task_id = 0, null, r.finished_at) as resumed_at,
+    if(r.task_id = 0, null, dateDiff('minute', l.finished_at, r.finished_at)) as disconnected_minutes
+from
+(
+    select object_id, object_address, subscriber_account_number, task_id, finished_at
+    from finished_tasks
+    where inspection_type = 'limitation'
+      and inspection_resolution = 'limited'
+) as l
+asof left join
+(
+    select object_id, task_id, finished_at
+    from finished_tasks
+    where inspection_type = 'resumption'
+      and inspection_resolution = 'resumed'
+) as r
+on l.object_id = r.object_id and l.finished_at < r.finished_at;
+
+create or replace view v_bi_object_access_refusals as
+select
+    toDate(finished_at) as day,
+    object_id,
+    argMax(object_address, finished_at) as object_address,
+    countIf(inspection_type = 'limitation') as limitation_refusals_count,
+    countIf(inspection_type = 'resumption') as resumption_refusals_count
+from finished_tasks
+where (inspection_type = 'limitation' and inspection_resolution != 'limited')
+   or (inspection_type = 'resumption' and inspection_resolution != 'resumed')
+group by day, object_id;
+
+create or replace view v_bi_device_readings as
+select
+    device_reading.2 as device_id,
+    device_reading.1 as inspected_device_id,
+    device_reading.5 as read_at,
+    device_reading.3 as value,
+    device_reading.4 as consumption_kwh,
+    arrayFirst(d -> d.1 = device_reading.2, object_devices) as object_device,
+    object_device.2 as device_type,
+    object_device.3 as device_number,
+    arrayMap(s -> s.2, object_device.6) as seal_numbers,
+    task_id,
+    finished_at,
+    object_id,
+    object_address,
+    subscriber_id,
+    subscriber_account_number
+from finished_tasks
+array join inspected_devices as device_reading;
+
+alter table finished_tasks
+    drop column if exists tenant;
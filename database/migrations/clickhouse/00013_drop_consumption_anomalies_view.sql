@@ -0,0 +1,116 @@
+-- +goose Up
+-- Consumption anomalies are flagged by the configurable rules of the anomaly engine and stored in Postgres.
+drop view if exists v_bi_consumption_anomalies;
+
+-- +goose Down
+create or replace view v_bi_consumption_anomalies as
+with scored as
+(
+    select
+        month,
+        subscriber_id,
+        subscriber_account_number,
+        subscriber_full_name,
+        object_id,
+        object_address,
+        district_name,
+        tenant,
+        device_ids,
+        monthly_consumption_kwh,
+        readings_count,
+        last_reading_at,
+        subscriber_avg_consumption_kwh,
+        subscriber_months_count,
+        district_avg_consumption_kwh,
+        if(
+            subscriber_avg_consumption_kwh > 0,
+            (monthly_consumption_kwh - subscriber_avg_consumption_kwh) / subscriber_avg_consumption_kwh,
+            0
+        ) as subscriber_deviation_ratio,
+        if(
+            district_avg_consumption_kwh > 0,
+            (monthly_consumption_kwh - district_avg_consumption_kwh) / district_avg_consumption_kwh,
+            0
+        ) as district_deviation_ratio
+    from
+    (
+        select
+            month,
+            subscriber_id,
+            subscriber_account_number,
+            subscriber_full_name,
+            object_id,
+            object_address,
+            district_name,
+            tenant,
+            device_ids,
+            toFloat64(monthly_consumption_kwh) as monthly_consumption_kwh,
+            readings_count,
+            last_reading_at,
+            ifNull(
+                sum(toFloat64(monthly_consumption_kwh)) over (
+                    partition by subscriber_id, object_id
+                    order by month
+                    rows between unbounded preceding and 1 preceding
+                ) / nullIf(
+                    count() over (
+                        partition by subscriber_id, object_id
+                        order by month
+                        rows between unbounded preceding and 1 preceding
+                    ),
+                    0
+                ),
+                0
+            ) as subscriber_avg_consumption_kwh,
+            count() over (
+                partition by subscriber_id, object_id
+                order by month
+                rows between unbounded preceding and 1 preceding
+            ) as subscriber_months_count,
+            ifNull(
+                (
+                    sum(toFloat64(monthly_consumption_kwh)) over (partition by tenant, district_name, month)
+                    - toFloat64(monthly_consumption_kwh)
+                ) / nullIf(count() over (partition by tenant, district_name, month) - 1, 0),
+                0
+            ) as district_avg_consumption_kwh
+        from v_bi_consumption_monthly
+    )
+)
+select
+    month,
+    subscriber_id,
+    subscriber_account_number,
+    subscriber_full_name,
+    object_id,
+    object_address,
+    district_name,
+    tenant,
+    device_ids,
+    monthly_consumption_kwh,
+    round(subscriber_avg_consumption_kwh, 2) as subscriber_avg_consumption_kwh,
+    subscriber_months_count,
+    round(district_avg_consumption_kwh, 2) as district_avg_consumption_kwh,
+    round(subscriber_deviation_ratio * 100, 2) as subscriber_deviation_percent,
+    round(district_deviation_ratio * 100, 2) as district_deviation_percent,
+    multiIf(
+        subscriber_months_count >= 3
+            and subscriber_deviation_ratio >= 0.5,
+        'Скачок относительно истории абонента',
+        subscriber_months_count >= 3
+            and subscriber_deviation_ratio <= -0.5,
+        'Провал относительно истории абонента',
+        district_deviation_ratio >= 1.5,
+        'Выше среднего по району',
+        district_deviation_ratio <= -0.6,
+        'Ниже среднего по району',
+        'Норма'
+    ) as anomaly_reason,
+    greatest(abs(subscriber_deviation_ratio), abs(district_deviation_ratio)) as severity_score,
+    readings_count,
+    last_reading_at
+from scored
+where
+    (subscriber_months_count >= 3 and abs(subscriber_deviation_ratio) >= 0.5)
+    or district_deviation_ratio >= 1.5
+    or district_deviation_ratio <= -0.6;
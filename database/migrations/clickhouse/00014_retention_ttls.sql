@@ -0,0 +1,14 @@
+-- +goose Up
+-- TTLs configured by retention settings are applied on startup, the applied ones are recorded here.
+-- A table without a record has the TTL it was created with.
+create table if not exists retention_ttls
+(
+    table_name LowCardinality(String),
+    ttl        String,
+    applied_at DateTime64(3, 'UTC') default now64(3)
+)
+    engine = ReplacingMergeTree(applied_at)
+        order by table_name;
+
+-- +goose Down
+drop table if exists retention_ttls;
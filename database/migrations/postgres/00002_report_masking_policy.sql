@@ -0,0 +1,7 @@
+-- +goose Up
+alter table reports
+    add column if not exists masking_policy text not null default 'full';
+
+-- +goose Down
+alter table reports
+    drop column if exists masking_policy;
@@ -0,0 +1,43 @@
+-- +goose Up
+create table if not exists audit_log
+(
+    id         bigint primary key generated always as identity,
+    actor_id   int         not null,
+    actor_role text        not null,
+    action     text        not null,
+    report_id  int,
+    parameters jsonb       not null default '{}',
+    client_ip  text        not null default '',
+    created_at timestamptz not null default now()
+);
+
+create index if not exists idx_audit_log_created_at on audit_log (created_at);
+create index if not exists idx_audit_log_actor on audit_log (actor_id);
+create index if not exists idx_audit_log_report on audit_log (report_id);
+
+-- +goose StatementBegin
+create or replace function audit_log_append_only() returns trigger as
+$$
+begin
+    raise exception 'audit_log is append-only';
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+
+create trigger audit_log_no_update_delete
+    before update or delete
+    on audit_log
+    for each row
+execute function audit_log_append_only();
+
+create trigger audit_log_no_truncate
+    before truncate
+    on audit_log
+    for each statement
+execute function audit_log_append_only();
+
+-- +goose Down
+drop trigger if exists audit_log_no_truncate on audit_log;
+drop trigger if exists audit_log_no_update_delete on audit_log;
+drop function if exists audit_log_append_only();
+drop table if exists audit_log;
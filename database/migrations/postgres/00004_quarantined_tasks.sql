@@ -0,0 +1,16 @@
+-- +goose Up
+create table if not exists quarantined_tasks
+(
+    id             bigint primary key generated always as identity,
+    task_id        int         not null,
+    payload        jsonb       not null,
+    missing_fields jsonb       not null default '[]',
+    reason         text        not null default '',
+    created_at     timestamptz not null default now(),
+    repaired_at    timestamptz
+);
+
+create index if not exists idx_quarantined_tasks_pending on quarantined_tasks (id) where repaired_at is null;
+
+-- +goose Down
+drop table if exists quarantined_tasks;
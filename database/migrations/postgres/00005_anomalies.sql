@@ -0,0 +1,46 @@
+-- +goose Up
+create table if not exists anomaly_rules
+(
+    id         int primary key generated always as identity,
+    name       text        not null unique,
+    kind       text        not null,
+    params     jsonb       not null default '{}',
+    enabled    boolean     not null default true,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now()
+);
+
+insert into anomaly_rules (name, kind, params)
+values ('Z-оценка относительно истории абонента', 'z_score', '{"threshold": 3, "minHistory": 6}'),
+       ('Выброс за межквартильный размах истории абонента', 'iqr', '{"k": 1.5, "minHistory": 6}'),
+       ('Отклонение от того же месяца прошлого года', 'seasonal', '{"threshold": 0.5}'),
+       ('Нулевое потребление несколько месяцев подряд', 'zero_streak', '{"minMonths": 3}')
+on conflict (name) do nothing;
+
+create table if not exists anomalies
+(
+    id                        bigint primary key generated always as identity,
+    rule_id                   int              not null references anomaly_rules (id),
+    month                     date             not null,
+    subscriber_id             int              not null,
+    subscriber_account_number text             not null default '',
+    object_id                 int              not null,
+    object_address            text             not null default '',
+    district_name             text             not null default '',
+    consumption_kwh           double precision not null,
+    expected_kwh              double precision,
+    score                     double precision not null,
+    status                    text             not null default 'new',
+    comment                   text             not null default '',
+    triaged_by                int,
+    triaged_at                timestamptz,
+    created_at                timestamptz      not null default now(),
+    updated_at                timestamptz      not null default now(),
+    unique (rule_id, subscriber_id, object_id, month)
+);
+
+create index if not exists idx_anomalies_status_month on anomalies (status, month);
+
+-- +goose Down
+drop table if exists anomalies;
+drop table if exists anomaly_rules;
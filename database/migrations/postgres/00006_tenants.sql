@@ -0,0 +1,17 @@
+-- +goose Up
+alter table reports
+    add column if not exists tenants jsonb not null default '[]';
+
+alter table anomalies
+    add column if not exists tenant text not null default '';
+
+create index if not exists idx_anomalies_tenant on anomalies (tenant);
+
+-- +goose Down
+drop index if exists idx_anomalies_tenant;
+
+alter table anomalies
+    drop column if exists tenant;
+
+alter table reports
+    drop column if exists tenants;
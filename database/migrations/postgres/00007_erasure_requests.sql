@@ -0,0 +1,16 @@
+-- +goose Up
+create table if not exists erasure_requests
+(
+    id            bigint primary key generated always as identity,
+    subscriber_id int         not null,
+    reason        text        not null default '',
+    requested_by  int         not null,
+    tenants       jsonb       not null default '[]',
+    anomalies     int         not null default 0,
+    created_at    timestamptz not null default now()
+);
+
+create index if not exists idx_erasure_requests_subscriber_id on erasure_requests (subscriber_id);
+
+-- +goose Down
+drop table if exists erasure_requests;
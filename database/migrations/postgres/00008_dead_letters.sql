@@ -0,0 +1,15 @@
+-- +goose Up
+create table if not exists dead_letters
+(
+    id         bigint primary key generated always as identity,
+    topic      text        not null,
+    partition  int         not null,
+    "offset"   bigint      not null,
+    task_id    int         not null default 0,
+    payload    bytea       not null,
+    reason     text        not null default '',
+    created_at timestamptz not null default now()
+);
+
+-- +goose Down
+drop table if exists dead_letters;
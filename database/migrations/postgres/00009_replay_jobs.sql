@@ -0,0 +1,17 @@
+-- +goose Up
+create table if not exists replay_jobs
+(
+    id           bigint primary key generated always as identity,
+    period_start timestamptz not null,
+    period_end   timestamptz not null,
+    table_name   text        not null,
+    status       text        not null default 'pending',
+    events       int         not null default 0,
+    error        text        not null default '',
+    requested_by int         not null,
+    created_at   timestamptz not null default now(),
+    finished_at  timestamptz
+);
+
+-- +goose Down
+drop table if exists replay_jobs;
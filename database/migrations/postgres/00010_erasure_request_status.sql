@@ -0,0 +1,11 @@
+-- +goose Up
+-- Requests recorded before the status column were processed in full.
+alter table erasure_requests
+    add column if not exists status text not null default 'done';
+
+alter table erasure_requests
+    alter column status set default 'pending';
+
+-- +goose Down
+alter table erasure_requests
+    drop column if exists status;
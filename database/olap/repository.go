@@ -0,0 +1,69 @@
+package olap
+
+import (
+	"analytics-service/service/olap"
+	"context"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var tracer = otel.Tracer("analytics-service/database/olap")
+
+type Repository struct {
+	clickhouse driver.Conn
+}
+
+func NewRepository(clickhouse driver.Conn) *Repository {
+	return &Repository{
+		clickhouse: clickhouse,
+	}
+}
+
+// Query runs a statement compiled by olap.Compile, its columns are the dimensions followed by the metrics.
+func (r *Repository) Query(ctx context.Context, stmt olap.Statement) (_ []olap.Row, err error) {
+	ctx, span := tracer.Start(ctx, "olap.Repository.Query")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("db.statement", stmt.SQL))
+
+	rows, err := r.clickhouse.Query(ctx, stmt.SQL, stmt.Args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.clickhouse.Query: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("rows.Close: %w", closeErr)
+		}
+	}()
+
+	result := make([]olap.Row, 0)
+	for rows.Next() {
+		row := olap.Row{
+			Dimensions: make([]string, stmt.Dimensions),
+			Metrics:    make([]float64, stmt.Metrics),
+		}
+
+		dest := make([]any, 0, stmt.Dimensions+stmt.Metrics)
+		for i := range row.Dimensions {
+			dest = append(dest, &row.Dimensions[i])
+		}
+		for i := range row.Metrics {
+			dest = append(dest, &row.Metrics[i])
+		}
+
+		if err = rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		result = append(result, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return result, nil
+}
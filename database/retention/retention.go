@@ -0,0 +1,82 @@
+package retention
+
+import (
+	"analytics-service/config"
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sunshineOfficial/golib/golog"
+)
+
+var (
+	//go:embed sql/add_applied_ttl.sql
+	addAppliedTTLSQL string
+
+	//go:embed sql/get_applied_ttl.sql
+	getAppliedTTLSQL string
+)
+
+// TTLs the tables are created with by the SQL migrations, zero retention restores them.
+const (
+	defaultFinishedTasksTTL = "finished_at + interval 2 year"
+	defaultRawEventsTTL     = "toDateTime(received_at) + interval 180 day"
+)
+
+// Migration sets the TTL of a ClickHouse table. Default is the TTL the table is created with.
+type Migration struct {
+	Table   string
+	TTL     string
+	Default string
+}
+
+func (m Migration) SQL() string {
+	return fmt.Sprintf("alter table %s modify ttl %s delete", m.Table, m.TTL)
+}
+
+// Migrations generates the TTL migrations from config, tables with zero retention get the TTL of the SQL migrations.
+func Migrations(settings config.Retention) []Migration {
+	finishedTasks := Migration{Table: "finished_tasks", TTL: defaultFinishedTasksTTL, Default: defaultFinishedTasksTTL}
+	if settings.FinishedTasksDays > 0 {
+		finishedTasks.TTL = fmt.Sprintf("finished_at + interval %d day", settings.FinishedTasksDays)
+	}
+
+	rawEvents := Migration{Table: "raw_events", TTL: defaultRawEventsTTL, Default: defaultRawEventsTTL}
+	if settings.RawEventsDays > 0 {
+		rawEvents.TTL = fmt.Sprintf("toDateTime(received_at) + interval %d day", settings.RawEventsDays)
+	}
+
+	return []Migration{finishedTasks, rawEvents}
+}
+
+// Migrate applies the migrations generated from config and records them in retention_ttls, which is created by
+// the SQL migrations. ClickHouse rewrites the parts of a table on every TTL change, so tables already having
+// the configured TTL are left alone.
+func Migrate(ctx context.Context, log golog.Logger, clickhouse *sqlx.DB, settings config.Retention) error {
+	for _, m := range Migrations(settings) {
+		var applied string
+		if err := clickhouse.GetContext(ctx, &applied, getAppliedTTLSQL, m.Table); err != nil {
+			return fmt.Errorf("get %s applied ttl: %w", m.Table, err)
+		}
+		if applied == "" {
+			applied = m.Default
+		}
+
+		if applied == m.TTL {
+			continue
+		}
+
+		if _, err := clickhouse.ExecContext(ctx, m.SQL()); err != nil {
+			return fmt.Errorf("modify %s ttl: %w", m.Table, err)
+		}
+
+		if _, err := clickhouse.ExecContext(ctx, addAppliedTTLSQL, m.Table, m.TTL); err != nil {
+			return fmt.Errorf("add %s applied ttl: %w", m.Table, err)
+		}
+
+		log.Debugf("%s ttl is set to %s", m.Table, m.TTL)
+	}
+
+	return nil
+}
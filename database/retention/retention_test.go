@@ -0,0 +1,22 @@
+package retention
+
+import (
+	"analytics-service/config"
+	"testing"
+)
+
+func TestMigrationsRestoreDefaultTTLOnZeroRetention(t *testing.T) {
+	migrations := Migrations(config.Retention{RawEventsDays: 90})
+	if len(migrations) != 2 {
+		t.Fatalf("expected migrations of both tables, got %v", migrations)
+	}
+
+	if m := migrations[0]; m.Table != "finished_tasks" || m.TTL != m.Default {
+		t.Fatalf("expected finished_tasks to get the default ttl, got %v", m)
+	}
+
+	want := "alter table raw_events modify ttl toDateTime(received_at) + interval 90 day delete"
+	if sql := migrations[1].SQL(); sql != want {
+		t.Fatalf("expected %q, got %q", want, sql)
+	}
+}
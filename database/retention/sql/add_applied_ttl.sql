@@ -0,0 +1,2 @@
+insert into retention_ttls (table_name, ttl)
+values ($1, $2)
@@ -0,0 +1,3 @@
+select argMax(ttl, applied_at)
+from retention_ttls
+where table_name = $1
@@ -39,93 +39,3584 @@ const docTemplate = `{
                 },
                 "type": "object"
             },
-            "analytics-service_service_analytics.Report": {
+            "analytics-service_cluster_subscriber.Status": {
+                "enum": [
+                    0,
+                    1,
+                    2,
+                    3
+                ],
+                "type": "integer",
+                "x-enum-varnames": [
+                    "StatusUnknown",
+                    "StatusActive",
+                    "StatusViolator",
+                    "StatusArchived"
+                ]
+            },
+            "analytics-service_service_analytics.Comparison": {
+                "properties": {
+                    "ByBrigade": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.ComparisonGroup"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "ByInspectionType": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.ComparisonGroup"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "Current": {
+                        "$ref": "#/components/schemas/analytics-service_service_analytics.Period"
+                    },
+                    "Previous": {
+                        "$ref": "#/components/schemas/analytics-service_service_analytics.Period"
+                    },
+                    "Total": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.ComparisonMetric"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.ComparisonGroup": {
+                "properties": {
+                    "Key": {
+                        "type": "string"
+                    },
+                    "Metrics": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.ComparisonMetric"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.ComparisonMetric": {
+                "properties": {
+                    "Current": {
+                        "type": "number"
+                    },
+                    "Delta": {
+                        "type": "number"
+                    },
+                    "DeltaPercent": {
+                        "type": "number"
+                    },
+                    "Name": {
+                        "type": "string"
+                    },
+                    "Previous": {
+                        "type": "number"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.DeadLetter": {
                 "properties": {
                     "CreatedAt": {
                         "type": "string"
                     },
-                    "Files": {
-                        "items": {
-                            "$ref": "#/components/schemas/analytics-service_cluster_file.File"
+                    "ID": {
+                        "type": "integer"
+                    },
+                    "Offset": {
+                        "type": "integer"
+                    },
+                    "Partition": {
+                        "type": "integer"
+                    },
+                    "Payload": {
+                        "items": {
+                            "type": "integer"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "Reason": {
+                        "type": "string"
+                    },
+                    "TaskID": {
+                        "type": "integer"
+                    },
+                    "Topic": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.DelayBucket": {
+                "properties": {
+                    "FromMinutes": {
+                        "type": "integer"
+                    },
+                    "TasksCount": {
+                        "type": "integer"
+                    },
+                    "ToMinutes": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.DeviceHistory": {
+                "properties": {
+                    "DeviceID": {
+                        "type": "integer"
+                    },
+                    "FirstReadAt": {
+                        "type": "string"
+                    },
+                    "JumpsCount": {
+                        "type": "integer"
+                    },
+                    "LastReadAt": {
+                        "type": "string"
+                    },
+                    "Readings": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.DeviceReading"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "RollbacksCount": {
+                        "type": "integer"
+                    },
+                    "TotalDelta": {
+                        "type": "number"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.DeviceReading": {
+                "properties": {
+                    "ConsumptionKWh": {
+                        "type": "number"
+                    },
+                    "DailyDelta": {
+                        "type": "number"
+                    },
+                    "DaysSincePrevious": {
+                        "type": "number"
+                    },
+                    "Delta": {
+                        "type": "number"
+                    },
+                    "DeviceID": {
+                        "type": "integer"
+                    },
+                    "DeviceNumber": {
+                        "type": "string"
+                    },
+                    "DeviceType": {
+                        "type": "string"
+                    },
+                    "FinishedAt": {
+                        "type": "string"
+                    },
+                    "Flags": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.ReadingFlag"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "InspectedDeviceID": {
+                        "type": "integer"
+                    },
+                    "ObjectAddress": {
+                        "type": "string"
+                    },
+                    "ObjectID": {
+                        "type": "integer"
+                    },
+                    "ReadAt": {
+                        "type": "string"
+                    },
+                    "SealNumbers": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "SubscriberAccountNumber": {
+                        "type": "string"
+                    },
+                    "SubscriberID": {
+                        "type": "integer"
+                    },
+                    "TaskID": {
+                        "type": "integer"
+                    },
+                    "Value": {
+                        "type": "number"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.InspectorPerformance": {
+                "properties": {
+                    "AvgDurationMinutes": {
+                        "type": "number"
+                    },
+                    "BrigadeIDs": {
+                        "items": {
+                            "type": "integer"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "FullName": {
+                        "type": "string"
+                    },
+                    "InspectorID": {
+                        "type": "integer"
+                    },
+                    "TasksCount": {
+                        "type": "integer"
+                    },
+                    "ViolationsDetectedCount": {
+                        "type": "integer"
+                    },
+                    "Workload": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.InspectorWorkload"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.InspectorWorkload": {
+                "properties": {
+                    "Day": {
+                        "type": "string"
+                    },
+                    "TasksCount": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.LimitationTurnaround": {
+                "properties": {
+                    "DisconnectedMinutes": {
+                        "type": "integer"
+                    },
+                    "LimitationTaskID": {
+                        "type": "integer"
+                    },
+                    "LimitedAt": {
+                        "type": "string"
+                    },
+                    "ObjectAddress": {
+                        "type": "string"
+                    },
+                    "ObjectID": {
+                        "type": "integer"
+                    },
+                    "ResumedAt": {
+                        "type": "string"
+                    },
+                    "ResumptionTaskID": {
+                        "type": "integer"
+                    },
+                    "SubscriberAccountNumber": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.ObjectTurnaround": {
+                "properties": {
+                    "AvgDisconnectedHours": {
+                        "type": "number"
+                    },
+                    "LimitationRefusalsCount": {
+                        "type": "integer"
+                    },
+                    "LimitationsCount": {
+                        "type": "integer"
+                    },
+                    "MaxDisconnectedHours": {
+                        "type": "number"
+                    },
+                    "ObjectAddress": {
+                        "type": "string"
+                    },
+                    "ObjectID": {
+                        "type": "integer"
+                    },
+                    "OpenCount": {
+                        "type": "integer"
+                    },
+                    "ResumedCount": {
+                        "type": "integer"
+                    },
+                    "ResumptionRefusalsCount": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.Period": {
+                "properties": {
+                    "End": {
+                        "type": "string"
+                    },
+                    "Start": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.Punctuality": {
+                "properties": {
+                    "ByBrigade": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.PunctualityGroup"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "ByDay": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.PunctualityGroup"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "ByDistrict": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.PunctualityGroup"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "ByInspector": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.PunctualityGroup"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "EarlyToleranceMinutes": {
+                        "type": "integer"
+                    },
+                    "LateToleranceMinutes": {
+                        "type": "integer"
+                    },
+                    "Total": {
+                        "$ref": "#/components/schemas/analytics-service_service_analytics.PunctualityMetrics"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.PunctualityGroup": {
+                "properties": {
+                    "Key": {
+                        "type": "string"
+                    },
+                    "Metrics": {
+                        "$ref": "#/components/schemas/analytics-service_service_analytics.PunctualityMetrics"
+                    },
+                    "Name": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.PunctualityMetrics": {
+                "properties": {
+                    "Buckets": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.DelayBucket"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "EarlyCount": {
+                        "type": "integer"
+                    },
+                    "LateCount": {
+                        "type": "integer"
+                    },
+                    "MedianDelayMinutes": {
+                        "type": "number"
+                    },
+                    "OnTimeCount": {
+                        "type": "integer"
+                    },
+                    "OnTimeShare": {
+                        "type": "number"
+                    },
+                    "P90DelayMinutes": {
+                        "type": "number"
+                    },
+                    "TasksCount": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.QuarantinedTask": {
+                "properties": {
+                    "CreatedAt": {
+                        "type": "string"
+                    },
+                    "ID": {
+                        "type": "integer"
+                    },
+                    "MissingFields": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "Payload": {
+                        "type": "object"
+                    },
+                    "Reason": {
+                        "type": "string"
+                    },
+                    "RepairedAt": {
+                        "type": "string"
+                    },
+                    "TaskID": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.ReadingFlag": {
+                "enum": [
+                    "rollback",
+                    "jump"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "ReadingFlagRollback",
+                    "ReadingFlagJump"
+                ]
+            },
+            "analytics-service_service_analytics.RepairFailure": {
+                "properties": {
+                    "ID": {
+                        "type": "integer"
+                    },
+                    "Reason": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.RepairResult": {
+                "properties": {
+                    "Failed": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.RepairFailure"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "Repaired": {
+                        "items": {
+                            "type": "integer"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "StillInvalid": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.QuarantinedTask"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.ReplayJob": {
+                "properties": {
+                    "CreatedAt": {
+                        "type": "string"
+                    },
+                    "Error": {
+                        "type": "string"
+                    },
+                    "Events": {
+                        "type": "integer"
+                    },
+                    "FinishedAt": {
+                        "type": "string"
+                    },
+                    "ID": {
+                        "type": "integer"
+                    },
+                    "PeriodEnd": {
+                        "type": "string"
+                    },
+                    "PeriodStart": {
+                        "type": "string"
+                    },
+                    "RequestedBy": {
+                        "type": "integer"
+                    },
+                    "Status": {
+                        "$ref": "#/components/schemas/analytics-service_service_analytics.ReplayStatus"
+                    },
+                    "Table": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.ReplayStatus": {
+                "enum": [
+                    "pending",
+                    "running",
+                    "done",
+                    "failed",
+                    "expired"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "ReplayStatusPending",
+                    "ReplayStatusRunning",
+                    "ReplayStatusDone",
+                    "ReplayStatusFailed",
+                    "ReplayStatusExpired"
+                ]
+            },
+            "analytics-service_service_analytics.Report": {
+                "properties": {
+                    "CreatedAt": {
+                        "type": "string"
+                    },
+                    "Files": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_cluster_file.File"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "ID": {
+                        "type": "integer"
+                    },
+                    "MaskingPolicy": {
+                        "$ref": "#/components/schemas/analytics-service_service_masking.Policy"
+                    },
+                    "PeriodEnd": {
+                        "type": "string"
+                    },
+                    "PeriodStart": {
+                        "type": "string"
+                    },
+                    "Tenants": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "Type": {
+                        "$ref": "#/components/schemas/analytics-service_service_analytics.ReportType"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.ReportType": {
+                "enum": [
+                    0,
+                    1,
+                    2,
+                    3,
+                    4,
+                    5,
+                    6
+                ],
+                "type": "integer",
+                "x-enum-varnames": [
+                    "ReportTypeUnknown",
+                    "ReportTypeBasic",
+                    "ReportTypeInspectors",
+                    "ReportTypePunctuality",
+                    "ReportTypeWatchList",
+                    "ReportTypeDevicePassport",
+                    "ReportTypeComparison"
+                ]
+            },
+            "analytics-service_service_analytics.Subscriber": {
+                "properties": {
+                    "AccountNumber": {
+                        "type": "string"
+                    },
+                    "BirthDate": {
+                        "type": "string"
+                    },
+                    "Email": {
+                        "type": "string"
+                    },
+                    "ID": {
+                        "type": "integer"
+                    },
+                    "INN": {
+                        "type": "string"
+                    },
+                    "Name": {
+                        "type": "string"
+                    },
+                    "Patronymic": {
+                        "type": "string"
+                    },
+                    "PhoneNumber": {
+                        "type": "string"
+                    },
+                    "Status": {
+                        "$ref": "#/components/schemas/analytics-service_cluster_subscriber.Status"
+                    },
+                    "Surname": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.WatchList": {
+                "properties": {
+                    "Entries": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.WatchListEntry"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "MaskingPolicy": {
+                        "$ref": "#/components/schemas/analytics-service_service_masking.Policy"
+                    },
+                    "PeriodEnd": {
+                        "type": "string"
+                    },
+                    "PeriodStart": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.WatchListEntry": {
+                "properties": {
+                    "Hits": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_analytics.WatchListHit"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "LastTaskAt": {
+                        "type": "string"
+                    },
+                    "ObjectAddress": {
+                        "type": "string"
+                    },
+                    "ObjectID": {
+                        "type": "integer"
+                    },
+                    "Rank": {
+                        "type": "integer"
+                    },
+                    "Score": {
+                        "type": "integer"
+                    },
+                    "Subscriber": {
+                        "$ref": "#/components/schemas/analytics-service_service_analytics.Subscriber"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.WatchListHit": {
+                "properties": {
+                    "Rule": {
+                        "$ref": "#/components/schemas/analytics-service_service_analytics.WatchListRule"
+                    },
+                    "TaskIDs": {
+                        "items": {
+                            "type": "integer"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_analytics.WatchListRule": {
+                "enum": [
+                    "unauthorized_after_limitation",
+                    "repeated_violations",
+                    "frequent_visits"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "WatchListRuleUnauthorizedAfterLimitation",
+                    "WatchListRuleRepeatedViolations",
+                    "WatchListRuleFrequentVisits"
+                ]
+            },
+            "analytics-service_service_anomaly.Anomaly": {
+                "properties": {
+                    "Comment": {
+                        "type": "string"
+                    },
+                    "ConsumptionKWh": {
+                        "type": "number"
+                    },
+                    "CreatedAt": {
+                        "type": "string"
+                    },
+                    "DistrictName": {
+                        "type": "string"
+                    },
+                    "ExpectedKWh": {
+                        "type": "number"
+                    },
+                    "ID": {
+                        "type": "integer"
+                    },
+                    "Month": {
+                        "type": "string"
+                    },
+                    "ObjectAddress": {
+                        "type": "string"
+                    },
+                    "ObjectID": {
+                        "type": "integer"
+                    },
+                    "RuleID": {
+                        "type": "integer"
+                    },
+                    "RuleKind": {
+                        "$ref": "#/components/schemas/analytics-service_service_anomaly.RuleKind"
+                    },
+                    "RuleName": {
+                        "type": "string"
+                    },
+                    "Score": {
+                        "type": "number"
+                    },
+                    "Status": {
+                        "$ref": "#/components/schemas/analytics-service_service_anomaly.Status"
+                    },
+                    "SubscriberAccountNumber": {
+                        "type": "string"
+                    },
+                    "SubscriberID": {
+                        "type": "integer"
+                    },
+                    "Tenant": {
+                        "type": "string"
+                    },
+                    "TriagedAt": {
+                        "type": "string"
+                    },
+                    "TriagedBy": {
+                        "type": "integer"
+                    },
+                    "UpdatedAt": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_anomaly.EvaluationResult": {
+                "properties": {
+                    "Anomalies": {
+                        "type": "integer"
+                    },
+                    "From": {
+                        "type": "string"
+                    },
+                    "Removed": {
+                        "type": "integer"
+                    },
+                    "Rules": {
+                        "type": "integer"
+                    },
+                    "Series": {
+                        "type": "integer"
+                    },
+                    "Tenanted": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_anomaly.Rule": {
+                "properties": {
+                    "Enabled": {
+                        "type": "boolean"
+                    },
+                    "ID": {
+                        "type": "integer"
+                    },
+                    "Kind": {
+                        "$ref": "#/components/schemas/analytics-service_service_anomaly.RuleKind"
+                    },
+                    "Name": {
+                        "type": "string"
+                    },
+                    "Params": {
+                        "$ref": "#/components/schemas/analytics-service_service_anomaly.RuleParams"
+                    },
+                    "UpdatedAt": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_anomaly.RuleKind": {
+                "enum": [
+                    "z_score",
+                    "iqr",
+                    "seasonal",
+                    "zero_streak"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "RuleKindZScore",
+                    "RuleKindIQR",
+                    "RuleKindSeasonal",
+                    "RuleKindZeroStreak"
+                ]
+            },
+            "analytics-service_service_anomaly.RuleParams": {
+                "properties": {
+                    "k": {
+                        "type": "number"
+                    },
+                    "minHistory": {
+                        "type": "integer"
+                    },
+                    "minMonths": {
+                        "type": "integer"
+                    },
+                    "threshold": {
+                        "type": "number"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_anomaly.Status": {
+                "enum": [
+                    "new",
+                    "confirmed",
+                    "dismissed"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "StatusNew",
+                    "StatusConfirmed",
+                    "StatusDismissed"
+                ]
+            },
+            "analytics-service_service_audit.Action": {
+                "enum": [
+                    "report_generate",
+                    "report_access",
+                    "report_download",
+                    "watch_list_access",
+                    "subscriber_erase"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "ActionReportGenerate",
+                    "ActionReportAccess",
+                    "ActionReportDownload",
+                    "ActionWatchListAccess",
+                    "ActionSubscriberErase"
+                ]
+            },
+            "analytics-service_service_audit.Record": {
+                "properties": {
+                    "Action": {
+                        "$ref": "#/components/schemas/analytics-service_service_audit.Action"
+                    },
+                    "ActorID": {
+                        "type": "integer"
+                    },
+                    "ActorRole": {
+                        "$ref": "#/components/schemas/analytics-service_service_auth.Role"
+                    },
+                    "ClientIP": {
+                        "type": "string"
+                    },
+                    "CreatedAt": {
+                        "type": "string"
+                    },
+                    "ID": {
+                        "type": "integer"
+                    },
+                    "Parameters": {
+                        "additionalProperties": {
+                            "type": "string"
+                        },
+                        "type": "object"
+                    },
+                    "ReportID": {
+                        "type": "integer"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_auth.Role": {
+                "enum": [
+                    "",
+                    "admin",
+                    "analyst",
+                    "contractor",
+                    "system"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "RoleUnknown",
+                    "RoleAdmin",
+                    "RoleAnalyst",
+                    "RoleContractor",
+                    "RoleSystem"
+                ]
+            },
+            "analytics-service_service_erasure.Request": {
+                "properties": {
+                    "Anomalies": {
+                        "type": "integer"
+                    },
+                    "CreatedAt": {
+                        "type": "string"
+                    },
+                    "ID": {
+                        "type": "integer"
+                    },
+                    "Reason": {
+                        "type": "string"
+                    },
+                    "RequestedBy": {
+                        "type": "integer"
+                    },
+                    "Status": {
+                        "$ref": "#/components/schemas/analytics-service_service_erasure.RequestStatus"
+                    },
+                    "SubscriberID": {
+                        "type": "integer"
+                    },
+                    "Tenants": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_erasure.RequestStatus": {
+                "enum": [
+                    "pending",
+                    "done"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "RequestStatusPending",
+                    "RequestStatusDone"
+                ]
+            },
+            "analytics-service_service_forecast.Accuracy": {
+                "properties": {
+                    "Coverage": {
+                        "type": "number"
+                    },
+                    "MAE": {
+                        "type": "number"
+                    },
+                    "MAPE": {
+                        "type": "number"
+                    },
+                    "Method": {
+                        "$ref": "#/components/schemas/analytics-service_service_forecast.Method"
+                    },
+                    "Points": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_forecast.BacktestPoint"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "RMSE": {
+                        "type": "number"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_forecast.Backtest": {
+                "properties": {
+                    "Confidence": {
+                        "type": "number"
+                    },
+                    "Days": {
+                        "type": "integer"
+                    },
+                    "HistoryFrom": {
+                        "type": "string"
+                    },
+                    "Series": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_forecast.SeriesBacktest"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "TestFrom": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_forecast.BacktestPoint": {
+                "properties": {
+                    "Actual": {
+                        "type": "integer"
+                    },
+                    "Day": {
+                        "type": "string"
+                    },
+                    "Lower": {
+                        "type": "number"
+                    },
+                    "Upper": {
+                        "type": "number"
+                    },
+                    "Value": {
+                        "type": "number"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_forecast.Forecast": {
+                "properties": {
+                    "Confidence": {
+                        "type": "number"
+                    },
+                    "Days": {
+                        "type": "integer"
+                    },
+                    "From": {
+                        "type": "string"
+                    },
+                    "HistoryFrom": {
+                        "type": "string"
+                    },
+                    "Method": {
+                        "$ref": "#/components/schemas/analytics-service_service_forecast.Method"
+                    },
+                    "Series": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_forecast.SeriesForecast"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_forecast.Method": {
+                "enum": [
+                    "seasonal_naive",
+                    "exponential_smoothing"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "MethodSeasonalNaive",
+                    "MethodExponentialSmoothing"
+                ]
+            },
+            "analytics-service_service_forecast.Prediction": {
+                "properties": {
+                    "Day": {
+                        "type": "string"
+                    },
+                    "Lower": {
+                        "type": "number"
+                    },
+                    "Upper": {
+                        "type": "number"
+                    },
+                    "Value": {
+                        "type": "number"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_forecast.SeriesBacktest": {
+                "properties": {
+                    "Accuracy": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_forecast.Accuracy"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "Type": {
+                        "$ref": "#/components/schemas/analytics-service_service_forecast.TaskType"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_forecast.SeriesForecast": {
+                "properties": {
+                    "Predictions": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_forecast.Prediction"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "Type": {
+                        "$ref": "#/components/schemas/analytics-service_service_forecast.TaskType"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_forecast.TaskType": {
+                "enum": [
+                    "limitation",
+                    "resumption",
+                    "verification",
+                    "unauthorized_connection",
+                    "total"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "TaskTypeLimitation",
+                    "TaskTypeResumption",
+                    "TaskTypeVerification",
+                    "TaskTypeUnauthorizedConnection",
+                    "TaskTypeTotal"
+                ]
+            },
+            "analytics-service_service_health.Dependency": {
+                "properties": {
+                    "Details": {
+                        "additionalProperties": {
+                            "type": "string"
+                        },
+                        "type": "object"
+                    },
+                    "Error": {
+                        "type": "string"
+                    },
+                    "Informational": {
+                        "type": "boolean"
+                    },
+                    "LatencyMS": {
+                        "type": "number"
+                    },
+                    "Name": {
+                        "type": "string"
+                    },
+                    "Status": {
+                        "$ref": "#/components/schemas/analytics-service_service_health.Status"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_health.Report": {
+                "properties": {
+                    "Dependencies": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_health.Dependency"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "Status": {
+                        "$ref": "#/components/schemas/analytics-service_service_health.Status"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_health.Status": {
+                "enum": [
+                    "up",
+                    "down"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "StatusUp",
+                    "StatusDown"
+                ]
+            },
+            "analytics-service_service_masking.Policy": {
+                "enum": [
+                    "full",
+                    "partial",
+                    "hashed",
+                    "dropped"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "PolicyFull",
+                    "PolicyPartial",
+                    "PolicyHashed",
+                    "PolicyDropped"
+                ]
+            },
+            "analytics-service_service_olap.Dimension": {
+                "enum": [
+                    "day",
+                    "week",
+                    "month",
+                    "brigade",
+                    "inspector",
+                    "inspection_type",
+                    "resolution",
+                    "subscriber_status",
+                    "district",
+                    "automaton"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "DimensionDay",
+                    "DimensionWeek",
+                    "DimensionMonth",
+                    "DimensionBrigade",
+                    "DimensionInspector",
+                    "DimensionInspectionType",
+                    "DimensionResolution",
+                    "DimensionSubscriberStatus",
+                    "DimensionDistrict",
+                    "DimensionAutomaton"
+                ]
+            },
+            "analytics-service_service_olap.Metric": {
+                "enum": [
+                    "count",
+                    "violations",
+                    "avg_duration",
+                    "median_duration",
+                    "p90_duration",
+                    "consumption_sum"
+                ],
+                "type": "string",
+                "x-enum-varnames": [
+                    "MetricCount",
+                    "MetricViolations",
+                    "MetricAvgDuration",
+                    "MetricMedianDuration",
+                    "MetricP90Duration",
+                    "MetricConsumptionSum"
+                ]
+            },
+            "analytics-service_service_olap.Result": {
+                "properties": {
+                    "Dimensions": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_olap.Dimension"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "Limit": {
+                        "type": "integer"
+                    },
+                    "Metrics": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_olap.Metric"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "Rows": {
+                        "items": {
+                            "$ref": "#/components/schemas/analytics-service_service_olap.Row"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "Truncated": {
+                        "type": "boolean"
+                    }
+                },
+                "type": "object"
+            },
+            "analytics-service_service_olap.Row": {
+                "properties": {
+                    "Dimensions": {
+                        "items": {
+                            "type": "string"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    },
+                    "Metrics": {
+                        "items": {
+                            "type": "number"
+                        },
+                        "type": "array",
+                        "uniqueItems": false
+                    }
+                },
+                "type": "object"
+            },
+            "gorouter.ErrorInfo": {
+                "properties": {
+                    "code": {
+                        "type": "string"
+                    },
+                    "message": {
+                        "type": "string"
+                    }
+                },
+                "type": "object"
+            },
+            "gorouter.ErrorResponse": {
+                "properties": {
+                    "error": {
+                        "$ref": "#/components/schemas/gorouter.ErrorInfo"
+                    }
+                },
+                "type": "object"
+            }
+        }
+    },
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "version": "{{.Version}}"
+    },
+    "externalDocs": {
+        "description": "",
+        "url": ""
+    },
+    "paths": {
+        "/anomalies": {
+            "get": {
+                "description": "Returns anomalies flagged by the anomaly rules, the latest months and the highest scores first.\nAccount numbers and addresses are masked according to the caller role. Only admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Filter by status",
+                        "in": "query",
+                        "name": "status",
+                        "schema": {
+                            "enum": [
+                                "new",
+                                "confirmed",
+                                "dismissed"
+                            ],
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by rule ID",
+                        "in": "query",
+                        "name": "ruleID",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Filter by object ID",
+                        "in": "query",
+                        "name": "objectID",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Maximum number of items to return; 0 means no limit",
+                        "in": "query",
+                        "name": "limit",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Number of items to skip",
+                        "in": "query",
+                        "name": "offset",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "items": {
+                                        "$ref": "#/components/schemas/analytics-service_service_anomaly.Anomaly"
+                                    },
+                                    "type": "array"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "List consumption anomalies",
+                "tags": [
+                    "anomalies"
+                ]
+            }
+        },
+        "/anomalies/evaluate": {
+            "post": {
+                "description": "Runs the anomaly rules now instead of waiting for the daily job, e.g. after a rule was changed. Only admins are allowed.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_anomaly.EvaluationResult"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Evaluate anomaly rules",
+                "tags": [
+                    "anomalies"
+                ]
+            }
+        },
+        "/anomalies/rules": {
+            "get": {
+                "description": "Returns the anomaly rules configured in Postgres. Only admins and analysts are allowed.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "items": {
+                                        "$ref": "#/components/schemas/analytics-service_service_anomaly.Rule"
+                                    },
+                                    "type": "array"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "List anomaly rules",
+                "tags": [
+                    "anomalies"
+                ]
+            }
+        },
+        "/anomalies/{id}/triage": {
+            "post": {
+                "description": "Sets the status of the anomaly, the caller is saved as the triager. Triaged anomalies are not changed by later evaluations.\nOnly admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Anomaly ID",
+                        "in": "path",
+                        "name": "id",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "New status",
+                        "in": "query",
+                        "name": "status",
+                        "required": true,
+                        "schema": {
+                            "enum": [
+                                "new",
+                                "confirmed",
+                                "dismissed"
+                            ],
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Analyst comment",
+                        "in": "query",
+                        "name": "comment",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_anomaly.Anomaly"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Triage anomaly",
+                "tags": [
+                    "anomalies"
+                ]
+            }
+        },
+        "/audit": {
+            "get": {
+                "description": "Returns report generation, access and download and subscriber erasure records, newest first. Only admins are allowed.",
+                "parameters": [
+                    {
+                        "description": "Filter by actor user ID",
+                        "in": "query",
+                        "name": "actorID",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Filter by action",
+                        "in": "query",
+                        "name": "action",
+                        "schema": {
+                            "enum": [
+                                "report_generate",
+                                "report_access",
+                                "report_download",
+                                "watch_list_access",
+                                "subscriber_erase"
+                            ],
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by report ID",
+                        "in": "query",
+                        "name": "reportID",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Inclusive start date in YYYY-MM-DD format",
+                        "in": "query",
+                        "name": "from",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Exclusive end date in YYYY-MM-DD format",
+                        "in": "query",
+                        "name": "to",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Maximum number of items to return; 0 means no limit",
+                        "in": "query",
+                        "name": "limit",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Number of items to skip",
+                        "in": "query",
+                        "name": "offset",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "items": {
+                                        "$ref": "#/components/schemas/analytics-service_service_audit.Record"
+                                    },
+                                    "type": "array"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "List audit records",
+                "tags": [
+                    "audit"
+                ]
+            }
+        },
+        "/comparison/{periodStart}/{periodEnd}": {
+            "get": {
+                "description": "Returns the main KPIs of the period and of the period it is compared with, with absolute and percentage deltas,\nin total, by inspection type and by brigade. Only admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Period start date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodStart",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Period end date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodEnd",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Period to compare with; previous by default, whole months are shifted by months",
+                        "in": "query",
+                        "name": "mode",
+                        "schema": {
+                            "enum": [
+                                "previous",
+                                "year",
+                                "custom"
+                            ],
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Start date of the custom period in YYYY-MM-DD format",
+                        "in": "query",
+                        "name": "compareStart",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "End date of the custom period in YYYY-MM-DD format",
+                        "in": "query",
+                        "name": "compareEnd",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_analytics.Comparison"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Compare KPIs of two periods",
+                "tags": [
+                    "comparison"
+                ]
+            }
+        },
+        "/devices/{deviceID}/readings": {
+            "get": {
+                "description": "Returns meter readings of the device in chronological order with deltas between visits, rollbacks and implausible jumps.\nAddresses and account numbers are masked according to the caller role. Only admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Device ID",
+                        "in": "path",
+                        "name": "deviceID",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_analytics.DeviceHistory"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Get device reading history",
+                "tags": [
+                    "devices"
+                ]
+            }
+        },
+        "/erasure": {
+            "get": {
+                "description": "Returns subscriber erasure requests of the caller's tenants, newest first. Only admins are allowed.",
+                "parameters": [
+                    {
+                        "description": "Maximum number of items to return; 0 means no limit",
+                        "in": "query",
+                        "name": "limit",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Number of items to skip",
+                        "in": "query",
+                        "name": "offset",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "items": {
+                                        "$ref": "#/components/schemas/analytics-service_service_erasure.Request"
+                                    },
+                                    "type": "array"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "List erasure requests",
+                "tags": [
+                    "erasure"
+                ]
+            }
+        },
+        "/erasure/subscribers/{subscriberID}": {
+            "post": {
+                "description": "Anonymizes personal data of the subscriber in the finished tasks and anomalies of the caller's tenants and records the request.\nRows are kept, so aggregate metrics don't change. Finished tasks and replay tables are rewritten by ClickHouse in the background.\nThe request is recorded as pending first and stays pending if the erasure fails, new tasks of the subscriber are anonymized on ingestion.\nOnly admins are allowed.",
+                "parameters": [
+                    {
+                        "description": "Subscriber ID",
+                        "in": "path",
+                        "name": "subscriberID",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Reason of the request, e.g. the application number",
+                        "in": "query",
+                        "name": "reason",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_erasure.Request"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Erase subscriber personal data",
+                "tags": [
+                    "erasure"
+                ]
+            }
+        },
+        "/forecast/tasks": {
+            "get": {
+                "description": "Predicts task counts per inspection type for the next days starting from today with 95% prediction intervals.\nThe forecast uses exponential smoothing with weekday offsets. Only admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Number of days to forecast; 0 means 14",
+                        "in": "query",
+                        "name": "days",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_forecast.Forecast"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Forecast daily tasks",
+                "tags": [
+                    "forecast"
+                ]
+            }
+        },
+        "/forecast/tasks/backtest": {
+            "get": {
+                "description": "Forecasts the last days before today from the earlier history with every method and compares the predictions\nwith the actual task counts. Only admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Number of past days to forecast; 0 means 14",
+                        "in": "query",
+                        "name": "days",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_forecast.Backtest"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Backtest daily tasks forecast",
+                "tags": [
+                    "forecast"
+                ]
+            }
+        },
+        "/health/live": {
+            "get": {
+                "description": "Reports that the service process is running. Dependencies are not checked.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_health.Report"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    }
+                },
+                "summary": "Liveness probe",
+                "tags": [
+                    "health"
+                ]
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Checks Postgres, ClickHouse and Kafka consumer lag. Peer services, if enabled, are informational and don't fail readiness.",
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_health.Report"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "503": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_health.Report"
+                                }
+                            }
+                        },
+                        "description": "Service Unavailable"
+                    }
+                },
+                "summary": "Readiness probe",
+                "tags": [
+                    "health"
+                ]
+            }
+        },
+        "/inspectors/{periodStart}/{periodEnd}": {
+            "get": {
+                "description": "Returns tasks, violations found, average duration, daily workload and brigades of every inspector for the period. Only admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Period start date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodStart",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Period end date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodEnd",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "items": {
+                                        "$ref": "#/components/schemas/analytics-service_service_analytics.InspectorPerformance"
+                                    },
+                                    "type": "array"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Get inspector performance",
+                "tags": [
+                    "inspectors"
+                ]
+            }
+        },
+        "/olap/query": {
+            "get": {
+                "description": "Aggregates metrics of finished tasks grouped by the chosen dimensions. Dimensions and metrics are comma-separated\nnames from the whitelist. Grouping or filtering by inspector counts a task for each inspector of its brigade.\nOnly admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Inclusive start date in YYYY-MM-DD format",
+                        "in": "query",
+                        "name": "from",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Exclusive end date in YYYY-MM-DD format",
+                        "in": "query",
+                        "name": "to",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Comma-separated dimensions: day, week, month, brigade, inspector, inspection_type, resolution, subscriber_status, district, automaton",
+                        "in": "query",
+                        "name": "dimensions",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Comma-separated metrics: count, violations, avg_duration, median_duration, p90_duration, consumption_sum",
+                        "in": "query",
+                        "name": "metrics",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by brigade ID",
+                        "in": "query",
+                        "name": "brigadeID",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Filter by inspector ID",
+                        "in": "query",
+                        "name": "inspectorID",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Filter by inspection type",
+                        "in": "query",
+                        "name": "inspectionType",
+                        "schema": {
+                            "enum": [
+                                "unknown",
+                                "limitation",
+                                "resumption",
+                                "verification",
+                                "unauthorized_connection"
+                            ],
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by inspection resolution",
+                        "in": "query",
+                        "name": "resolution",
+                        "schema": {
+                            "enum": [
+                                "unknown",
+                                "limited",
+                                "stopped",
+                                "resumed"
+                            ],
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by subscriber status",
+                        "in": "query",
+                        "name": "subscriberStatus",
+                        "schema": {
+                            "enum": [
+                                "unknown",
+                                "active",
+                                "violator",
+                                "archived"
+                            ],
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by district, the first part of the object address",
+                        "in": "query",
+                        "name": "district",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Filter by automaton presence",
+                        "in": "query",
+                        "name": "haveAutomaton",
+                        "schema": {
+                            "type": "boolean"
+                        }
+                    },
+                    {
+                        "description": "Maximum number of rows to return; 0 means the configured maximum",
+                        "in": "query",
+                        "name": "limit",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_olap.Result"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Query task metrics",
+                "tags": [
+                    "olap"
+                ]
+            }
+        },
+        "/punctuality/{periodStart}/{periodEnd}": {
+            "get": {
+                "description": "Compares task start with the planned visit time: on-time share, median and p90 delay, delay distribution in total and per brigade, inspector, district and day.\nOnly admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Period start date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodStart",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Period end date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodEnd",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_analytics.Punctuality"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Get visit punctuality",
+                "tags": [
+                    "punctuality"
+                ]
+            }
+        },
+        "/quarantine": {
+            "get": {
+                "description": "Returns finished task events that failed validation and are not repaired yet. Only admins are allowed.",
+                "parameters": [
+                    {
+                        "description": "Maximum number of items to return; 0 means no limit",
+                        "in": "query",
+                        "name": "limit",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Number of items to skip",
+                        "in": "query",
+                        "name": "offset",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "items": {
+                                        "$ref": "#/components/schemas/analytics-service_service_analytics.QuarantinedTask"
+                                    },
+                                    "type": "array"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "List quarantined tasks",
+                "tags": [
+                    "quarantine"
+                ]
+            }
+        },
+        "/quarantine/dead-letters": {
+            "get": {
+                "description": "Returns consumed messages that could not be handled, newest first. Payload is the raw Kafka message. Only admins are allowed.",
+                "parameters": [
+                    {
+                        "description": "Maximum number of items to return; 0 means no limit",
+                        "in": "query",
+                        "name": "limit",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Number of items to skip",
+                        "in": "query",
+                        "name": "offset",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "items": {
+                                        "$ref": "#/components/schemas/analytics-service_service_analytics.DeadLetter"
+                                    },
+                                    "type": "array"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "List dead letters",
+                "tags": [
+                    "quarantine"
+                ]
+            }
+        },
+        "/quarantine/repair": {
+            "post": {
+                "description": "Re-ingests quarantined tasks with fresh upstream data. Incomplete or failed tasks stay in quarantine, stored tasks aren't inserted again. Only admins are allowed.",
+                "parameters": [
+                    {
+                        "description": "Quarantined task ID; all pending tasks are repaired if omitted",
+                        "in": "query",
+                        "name": "id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_analytics.RepairResult"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Repair quarantined tasks",
+                "tags": [
+                    "quarantine"
+                ]
+            }
+        },
+        "/replay": {
+            "get": {
+                "description": "Returns replay jobs with their status, the latest first. Tables of jobs finished a week ago are dropped and the jobs become expired. Only admins are allowed.",
+                "parameters": [
+                    {
+                        "description": "Replay job ID; all jobs are returned if omitted",
+                        "in": "query",
+                        "name": "id",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Maximum number of items to return; 0 means no limit",
+                        "in": "query",
+                        "name": "limit",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Number of items to skip",
+                        "in": "query",
+                        "name": "offset",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "items": {
+                                        "$ref": "#/components/schemas/analytics-service_service_analytics.ReplayJob"
+                                    },
+                                    "type": "array"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "List replay jobs",
+                "tags": [
+                    "replay"
+                ]
+            },
+            "post": {
+                "description": "Queues a job that re-runs task events received in the period through the ingestion pipeline into a new table. GET /replay shows its status. Only admins are allowed.",
+                "parameters": [
+                    {
+                        "description": "Inclusive start date in YYYY-MM-DD format",
+                        "in": "query",
+                        "name": "from",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Exclusive end date in YYYY-MM-DD format",
+                        "in": "query",
+                        "name": "to",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_analytics.ReplayJob"
+                                }
+                            }
+                        },
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Replay archived task events",
+                "tags": [
+                    "replay"
+                ]
+            }
+        },
+        "/reports": {
+            "get": {
+                "description": "Returns all generated analytics reports. File URLs are empty, download links are issued by /reports/{reportID}/files/{fileID}.\nReports masked less strictly than the caller's own reports of the type are left out. Only admins, analysts and contractors are allowed.",
+                "parameters": [
+                    {
+                        "description": "Maximum number of items to return; 0 means no limit",
+                        "in": "query",
+                        "name": "limit",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    {
+                        "description": "Number of items to skip",
+                        "in": "query",
+                        "name": "offset",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "items": {
+                                        "$ref": "#/components/schemas/analytics-service_service_analytics.Report"
+                                    },
+                                    "type": "array"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "List reports",
+                "tags": [
+                    "reports"
+                ]
+            }
+        },
+        "/reports/basic/{periodStart}/{periodEnd}": {
+            "post": {
+                "description": "Generates a basic analytics report for the inclusive date period. Subscriber personal data is masked according to the caller role.\nOnly admins, analysts and contractors are allowed.",
+                "parameters": [
+                    {
+                        "description": "Period start date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodStart",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Period end date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodEnd",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_analytics.Report"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Create basic report",
+                "tags": [
+                    "reports"
+                ]
+            }
+        },
+        "/reports/comparison/{periodStart}/{periodEnd}": {
+            "post": {
+                "description": "Generates an XLSX comparison of the KPIs of two periods with increases and decreases highlighted.\nOnly admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Period start date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodStart",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Period end date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodEnd",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Period to compare with; previous by default, whole months are shifted by months",
+                        "in": "query",
+                        "name": "mode",
+                        "schema": {
+                            "enum": [
+                                "previous",
+                                "year",
+                                "custom"
+                            ],
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Start date of the custom period in YYYY-MM-DD format",
+                        "in": "query",
+                        "name": "compareStart",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "End date of the custom period in YYYY-MM-DD format",
+                        "in": "query",
+                        "name": "compareEnd",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_analytics.Report"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Create comparison report",
+                "tags": [
+                    "reports"
+                ]
+            }
+        },
+        "/reports/devices/{deviceID}": {
+            "post": {
+                "description": "Generates an XLSX passport of the device with its reading timeline, rollbacks and jumps.\nThe report period spans the first and the last readings. Only admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Device ID",
+                        "in": "path",
+                        "name": "deviceID",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_analytics.Report"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Create device passport report",
+                "tags": [
+                    "reports"
+                ]
+            }
+        },
+        "/reports/inspectors/{periodStart}/{periodEnd}": {
+            "post": {
+                "description": "Generates an XLSX report with per-inspector metrics and daily workload for the period. Only admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Period start date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodStart",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Period end date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodEnd",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_analytics.Report"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Create inspector report",
+                "tags": [
+                    "reports"
+                ]
+            }
+        },
+        "/reports/punctuality/{periodStart}/{periodEnd}": {
+            "post": {
+                "description": "Generates an XLSX report with visit punctuality in total and per brigade, inspector, district and day. Only admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Period start date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodStart",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Period end date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodEnd",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_analytics.Report"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "Create punctuality report",
+                "tags": [
+                    "reports"
+                ]
+            }
+        },
+        "/reports/watchlist/{periodStart}/{periodEnd}": {
+            "post": {
+                "description": "Generates an XLSX watch-list for the security department. The report is also created weekly by cron. Only admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Period start date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodStart",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "description": "Period end date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodEnd",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_service_analytics.Report"
+                                }
+                            }
                         },
-                        "type": "array",
-                        "uniqueItems": false
-                    },
-                    "ID": {
-                        "type": "integer"
+                        "description": "OK"
                     },
-                    "PeriodEnd": {
-                        "type": "string"
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
                     },
-                    "PeriodStart": {
-                        "type": "string"
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
                     },
-                    "Type": {
-                        "$ref": "#/components/schemas/analytics-service_service_analytics.ReportType"
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
                     }
                 },
-                "type": "object"
-            },
-            "analytics-service_service_analytics.ReportType": {
-                "enum": [
-                    0,
-                    1
-                ],
-                "type": "integer",
-                "x-enum-varnames": [
-                    "ReportTypeUnknown",
-                    "ReportTypeBasic"
+                "summary": "Create watch-list report",
+                "tags": [
+                    "reports"
                 ]
-            },
-            "gorouter.ErrorInfo": {
-                "properties": {
-                    "code": {
-                        "type": "string"
+            }
+        },
+        "/reports/{reportID}/files/{fileID}": {
+            "get": {
+                "description": "Returns a report file with its download URL, the download is audited.\nThe report is checked like in the report list. Only admins, analysts and contractors are allowed.",
+                "parameters": [
+                    {
+                        "description": "Report ID",
+                        "in": "path",
+                        "name": "reportID",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
                     },
-                    "message": {
-                        "type": "string"
+                    {
+                        "description": "File ID",
+                        "in": "path",
+                        "name": "fileID",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
                     }
-                },
-                "type": "object"
-            },
-            "gorouter.ErrorResponse": {
-                "properties": {
-                    "error": {
-                        "$ref": "#/components/schemas/gorouter.ErrorInfo"
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/analytics-service_cluster_file.File"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
                     }
                 },
-                "type": "object"
+                "summary": "Get report file",
+                "tags": [
+                    "reports"
+                ]
             }
-        }
-    },
-    "info": {
-        "description": "{{escape .Description}}",
-        "title": "{{.Title}}",
-        "version": "{{.Version}}"
-    },
-    "externalDocs": {
-        "description": "",
-        "url": ""
-    },
-    "paths": {
-        "/reports": {
+        },
+        "/turnaround/open": {
             "get": {
-                "description": "Returns all generated analytics reports.",
+                "description": "Returns limitations that were not resumed for more than olderThanDays days, the oldest first.\nAddresses and account numbers are masked according to the caller role. Only admins and analysts are allowed.",
                 "parameters": [
                     {
-                        "description": "Maximum number of items to return; 0 means no limit",
+                        "description": "Minimum age of a limitation in days; 0 means 30",
                         "in": "query",
-                        "name": "limit",
+                        "name": "olderThanDays",
                         "schema": {
                             "type": "integer"
                         }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "items": {
+                                        "$ref": "#/components/schemas/analytics-service_service_analytics.LimitationTurnaround"
+                                    },
+                                    "type": "array"
+                                }
+                            }
+                        },
+                        "description": "OK"
+                    },
+                    "400": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Bad Request"
+                    },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
+                    "500": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Internal Server Error"
+                    }
+                },
+                "summary": "List open limitations",
+                "tags": [
+                    "turnaround"
+                ]
+            }
+        },
+        "/turnaround/{periodStart}/{periodEnd}": {
+            "get": {
+                "description": "Pairs each limitation made in the period with the next resumption of the same object and returns per object time disconnected,\nopen limitations and refusals of access. Addresses are masked according to the caller role. Only admins and analysts are allowed.",
+                "parameters": [
+                    {
+                        "description": "Period start date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodStart",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
                     },
                     {
-                        "description": "Number of items to skip",
-                        "in": "query",
-                        "name": "offset",
+                        "description": "Period end date in YYYY-MM-DD format",
+                        "in": "path",
+                        "name": "periodEnd",
+                        "required": true,
                         "schema": {
-                            "type": "integer"
+                            "type": "string"
                         }
                     }
                 ],
@@ -135,7 +3626,7 @@ const docTemplate = `{
                             "application/json": {
                                 "schema": {
                                     "items": {
-                                        "$ref": "#/components/schemas/analytics-service_service_analytics.Report"
+                                        "$ref": "#/components/schemas/analytics-service_service_analytics.ObjectTurnaround"
                                     },
                                     "type": "array"
                                 }
@@ -153,6 +3644,16 @@ const docTemplate = `{
                         },
                         "description": "Bad Request"
                     },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
                     "500": {
                         "content": {
                             "application/json": {
@@ -164,15 +3665,15 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 },
-                "summary": "List reports",
+                "summary": "Get limitation turnaround per object",
                 "tags": [
-                    "reports"
+                    "turnaround"
                 ]
             }
         },
-        "/reports/basic/{periodStart}/{periodEnd}": {
-            "post": {
-                "description": "Generates a basic analytics report for the inclusive date period.",
+        "/watchlist/{periodStart}/{periodEnd}": {
+            "get": {
+                "description": "Returns subscribers and objects ranked by the recidivism rules fired in the period with the supporting task IDs.\nSubscriber personal data is masked according to the caller role. Only admins and analysts are allowed.",
                 "parameters": [
                     {
                         "description": "Period start date in YYYY-MM-DD format",
@@ -198,7 +3699,7 @@ const docTemplate = `{
                         "content": {
                             "application/json": {
                                 "schema": {
-                                    "$ref": "#/components/schemas/analytics-service_service_analytics.Report"
+                                    "$ref": "#/components/schemas/analytics-service_service_analytics.WatchList"
                                 }
                             }
                         },
@@ -214,6 +3715,16 @@ const docTemplate = `{
                         },
                         "description": "Bad Request"
                     },
+                    "403": {
+                        "content": {
+                            "application/json": {
+                                "schema": {
+                                    "$ref": "#/components/schemas/gorouter.ErrorResponse"
+                                }
+                            }
+                        },
+                        "description": "Forbidden"
+                    },
                     "500": {
                         "content": {
                             "application/json": {
@@ -225,9 +3736,9 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 },
-                "summary": "Create basic report",
+                "summary": "Get recidivism watch-list",
                 "tags": [
-                    "reports"
+                    "watchlist"
                 ]
             }
         }
@@ -33,6 +33,11 @@ func main() {
 
 	app := NewApp(mainCtx, log, settings)
 
+	if err = app.InitTracing(); err != nil {
+		log.Errorf("failed to init tracing: %v", err)
+		return
+	}
+
 	if err = app.InitDatabases(os.DirFS("./"), "database/migrations"); err != nil {
 		log.Errorf("failed to init databases: %v", err)
 		return
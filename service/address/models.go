@@ -0,0 +1,34 @@
+package address
+
+// Address is an object address split into parts. Parts missing in the source address are empty.
+type Address struct {
+	Region   string `json:"Region"`
+	City     string `json:"City"`
+	District string `json:"District"`
+	Street   string `json:"Street"`
+	House    string `json:"House"`
+	Flat     string `json:"Flat"`
+}
+
+type kind int
+
+const (
+	kindNone kind = iota
+	kindRegion
+	kindCity
+	kindDistrict
+	kindStreet
+	kindHouse
+	kindFlat
+	kindNumber
+)
+
+type marker struct {
+	text string
+	kind kind
+}
+
+type entry struct {
+	name string
+	kind kind
+}
@@ -0,0 +1,176 @@
+package address
+
+import (
+	"analytics-service/config"
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	postcodeRegexp = regexp.MustCompile(`^\d{6}$`)
+	numberRegexp   = regexp.MustCompile(`(?i)^\d+\s?[\p{L}]?(/\d+\s?[\p{L}]?)?(\s?(к|корп\.?|стр\.?)\s?\d+)?$`)
+)
+
+// Parser splits addresses by the configured markers and dictionary. It's safe for concurrent use.
+type Parser struct {
+	markers    []marker
+	dictionary map[string]entry
+}
+
+func NewParser(settings config.Address) *Parser {
+	p := &Parser{
+		dictionary: make(map[string]entry),
+	}
+
+	p.addMarkers(kindRegion, settings.Markers.Region)
+	p.addMarkers(kindCity, settings.Markers.City)
+	p.addMarkers(kindDistrict, settings.Markers.District)
+	p.addMarkers(kindStreet, settings.Markers.Street)
+	p.addMarkers(kindHouse, settings.Markers.House)
+	p.addMarkers(kindFlat, settings.Markers.Flat)
+
+	// Longer markers go first, so "р-н." is cut whole rather than as "р-н".
+	slices.SortStableFunc(p.markers, func(a, b marker) int {
+		return cmp.Compare(len(b.text), len(a.text))
+	})
+
+	p.addEntries(kindRegion, settings.Dictionary.Regions)
+	p.addEntries(kindCity, settings.Dictionary.Cities)
+	p.addEntries(kindDistrict, settings.Dictionary.Districts)
+
+	return p
+}
+
+func (p *Parser) addMarkers(k kind, markers []string) {
+	for _, m := range markers {
+		if m = strings.TrimSpace(m); m != "" {
+			p.markers = append(p.markers, marker{text: m, kind: k})
+		}
+	}
+}
+
+func (p *Parser) addEntries(k kind, entries []config.AddressEntry) {
+	for _, e := range entries {
+		for _, spelling := range append([]string{e.Name}, e.Aliases...) {
+			p.dictionary[normalize(spelling)] = entry{name: e.Name, kind: k}
+		}
+	}
+}
+
+// Parse classifies every comma separated part of the address, the first part of a kind wins. Postcodes are skipped,
+// a bare number is the house and the next one is the flat. Markers are kept in regions, districts and streets and
+// cut from cities, houses and flats. If no part is marked as a district, the last unclassified part before the street
+// that follows the region and the city is taken as the district, as districts are often written without a marker.
+func (p *Parser) Parse(address string) Address {
+	var (
+		result    Address
+		candidate string
+	)
+
+	for _, part := range strings.Split(address, ",") {
+		part = strings.Join(strings.Fields(part), " ")
+		if part == "" || postcodeRegexp.MatchString(part) {
+			continue
+		}
+
+		k, value := p.classify(part)
+		switch k {
+		case kindRegion:
+			setOnce(&result.Region, value)
+			candidate = ""
+		case kindCity:
+			setOnce(&result.City, value)
+			candidate = ""
+		case kindDistrict:
+			setOnce(&result.District, value)
+		case kindStreet:
+			setOnce(&result.Street, value)
+		case kindHouse:
+			setOnce(&result.House, value)
+		case kindFlat:
+			setOnce(&result.Flat, value)
+		case kindNumber:
+			if result.House == "" {
+				result.House = value
+			} else {
+				setOnce(&result.Flat, value)
+			}
+		case kindNone:
+			if result.Street == "" && result.House == "" {
+				candidate = value
+			}
+		}
+	}
+
+	if result.District == "" {
+		result.District = candidate
+	}
+
+	return result
+}
+
+func (p *Parser) classify(part string) (kind, string) {
+	if e, ok := p.dictionary[normalize(part)]; ok {
+		return e.kind, e.name
+	}
+
+	for _, m := range p.markers {
+		rest, ok := cutMarker(part, m.text)
+		if !ok {
+			continue
+		}
+
+		if e, ok := p.dictionary[normalize(rest)]; ok && e.kind == m.kind {
+			return e.kind, e.name
+		}
+
+		switch m.kind {
+		case kindCity, kindHouse, kindFlat:
+			return m.kind, rest
+		default:
+			return m.kind, part
+		}
+	}
+
+	if numberRegexp.MatchString(part) {
+		return kindNumber, part
+	}
+
+	return kindNone, part
+}
+
+// cutMarker removes the marker from the start or the end of the part. The marker must be separated by a space,
+// unless it ends with a dot, like in "г.Москва".
+func cutMarker(part, m string) (string, bool) {
+	if len(part) <= len(m) {
+		return "", false
+	}
+
+	if head := part[:len(m)]; strings.EqualFold(head, m) {
+		rest := part[len(m):]
+		if strings.HasPrefix(rest, " ") || strings.HasSuffix(m, ".") {
+			return strings.TrimSpace(rest), true
+		}
+	}
+
+	if tail := part[len(part)-len(m):]; strings.EqualFold(tail, m) {
+		rest := part[:len(part)-len(m)]
+		if strings.HasSuffix(rest, " ") {
+			return strings.TrimSpace(rest), true
+		}
+	}
+
+	return "", false
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+func setOnce(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
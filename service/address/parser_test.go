@@ -0,0 +1,58 @@
+package address
+
+import (
+	"analytics-service/config"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	parser := NewParser(config.Address{
+		Markers: config.AddressMarkers{
+			Region:   []string{"обл.", "область"},
+			City:     []string{"г.", "город"},
+			District: []string{"р-н", "район"},
+			Street:   []string{"ул.", "пр-т"},
+			House:    []string{"д."},
+			Flat:     []string{"кв.", "оф."},
+		},
+		Dictionary: config.AddressDictionary{
+			Cities:    []config.AddressEntry{{Name: "Екатеринбург", Aliases: []string{"Екб"}}},
+			Districts: []config.AddressEntry{{Name: "Кировский р-н", Aliases: []string{"Кировский район"}}},
+		},
+	})
+
+	tests := []struct {
+		address string
+		want    Address
+	}{
+		{
+			address: "Кировский р-н, ул. Ленина, 1",
+			want:    Address{District: "Кировский р-н", Street: "ул. Ленина", House: "1"},
+		},
+		{
+			address: "620000, Свердловская обл., г.Екб, Кировский район, ул. Ленина, д. 5/1, кв. 12",
+			want: Address{
+				Region: "Свердловская обл.", City: "Екатеринбург", District: "Кировский р-н", Street: "ул. Ленина", House: "5/1",
+				Flat: "12",
+			},
+		},
+		{
+			address: "г. Тула, Центральный, пр-т  Ленина, 10А, 3",
+			want:    Address{City: "Тула", District: "Центральный", Street: "пр-т Ленина", House: "10А", Flat: "3"},
+		},
+		{
+			address: "Россия, Екатеринбург, ул. Мира, 7 к 2, оф. 4",
+			want:    Address{City: "Екатеринбург", Street: "ул. Мира", House: "7 к 2", Flat: "4"},
+		},
+		{
+			address: "",
+			want:    Address{},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := parser.Parse(tt.address); got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.address, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package analytics
+
+import (
+	"analytics-service/tracing"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// batcher collects rows and inserts them at once. Rows are acknowledged only after they are inserted,
+// a failed insert keeps the rows for the next flush.
+type batcher[T any] struct {
+	table  string
+	insert func(ctx context.Context, rows []T) error
+	size   int
+	maxAge time.Duration
+
+	mu      sync.Mutex
+	rows    []T
+	acks    []func()
+	links   []trace.Link
+	firstAt time.Time
+}
+
+func newBatcher[T any](table string, insert func(ctx context.Context, rows []T) error, size int, maxAge time.Duration) *batcher[T] {
+	return &batcher[T]{
+		table:  table,
+		insert: insert,
+		size:   max(size, 1),
+		maxAge: maxAge,
+	}
+}
+
+// Add flushes the batch once it's full. While the batch can't be flushed Add retries and blocks the caller,
+// so consuming slows down instead of piling up rows in memory.
+func (b *batcher[T]) Add(ctx context.Context, row T, ack func()) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.rows) == 0 {
+		b.firstAt = time.Now()
+	}
+
+	b.rows = append(b.rows, row)
+	b.acks = append(b.acks, ack)
+	b.links = append(b.links, trace.LinkFromContext(ctx))
+
+	for len(b.rows) >= b.size {
+		err := b.flush(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if waitErr := wait(ctx, eventRetryBackoff); waitErr != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FlushExpired flushes the batch if its oldest row waits longer than maxAge.
+func (b *batcher[T]) FlushExpired(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.rows) == 0 || time.Since(b.firstAt) < b.maxAge {
+		return nil
+	}
+
+	return b.flush(ctx)
+}
+
+func (b *batcher[T]) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.flush(ctx)
+}
+
+func (b *batcher[T]) flush(ctx context.Context) error {
+	if len(b.rows) == 0 {
+		return nil
+	}
+
+	// The insert has many parents, so it's a separate trace linked to the traces of its rows.
+	ctx, span := tracer.Start(ctx, "insert batch", trace.WithNewRoot(), trace.WithLinks(b.links...),
+		trace.WithAttributes(attribute.String("db.collection.name", b.table), attribute.Int("db.operation.batch.size", len(b.rows))))
+
+	start := time.Now()
+	err := b.insert(ctx, b.rows)
+	observeDuration(insertDuration, b.table, start)
+	tracing.End(span, err)
+
+	if err != nil {
+		return fmt.Errorf("insert %d rows: %w", len(b.rows), err)
+	}
+
+	for _, ack := range b.acks {
+		ack()
+	}
+
+	b.rows, b.acks, b.links = nil, nil, nil
+
+	return nil
+}
+
+// ackAfter calls ack once the returned function is called n times.
+func ackAfter(n int32, ack func()) func() {
+	var left atomic.Int32
+	left.Store(n)
+
+	return func() {
+		if left.Add(-1) == 0 {
+			ack()
+		}
+	}
+}
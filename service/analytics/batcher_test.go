@@ -0,0 +1,102 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBatcherFlushesFullBatch(t *testing.T) {
+	var inserted [][]int
+	b := newBatcher("test", func(_ context.Context, rows []int) error {
+		inserted = append(inserted, slices.Clone(rows))
+		return nil
+	}, 2, time.Hour)
+
+	acked := 0
+	ack := func() { acked++ }
+
+	if err := b.Add(context.Background(), 1, ack); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(inserted) != 0 || acked != 0 {
+		t.Fatalf("expected nothing to be inserted before the batch is full, got %v", inserted)
+	}
+
+	if err := b.Add(context.Background(), 2, ack); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(inserted) != 1 || !slices.Equal(inserted[0], []int{1, 2}) || acked != 2 {
+		t.Fatalf("expected [1 2] to be inserted and acked, got %v (acked = %d)", inserted, acked)
+	}
+}
+
+func TestBatcherAcksOnlyAfterInsert(t *testing.T) {
+	fail := true
+	var inserted []int
+	b := newBatcher("test", func(_ context.Context, rows []int) error {
+		if fail {
+			return errors.New("clickhouse is down")
+		}
+
+		inserted = append(inserted, rows...)
+		return nil
+	}, 10, time.Hour)
+
+	acked := false
+	if err := b.Add(context.Background(), 1, func() { acked = true }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := b.Flush(context.Background()); err == nil {
+		t.Fatal("expected the insert error")
+	}
+	if acked {
+		t.Fatal("expected the row not to be acked after a failed insert")
+	}
+
+	fail = false
+	if err := b.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !acked || !slices.Equal(inserted, []int{1}) {
+		t.Fatalf("expected the kept row to be inserted and acked, got %v (acked = %v)", inserted, acked)
+	}
+}
+
+func TestBatcherFlushExpiredWaitsForMaxAge(t *testing.T) {
+	inserts := 0
+	b := newBatcher("test", func(context.Context, []int) error {
+		inserts++
+		return nil
+	}, 10, 20*time.Millisecond)
+
+	_ = b.Add(context.Background(), 1, func() {})
+
+	if err := b.FlushExpired(context.Background()); err != nil || inserts != 0 {
+		t.Fatalf("expected a young batch to be kept, got %d inserts (err = %v)", inserts, err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if err := b.FlushExpired(context.Background()); err != nil || inserts != 1 {
+		t.Fatalf("expected an expired batch to be inserted, got %d inserts (err = %v)", inserts, err)
+	}
+}
+
+func TestAckAfterWaitsForAllBatches(t *testing.T) {
+	acked := 0
+	ack := ackAfter(2, func() { acked++ })
+
+	ack()
+	if acked != 0 {
+		t.Fatal("expected the message not to be acked before both rows are inserted")
+	}
+
+	ack()
+	if acked != 1 {
+		t.Fatalf("expected the message to be acked once, got %d", acked)
+	}
+}
@@ -0,0 +1,407 @@
+package analytics
+
+import (
+	"analytics-service/cluster/file"
+	"analytics-service/service/auth"
+	"analytics-service/tracing"
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/golog"
+	"github.com/sunshineOfficial/golib/gotime"
+	"github.com/xuri/excelize/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	metricTasksCount                 = "tasks_count"
+	metricViolationsDetectedCount    = "violations_detected_count"
+	metricUnauthorizedConsumersCount = "unauthorized_consumers_count"
+	metricAvgDurationMinutes         = "avg_duration_minutes"
+	metricSuccessfulLimitations      = "successful_limitations_count"
+	metricSuccessfulResumptions      = "successful_resumptions_count"
+)
+
+var (
+	totalMetrics   = []string{metricTasksCount, metricViolationsDetectedCount, metricUnauthorizedConsumersCount, metricAvgDurationMinutes}
+	brigadeMetrics = []string{
+		metricTasksCount, metricAvgDurationMinutes, metricSuccessfulLimitations, metricSuccessfulResumptions, metricViolationsDetectedCount,
+	}
+	inspectionTypeKeys = []string{"limitation", "resumption", "verification", "unauthorized_connection"}
+)
+
+// PreviousPeriod returns the period to compare the current one with. Custom periods are given explicitly.
+func PreviousPeriod(current Period, mode ComparisonMode) (Period, error) {
+	switch mode {
+	case ComparisonModePrevious:
+		if months := wholeMonths(current); months > 0 {
+			return Period{Start: current.Start.AddDate(0, -months, 0), End: current.Start}, nil
+		}
+
+		return Period{Start: current.Start.Add(-current.End.Sub(current.Start)), End: current.Start}, nil
+	case ComparisonModeYear:
+		return Period{Start: current.Start.AddDate(-1, 0, 0), End: current.End.AddDate(-1, 0, 0)}, nil
+	default:
+		return Period{}, fmt.Errorf("unknown comparison mode: %q", mode)
+	}
+}
+
+// wholeMonths returns the number of calendar months in the period or 0 if its bounds are not the first days of months.
+func wholeMonths(p Period) int {
+	if p.Start.Day() != 1 || p.End.Day() != 1 {
+		return 0
+	}
+
+	return (p.End.Year()-p.Start.Year())*12 + int(p.End.Month()) - int(p.Start.Month())
+}
+
+func (s *Service) GetComparison(ctx goctx.Context, current, previous Period) (Comparison, error) {
+	currentData, err := s.getComparisonData(ctx, current)
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	previousData, err := s.getComparisonData(ctx, previous)
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	return ComputeComparison(currentData, previousData), nil
+}
+
+// ComparisonData is the daily KPIs of a period taken from the BI views.
+type ComparisonData struct {
+	Period   Period
+	Tasks    []TasksDaily
+	Brigades []BrigadeDay
+}
+
+func (s *Service) getComparisonData(ctx goctx.Context, p Period) (ComparisonData, error) {
+	start, end, err := reportPeriod(p.Start, p.End)
+	if err != nil {
+		return ComparisonData{}, err
+	}
+
+	scope := auth.FromContext(ctx).TenantScope()
+
+	tasks, err := s.repository.GetTasksDailyByPeriod(ctx, scope, start, end)
+	if err != nil {
+		return ComparisonData{}, fmt.Errorf("get tasks daily: %w", err)
+	}
+
+	brigades, err := s.repository.GetBrigadeDaysByPeriod(ctx, scope, start, end)
+	if err != nil {
+		return ComparisonData{}, fmt.Errorf("get brigade days: %w", err)
+	}
+
+	return ComparisonData{Period: Period{Start: start, End: end}, Tasks: tasks, Brigades: brigades}, nil
+}
+
+// ComputeComparison sums the daily KPIs of both periods and compares them in total, by inspection type and by brigade.
+// Average durations are weighted by the number of tasks of each day.
+func ComputeComparison(current, previous ComparisonData) Comparison {
+	currentTotal, currentTypes := sumTasksDaily(current.Tasks)
+	previousTotal, previousTypes := sumTasksDaily(previous.Tasks)
+
+	comparison := Comparison{
+		Current:          current.Period,
+		Previous:         previous.Period,
+		Total:            compareMetrics(totalMetrics, currentTotal, previousTotal),
+		ByInspectionType: make([]ComparisonGroup, 0, len(inspectionTypeKeys)),
+		ByBrigade:        make([]ComparisonGroup, 0),
+	}
+
+	for _, key := range inspectionTypeKeys {
+		comparison.ByInspectionType = append(comparison.ByInspectionType, ComparisonGroup{
+			Key:     key,
+			Metrics: compareMetrics([]string{metricTasksCount}, currentTypes[key], previousTypes[key]),
+		})
+	}
+
+	currentBrigades, previousBrigades := sumBrigadeDays(current.Brigades), sumBrigadeDays(previous.Brigades)
+
+	brigadeIDs := make([]int, 0, len(currentBrigades)+len(previousBrigades))
+	for _, brigades := range []map[int]map[string]float64{currentBrigades, previousBrigades} {
+		for id := range brigades {
+			if !slices.Contains(brigadeIDs, id) {
+				brigadeIDs = append(brigadeIDs, id)
+			}
+		}
+	}
+	slices.Sort(brigadeIDs)
+
+	for _, id := range brigadeIDs {
+		comparison.ByBrigade = append(comparison.ByBrigade, ComparisonGroup{
+			Key:     strconv.Itoa(id),
+			Metrics: compareMetrics(brigadeMetrics, currentBrigades[id], previousBrigades[id]),
+		})
+	}
+
+	return comparison
+}
+
+func sumTasksDaily(days []TasksDaily) (map[string]float64, map[string]map[string]float64) {
+	total := make(map[string]float64)
+	byType := make(map[string]map[string]float64, len(inspectionTypeKeys))
+	for _, key := range inspectionTypeKeys {
+		byType[key] = make(map[string]float64)
+	}
+
+	var totalDuration float64
+	for _, d := range days {
+		total[metricTasksCount] += float64(d.TasksCount)
+		total[metricViolationsDetectedCount] += float64(d.ViolationsDetectedCount)
+		total[metricUnauthorizedConsumersCount] += float64(d.UnauthorizedConsumersCount)
+		totalDuration += d.AvgDurationMinutes * float64(d.TasksCount)
+
+		byType["limitation"][metricTasksCount] += float64(d.LimitationCount)
+		byType["resumption"][metricTasksCount] += float64(d.ResumptionCount)
+		byType["verification"][metricTasksCount] += float64(d.VerificationCount)
+		byType["unauthorized_connection"][metricTasksCount] += float64(d.UnauthorizedConnectionCount)
+	}
+
+	if total[metricTasksCount] > 0 {
+		total[metricAvgDurationMinutes] = round2(totalDuration / total[metricTasksCount])
+	}
+
+	return total, byType
+}
+
+func sumBrigadeDays(days []BrigadeDay) map[int]map[string]float64 {
+	brigades := make(map[int]map[string]float64)
+	durations := make(map[int]float64)
+
+	for _, d := range days {
+		b, ok := brigades[d.BrigadeID]
+		if !ok {
+			b = make(map[string]float64)
+			brigades[d.BrigadeID] = b
+		}
+
+		b[metricTasksCount] += float64(d.TasksCount)
+		b[metricSuccessfulLimitations] += float64(d.SuccessfulLimitationsCount)
+		b[metricSuccessfulResumptions] += float64(d.SuccessfulResumptionsCount)
+		b[metricViolationsDetectedCount] += float64(d.ViolationsDetectedCount)
+		durations[d.BrigadeID] += d.AvgDurationMinutes * float64(d.TasksCount)
+	}
+
+	for id, b := range brigades {
+		if b[metricTasksCount] > 0 {
+			b[metricAvgDurationMinutes] = round2(durations[id] / b[metricTasksCount])
+		}
+	}
+
+	return brigades
+}
+
+func compareMetrics(names []string, current, previous map[string]float64) []ComparisonMetric {
+	metrics := make([]ComparisonMetric, 0, len(names))
+	for _, name := range names {
+		m := ComparisonMetric{
+			Name:     name,
+			Current:  current[name],
+			Previous: previous[name],
+			Delta:    round2(current[name] - previous[name]),
+		}
+		if m.Previous != 0 {
+			percent := round2(m.Delta / m.Previous * 100)
+			m.DeltaPercent = &percent
+		}
+
+		metrics = append(metrics, m)
+	}
+
+	return metrics
+}
+
+func comparisonMetricTitle(name string) string {
+	switch name {
+	case metricTasksCount:
+		return "Задач"
+	case metricViolationsDetectedCount:
+		return "Выявлено нарушений"
+	case metricUnauthorizedConsumersCount:
+		return "Несанкционированные потребители"
+	case metricAvgDurationMinutes:
+		return "Средняя длительность, мин"
+	case metricSuccessfulLimitations:
+		return "Введено ограничений"
+	case metricSuccessfulResumptions:
+		return "Выполнено возобновлений"
+	default:
+		return name
+	}
+}
+
+func inspectionTypeTitle(key string) string {
+	switch key {
+	case "limitation":
+		return "Ограничение"
+	case "resumption":
+		return "Возобновление"
+	case "verification":
+		return "Контроль ограничения"
+	case "unauthorized_connection":
+		return "Несанкционированное подключение"
+	default:
+		return key
+	}
+}
+
+func (s *Service) CreateComparisonReport(ctx goctx.Context, log golog.Logger, current, previous Period) (_ Report, err error) {
+	spanCtx, span := tracer.Start(ctx, "create comparison report")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	ctx = goctx.Wrap(spanCtx)
+
+	comparison, err := s.GetComparison(ctx, current, previous)
+	if err != nil {
+		return Report{}, err
+	}
+
+	_, renderSpan := tracer.Start(ctx, "excelize render comparison report",
+		trace.WithAttributes(attribute.Int("brigades", len(comparison.ByBrigade))))
+	buf, err := renderComparisonReport(log, comparison)
+	tracing.End(renderSpan, err)
+	if err != nil {
+		return Report{}, err
+	}
+
+	fileName := fmt.Sprintf("Сравнение %s с %s.xlsx", periodTitle(comparison.Current), periodTitle(comparison.Previous))
+
+	uploadedFile, err := s.fileService.Upload(ctx, fileName, buf)
+	if err != nil {
+		return Report{}, fmt.Errorf("upload file: %w", err)
+	}
+
+	report := Report{
+		Type:          ReportTypeComparison,
+		Files:         []file.File{uploadedFile},
+		PeriodStart:   comparison.Current.Start,
+		PeriodEnd:     comparison.Current.End,
+		MaskingPolicy: s.masker.Policy(ReportTypeComparison.Name(), auth.FromContext(ctx).Role),
+		Tenants:       auth.FromContext(ctx).TenantScope().Tenants,
+	}
+
+	report, err = s.repository.AddReport(ctx, report)
+	if err != nil {
+		return Report{}, fmt.Errorf("add report: %w", err)
+	}
+
+	return report, nil
+}
+
+func periodTitle(p Period) string {
+	return p.Start.Format(gotime.DateOnlyNet) + "-" + p.End.Format(gotime.DateOnlyNet)
+}
+
+// renderComparisonReport fills increases green and decreases red in the delta columns.
+func renderComparisonReport(log golog.Logger, c Comparison) (*bytes.Buffer, error) {
+	f := excelize.NewFile()
+	defer func() {
+		if fErr := f.Close(); fErr != nil {
+			log.Errorf("close comparison report file: %v", fErr)
+		}
+	}()
+
+	increaseStyle, err := f.NewStyle(&excelize.Style{Fill: excelize.Fill{Type: "pattern", Color: []string{"#C6EFCE"}, Pattern: 1}})
+	if err != nil {
+		return nil, fmt.Errorf("new style: %w", err)
+	}
+
+	decreaseStyle, err := f.NewStyle(&excelize.Style{Fill: excelize.Fill{Type: "pattern", Color: []string{"#FFC7CE"}, Pattern: 1}})
+	if err != nil {
+		return nil, fmt.Errorf("new style: %w", err)
+	}
+
+	typeGroups := make([]ComparisonGroup, 0, len(c.ByInspectionType))
+	for _, g := range c.ByInspectionType {
+		typeGroups = append(typeGroups, ComparisonGroup{Key: inspectionTypeTitle(g.Key), Metrics: g.Metrics})
+	}
+
+	sheets := []struct {
+		name   string
+		title  string
+		groups []ComparisonGroup
+	}{
+		{name: "Итого", groups: []ComparisonGroup{{Metrics: c.Total}}},
+		{name: "По типам работ", title: "Тип работ", groups: typeGroups},
+		{name: "По бригадам", title: "Бригада", groups: c.ByBrigade},
+	}
+
+	for i, sheet := range sheets {
+		if i == 0 {
+			if err = f.SetSheetName(f.GetSheetName(0), sheet.name); err != nil {
+				return nil, fmt.Errorf("set sheet name: %w", err)
+			}
+		} else if _, err = f.NewSheet(sheet.name); err != nil {
+			return nil, fmt.Errorf("new sheet: %w", err)
+		}
+
+		header := []any{"Показатель", periodTitle(c.Current), periodTitle(c.Previous), "Изменение", "Изменение, %"}
+		if sheet.title != "" {
+			header = append([]any{sheet.title}, header...)
+		}
+
+		rows := [][]any{header}
+		deltas := []float64{0}
+		for _, g := range sheet.groups {
+			for _, m := range g.Metrics {
+				var percent any
+				if m.DeltaPercent != nil {
+					percent = *m.DeltaPercent
+				}
+
+				row := []any{comparisonMetricTitle(m.Name), m.Current, m.Previous, m.Delta, percent}
+				if sheet.title != "" {
+					row = append([]any{g.Key}, row...)
+				}
+
+				rows = append(rows, row)
+				deltas = append(deltas, m.Delta)
+			}
+		}
+
+		if err = setSheetRows(f, sheet.name, rows); err != nil {
+			return nil, err
+		}
+
+		for row, delta := range deltas {
+			if delta == 0 {
+				continue
+			}
+
+			style := increaseStyle
+			if delta < 0 {
+				style = decreaseStyle
+			}
+
+			from, cellErr := excelize.CoordinatesToCellName(len(header)-1, row+1)
+			if cellErr != nil {
+				return nil, fmt.Errorf("coordinates to cell name: %w", cellErr)
+			}
+
+			to, cellErr := excelize.CoordinatesToCellName(len(header), row+1)
+			if cellErr != nil {
+				return nil, fmt.Errorf("coordinates to cell name: %w", cellErr)
+			}
+
+			if err = f.SetCellStyle(sheet.name, from, to, style); err != nil {
+				return nil, fmt.Errorf("set cell style: %w", err)
+			}
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("write file to buffer: %w", err)
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,75 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPreviousPeriod(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		current  Period
+		mode     ComparisonMode
+		expected Period
+	}{
+		{Period{date(2025, 3, 1), date(2025, 4, 1)}, ComparisonModePrevious, Period{date(2025, 2, 1), date(2025, 3, 1)}},
+		{Period{date(2025, 1, 1), date(2025, 4, 1)}, ComparisonModePrevious, Period{date(2024, 10, 1), date(2025, 1, 1)}},
+		{Period{date(2025, 3, 10), date(2025, 3, 17)}, ComparisonModePrevious, Period{date(2025, 3, 3), date(2025, 3, 10)}},
+		{Period{date(2025, 3, 1), date(2025, 4, 1)}, ComparisonModeYear, Period{date(2024, 3, 1), date(2024, 4, 1)}},
+	}
+
+	for _, tt := range tests {
+		previous, err := PreviousPeriod(tt.current, tt.mode)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !previous.Start.Equal(tt.expected.Start) || !previous.End.Equal(tt.expected.End) {
+			t.Fatalf("%s of %+v: expected %+v, got %+v", tt.mode, tt.current, tt.expected, previous)
+		}
+	}
+
+	if _, err := PreviousPeriod(Period{}, ComparisonModeCustom); err == nil {
+		t.Fatal("expected an error for the custom mode")
+	}
+}
+
+func TestComputeComparison(t *testing.T) {
+	comparison := ComputeComparison(
+		ComparisonData{
+			Tasks: []TasksDaily{
+				{TasksCount: 10, LimitationCount: 6, ResumptionCount: 4, ViolationsDetectedCount: 2, AvgDurationMinutes: 30},
+				{TasksCount: 30, LimitationCount: 30, AvgDurationMinutes: 50},
+			},
+			Brigades: []BrigadeDay{{BrigadeID: 2, TasksCount: 40, AvgDurationMinutes: 45}},
+		},
+		ComparisonData{
+			Tasks:    []TasksDaily{{TasksCount: 20, LimitationCount: 20, ViolationsDetectedCount: 4, AvgDurationMinutes: 40}},
+			Brigades: []BrigadeDay{{BrigadeID: 1, TasksCount: 20, AvgDurationMinutes: 40}},
+		},
+	)
+
+	tasks := comparison.Total[0]
+	if tasks.Current != 40 || tasks.Previous != 20 || tasks.Delta != 20 || tasks.DeltaPercent == nil || *tasks.DeltaPercent != 100 {
+		t.Fatalf("unexpected tasks metric: %+v", tasks)
+	}
+
+	if violations := comparison.Total[1]; violations.Delta != -2 || *violations.DeltaPercent != -50 {
+		t.Fatalf("unexpected violations metric: %+v", violations)
+	}
+
+	if duration := comparison.Total[3]; duration.Current != 45 || duration.Previous != 40 {
+		t.Fatalf("expected durations weighted by tasks, got %+v", duration)
+	}
+
+	if resumption := comparison.ByInspectionType[1]; resumption.Key != "resumption" || resumption.Metrics[0].DeltaPercent != nil {
+		t.Fatalf("expected no percentage without previous tasks, got %+v", resumption)
+	}
+
+	if len(comparison.ByBrigade) != 2 || comparison.ByBrigade[0].Key != "1" || comparison.ByBrigade[0].Metrics[0].Current != 0 {
+		t.Fatalf("unexpected brigades: %+v", comparison.ByBrigade)
+	}
+}
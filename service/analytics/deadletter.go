@@ -0,0 +1,50 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/golog"
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+// deadLetter keeps a message that can't be handled, retrying until it's written. The message must be acked only if
+// true is returned, otherwise ctx is done and the message is consumed again after restart.
+func (p *pipeline) deadLetter(ctx context.Context, log golog.Logger, message kafka.Message, taskID int, cause error) bool {
+	deadLetter := DeadLetter{
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+		TaskID:    taskID,
+		Payload:   message.Value,
+		Reason:    cause.Error(),
+	}
+
+	for {
+		err := p.repository.AddDeadLetter(ctx, deadLetter)
+		if err == nil {
+			return true
+		}
+
+		log.Errorf("failed to add dead letter (partition = %d, offset = %d): %v", message.Partition, message.Offset, err)
+
+		if wait(ctx, eventRetryBackoff) != nil {
+			return false
+		}
+	}
+}
+
+func (s *Service) GetDeadLetters(ctx goctx.Context, page pagination.Pagination) ([]DeadLetter, error) {
+	if err := page.Validate(); err != nil {
+		return nil, fmt.Errorf("validate pagination: %w", err)
+	}
+
+	deadLetters, err := s.repository.GetDeadLetters(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("get dead letters: %w", err)
+	}
+
+	return deadLetters, nil
+}
@@ -0,0 +1,266 @@
+package analytics
+
+import (
+	"analytics-service/cluster/file"
+	"analytics-service/config"
+	"analytics-service/service/auth"
+	"analytics-service/service/masking"
+	"analytics-service/tracing"
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/golog"
+	"github.com/sunshineOfficial/golib/gotime"
+	"github.com/xuri/excelize/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	devicePassportSheet = "Паспорт прибора"
+	deviceReadingsSheet = "Показания"
+
+	// minJumpHistory is the number of earlier intervals needed to compare a daily delta with their median.
+	minJumpHistory = 3
+)
+
+func (r ReadingFlag) Title() string {
+	switch r {
+	case ReadingFlagRollback:
+		return "Откат показаний"
+	case ReadingFlagJump:
+		return "Скачок потребления"
+	default:
+		return "Неизвестно"
+	}
+}
+
+// GetDeviceHistory returns the reading timeline, addresses and account numbers are masked according to the caller role.
+func (s *Service) GetDeviceHistory(ctx goctx.Context, deviceID int) (DeviceHistory, error) {
+	history, err := s.deviceHistory(ctx, deviceID)
+	if err != nil {
+		return DeviceHistory{}, err
+	}
+
+	return MaskDeviceHistory(s.masker, s.masker.Policy(masking.ScopeAPI, auth.FromContext(ctx).Role), history), nil
+}
+
+func (s *Service) deviceHistory(ctx goctx.Context, deviceID int) (DeviceHistory, error) {
+	readings, err := s.repository.GetDeviceReadings(ctx, auth.FromContext(ctx).TenantScope(), deviceID)
+	if err != nil {
+		return DeviceHistory{}, fmt.Errorf("get device readings: %w", err)
+	}
+
+	return BuildDeviceHistory(deviceID, readings, s.devices), nil
+}
+
+// BuildDeviceHistory compares every reading with the previous one of the device. A reading lower than the previous one
+// is a rollback. A reading is a jump if its daily delta exceeds MaxDailyDelta or JumpFactor times the median daily delta
+// of the earlier intervals without rollbacks. Intervals shorter than a day count as one day.
+func BuildDeviceHistory(deviceID int, readings []DeviceReading, settings config.Devices) DeviceHistory {
+	readings = slices.Clone(readings)
+	slices.SortStableFunc(readings, func(a, b DeviceReading) int {
+		return a.ReadAt.Compare(b.ReadAt)
+	})
+
+	history := DeviceHistory{
+		DeviceID:   deviceID,
+		TotalDelta: decimal.Zero,
+		Readings:   make([]DeviceReading, 0, len(readings)),
+	}
+
+	var dailyDeltas []float64
+	for i, r := range readings {
+		r.Flags = make([]ReadingFlag, 0)
+
+		if i > 0 {
+			prev := readings[i-1]
+
+			delta := r.Value.Sub(prev.Value)
+			days := round2(r.ReadAt.Sub(prev.ReadAt).Hours() / 24)
+			daily := round2(delta.InexactFloat64() / max(days, 1))
+
+			r.Delta = &delta
+			r.DaysSincePrevious = &days
+			r.DailyDelta = &daily
+
+			if delta.IsNegative() {
+				r.Flags = append(r.Flags, ReadingFlagRollback)
+				history.RollbacksCount++
+			} else {
+				if isJump(daily, dailyDeltas, settings) {
+					r.Flags = append(r.Flags, ReadingFlagJump)
+					history.JumpsCount++
+				}
+
+				history.TotalDelta = history.TotalDelta.Add(delta)
+				dailyDeltas = append(dailyDeltas, daily)
+			}
+		}
+
+		history.Readings = append(history.Readings, r)
+	}
+
+	if len(readings) > 0 {
+		first, last := readings[0].ReadAt, readings[len(readings)-1].ReadAt
+		history.FirstReadAt = &first
+		history.LastReadAt = &last
+	}
+
+	return history
+}
+
+func isJump(daily float64, previous []float64, settings config.Devices) bool {
+	if settings.MaxDailyDelta > 0 && daily > settings.MaxDailyDelta {
+		return true
+	}
+
+	if settings.JumpFactor <= 0 || len(previous) < minJumpHistory {
+		return false
+	}
+
+	sorted := slices.Clone(previous)
+	slices.Sort(sorted)
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + median) / 2
+	}
+
+	return median > 0 && daily > settings.JumpFactor*median
+}
+
+func (s *Service) CreateDevicePassportReport(ctx goctx.Context, log golog.Logger, deviceID int) (_ Report, err error) {
+	spanCtx, span := tracer.Start(ctx, "create device passport report", trace.WithAttributes(attribute.Int("device.id", deviceID)))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	ctx = goctx.Wrap(spanCtx)
+
+	history, err := s.deviceHistory(ctx, deviceID)
+	if err != nil {
+		return Report{}, err
+	}
+	if len(history.Readings) == 0 {
+		return Report{}, fmt.Errorf("no readings found for device %d", deviceID)
+	}
+
+	maskingPolicy := s.masker.Policy(ReportTypeDevicePassport.Name(), auth.FromContext(ctx).Role)
+	history = MaskDeviceHistory(s.masker, maskingPolicy, history)
+
+	_, renderSpan := tracer.Start(ctx, "excelize render device passport report",
+		trace.WithAttributes(attribute.Int("readings", len(history.Readings))))
+	buf, err := renderDevicePassportReport(log, history)
+	tracing.End(renderSpan, err)
+	if err != nil {
+		return Report{}, err
+	}
+
+	fileName := fmt.Sprintf("Паспорт прибора %d на %s.xlsx", deviceID, history.LastReadAt.In(gotime.Moscow).Format(gotime.DateOnlyNet))
+
+	uploadedFile, err := s.fileService.Upload(ctx, fileName, buf)
+	if err != nil {
+		return Report{}, fmt.Errorf("upload file: %w", err)
+	}
+
+	report := Report{
+		Type:          ReportTypeDevicePassport,
+		Files:         []file.File{uploadedFile},
+		PeriodStart:   *history.FirstReadAt,
+		PeriodEnd:     *history.LastReadAt,
+		MaskingPolicy: maskingPolicy,
+		Tenants:       auth.FromContext(ctx).TenantScope().Tenants,
+	}
+
+	report, err = s.repository.AddReport(ctx, report)
+	if err != nil {
+		return Report{}, fmt.Errorf("add report: %w", err)
+	}
+
+	return report, nil
+}
+
+func renderDevicePassportReport(log golog.Logger, h DeviceHistory) (*bytes.Buffer, error) {
+	f := excelize.NewFile()
+	defer func() {
+		if fErr := f.Close(); fErr != nil {
+			log.Errorf("close device passport report file: %v", fErr)
+		}
+	}()
+
+	if err := f.SetSheetName(f.GetSheetName(0), devicePassportSheet); err != nil {
+		return nil, fmt.Errorf("set sheet name: %w", err)
+	}
+
+	if _, err := f.NewSheet(deviceReadingsSheet); err != nil {
+		return nil, fmt.Errorf("new sheet: %w", err)
+	}
+
+	first, last := h.Readings[0], h.Readings[len(h.Readings)-1]
+	passportRows := [][]any{
+		{"Прибор учета", h.DeviceID},
+		{"Тип", last.DeviceType},
+		{"Заводской номер", last.DeviceNumber},
+		{"Пломбы", strings.Join(last.SealNumbers, ", ")},
+		{"Объект", last.ObjectID},
+		{"Адрес", last.ObjectAddress},
+		{"Лицевой счет", last.SubscriberAccountNumber},
+		{"Первое показание", first.ReadAt.In(gotime.Moscow).Format(gotime.DateOnlyNet), first.Value.InexactFloat64()},
+		{"Последнее показание", last.ReadAt.In(gotime.Moscow).Format(gotime.DateOnlyNet), last.Value.InexactFloat64()},
+		{"Показаний", len(h.Readings)},
+		{"Прирост показаний", h.TotalDelta.InexactFloat64()},
+		{"Откатов", h.RollbacksCount},
+		{"Скачков", h.JumpsCount},
+	}
+
+	rows := [][]any{{
+		"Дата", "Задача", "Показание", "Потребление, кВт*ч", "Разница", "Дней с прошлого", "В сутки", "Пломбы", "Объект",
+		"Адрес", "Лицевой счет", "Отметки",
+	}}
+	for _, r := range h.Readings {
+		var delta, days, daily any
+		if r.Delta != nil {
+			delta, days, daily = r.Delta.InexactFloat64(), *r.DaysSincePrevious, *r.DailyDelta
+		}
+
+		flags := make([]string, 0, len(r.Flags))
+		for _, flag := range r.Flags {
+			flags = append(flags, flag.Title())
+		}
+
+		rows = append(rows, []any{
+			r.ReadAt.In(gotime.Moscow).Format(gotime.DateTimeNet),
+			r.TaskID,
+			r.Value.InexactFloat64(),
+			r.ConsumptionKWh.InexactFloat64(),
+			delta,
+			days,
+			daily,
+			strings.Join(r.SealNumbers, ", "),
+			r.ObjectID,
+			r.ObjectAddress,
+			r.SubscriberAccountNumber,
+			strings.Join(flags, "; "),
+		})
+	}
+
+	if err := setSheetRows(f, devicePassportSheet, passportRows); err != nil {
+		return nil, err
+	}
+
+	if err := setSheetRows(f, deviceReadingsSheet, rows); err != nil {
+		return nil, err
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("write file to buffer: %w", err)
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,51 @@
+package analytics
+
+import (
+	"analytics-service/config"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBuildDeviceHistory(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	reading := func(taskID, days int, value int64) DeviceReading {
+		return DeviceReading{DeviceID: 7, TaskID: taskID, ReadAt: start.AddDate(0, 0, days), Value: decimal.NewFromInt(value)}
+	}
+
+	history := BuildDeviceHistory(7, []DeviceReading{
+		reading(5, 50, 1900),
+		reading(1, 0, 1000),
+		reading(2, 10, 1100),
+		reading(3, 20, 1200),
+		reading(4, 30, 1300),
+		reading(6, 60, 1850),
+		reading(7, 61, 2500),
+	}, config.Devices{MaxDailyDelta: 500, JumpFactor: 2})
+
+	if len(history.Readings) != 7 || history.Readings[0].TaskID != 1 || history.Readings[0].Delta != nil {
+		t.Fatalf("unexpected readings order: %+v", history.Readings)
+	}
+
+	if r := history.Readings[4]; !slices.Equal(r.Flags, []ReadingFlag{ReadingFlagJump}) || *r.DaysSincePrevious != 20 || *r.DailyDelta != 30 {
+		t.Fatalf("expected a jump by median, got %+v", r)
+	}
+
+	if r := history.Readings[5]; !slices.Equal(r.Flags, []ReadingFlag{ReadingFlagRollback}) || !r.Delta.Equal(decimal.NewFromInt(-50)) {
+		t.Fatalf("expected a rollback, got %+v", r)
+	}
+
+	if r := history.Readings[6]; !slices.Equal(r.Flags, []ReadingFlag{ReadingFlagJump}) || *r.DailyDelta != 650 {
+		t.Fatalf("expected a jump over the daily maximum, got %+v", r)
+	}
+
+	if history.RollbacksCount != 1 || history.JumpsCount != 2 || !history.TotalDelta.Equal(decimal.NewFromInt(1550)) {
+		t.Fatalf("unexpected totals: %+v", history)
+	}
+
+	if !history.FirstReadAt.Equal(start) || !history.LastReadAt.Equal(start.AddDate(0, 0, 61)) {
+		t.Fatalf("unexpected bounds: %v - %v", history.FirstReadAt, history.LastReadAt)
+	}
+}
@@ -0,0 +1,232 @@
+package analytics
+
+import (
+	"analytics-service/cluster/file"
+	"analytics-service/service/auth"
+	"analytics-service/tracing"
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/golog"
+	"github.com/sunshineOfficial/golib/gotime"
+	"github.com/xuri/excelize/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	inspectorsSheet = "Инспекторы"
+	workloadSheet   = "Нагрузка по дням"
+)
+
+func (s *Service) GetInspectorPerformance(ctx goctx.Context, periodStart, periodEnd time.Time) ([]InspectorPerformance, error) {
+	periodStart, periodEnd, err := reportPeriod(periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.repository.GetInspectorDaysByPeriod(ctx, auth.FromContext(ctx).TenantScope(), periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("get inspector days: %w", err)
+	}
+
+	return AggregateInspectorDays(days), nil
+}
+
+// AggregateInspectorDays sums up the output of every inspector over all days and brigades.
+// Inspectors are sorted by the number of tasks, the busiest first.
+func AggregateInspectorDays(days []InspectorDay) []InspectorPerformance {
+	byID := make(map[int]*InspectorPerformance)
+	totalDuration := make(map[int]int)
+	workload := make(map[int]map[time.Time]int)
+
+	for _, d := range days {
+		p, ok := byID[d.InspectorID]
+		if !ok {
+			p = &InspectorPerformance{
+				InspectorID: d.InspectorID,
+				FullName:    d.InspectorFullName,
+				BrigadeIDs:  []int{},
+			}
+			byID[d.InspectorID] = p
+			workload[d.InspectorID] = make(map[time.Time]int)
+		}
+
+		p.TasksCount += d.TasksCount
+		p.ViolationsDetectedCount += d.ViolationsDetectedCount
+		totalDuration[d.InspectorID] += d.TotalDurationMinutes
+		workload[d.InspectorID][d.Day] += d.TasksCount
+
+		if !slices.Contains(p.BrigadeIDs, d.BrigadeID) {
+			p.BrigadeIDs = append(p.BrigadeIDs, d.BrigadeID)
+		}
+	}
+
+	result := make([]InspectorPerformance, 0, len(byID))
+	for id, p := range byID {
+		if p.TasksCount > 0 {
+			p.AvgDurationMinutes = math.Round(float64(totalDuration[id])/float64(p.TasksCount)*100) / 100
+		}
+
+		p.Workload = make([]InspectorWorkload, 0, len(workload[id]))
+		for day, tasksCount := range workload[id] {
+			p.Workload = append(p.Workload, InspectorWorkload{Day: day, TasksCount: tasksCount})
+		}
+
+		slices.SortFunc(p.Workload, func(a, b InspectorWorkload) int {
+			return a.Day.Compare(b.Day)
+		})
+		slices.Sort(p.BrigadeIDs)
+
+		result = append(result, *p)
+	}
+
+	slices.SortFunc(result, func(a, b InspectorPerformance) int {
+		return cmp.Or(cmp.Compare(b.TasksCount, a.TasksCount), cmp.Compare(a.InspectorID, b.InspectorID))
+	})
+
+	return result
+}
+
+func (s *Service) CreateInspectorReport(ctx goctx.Context, log golog.Logger, periodStart, periodEnd time.Time) (_ Report, err error) {
+	spanCtx, span := tracer.Start(ctx, "create inspector report")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	ctx = goctx.Wrap(spanCtx)
+
+	periodStart, periodEnd, err = reportPeriod(periodStart, periodEnd)
+	if err != nil {
+		return Report{}, err
+	}
+
+	performance, err := s.GetInspectorPerformance(ctx, periodStart, periodEnd)
+	if err != nil {
+		return Report{}, err
+	}
+	if len(performance) == 0 {
+		return Report{}, fmt.Errorf("no inspector tasks found from %s to %s", periodStart, periodEnd)
+	}
+
+	_, renderSpan := tracer.Start(ctx, "excelize render inspector report", trace.WithAttributes(attribute.Int("rows", len(performance))))
+	buf, err := renderInspectorReport(log, performance)
+	tracing.End(renderSpan, err)
+	if err != nil {
+		return Report{}, err
+	}
+
+	fileName := fmt.Sprintf("Отчет по инспекторам за %s-%s.xlsx",
+		periodStart.Format(gotime.DateOnlyNet), periodEnd.Format(gotime.DateOnlyNet))
+
+	uploadedFile, err := s.fileService.Upload(ctx, fileName, buf)
+	if err != nil {
+		return Report{}, fmt.Errorf("upload file: %w", err)
+	}
+
+	report := Report{
+		Type:          ReportTypeInspectors,
+		Files:         []file.File{uploadedFile},
+		PeriodStart:   periodStart,
+		PeriodEnd:     periodEnd,
+		MaskingPolicy: s.masker.Policy(ReportTypeInspectors.Name(), auth.FromContext(ctx).Role),
+		Tenants:       auth.FromContext(ctx).TenantScope().Tenants,
+	}
+
+	report, err = s.repository.AddReport(ctx, report)
+	if err != nil {
+		return Report{}, fmt.Errorf("add report: %w", err)
+	}
+
+	return report, nil
+}
+
+func renderInspectorReport(log golog.Logger, performance []InspectorPerformance) (*bytes.Buffer, error) {
+	f := excelize.NewFile()
+	defer func() {
+		if fErr := f.Close(); fErr != nil {
+			log.Errorf("close inspector report file: %v", fErr)
+		}
+	}()
+
+	if err := f.SetSheetName(f.GetSheetName(0), inspectorsSheet); err != nil {
+		return nil, fmt.Errorf("set sheet name: %w", err)
+	}
+
+	if _, err := f.NewSheet(workloadSheet); err != nil {
+		return nil, fmt.Errorf("new sheet: %w", err)
+	}
+
+	rows := [][]any{{"№", "Инспектор", "Бригады", "Задач", "Выявлено нарушений", "Средняя длительность, мин", "Рабочих дней"}}
+	workloadRows := [][]any{{"Дата", "Инспектор", "Задач"}}
+
+	for i, p := range performance {
+		brigades := make([]string, 0, len(p.BrigadeIDs))
+		for _, id := range p.BrigadeIDs {
+			brigades = append(brigades, strconv.Itoa(id))
+		}
+
+		rows = append(rows, []any{
+			i + 1,
+			p.FullName,
+			strings.Join(brigades, ", "),
+			p.TasksCount,
+			p.ViolationsDetectedCount,
+			p.AvgDurationMinutes,
+			len(p.Workload),
+		})
+
+		for _, w := range p.Workload {
+			workloadRows = append(workloadRows, []any{w.Day.Format(gotime.DateOnlyNet), p.FullName, w.TasksCount})
+		}
+	}
+
+	if err := setSheetRows(f, inspectorsSheet, rows); err != nil {
+		return nil, err
+	}
+
+	if err := setSheetRows(f, workloadSheet, workloadRows); err != nil {
+		return nil, err
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("write file to buffer: %w", err)
+	}
+
+	return buf, nil
+}
+
+func setSheetRows(f *excelize.File, sheet string, rows [][]any) error {
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return fmt.Errorf("coordinates to cell name: %w", err)
+		}
+
+		if err = f.SetSheetRow(sheet, cell, &row); err != nil {
+			return fmt.Errorf("set sheet row: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// reportPeriod moves the period bounds to Moscow midnights, the period must be at least one day long.
+func reportPeriod(periodStart, periodEnd time.Time) (time.Time, time.Time, error) {
+	periodStart = time.Date(periodStart.Year(), periodStart.Month(), periodStart.Day(), 0, 0, 0, 0, gotime.Moscow)
+	periodEnd = time.Date(periodEnd.Year(), periodEnd.Month(), periodEnd.Day(), 0, 0, 0, 0, gotime.Moscow)
+
+	if days := gotime.Days(periodEnd, periodStart); days < 1 {
+		return time.Time{}, time.Time{}, fmt.Errorf("period days must be positive, got: %f", days)
+	}
+
+	return periodStart, periodEnd, nil
+}
@@ -0,0 +1,39 @@
+package analytics
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAggregateInspectorDays(t *testing.T) {
+	monday := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+
+	performance := AggregateInspectorDays([]InspectorDay{
+		{Day: monday, InspectorID: 1, InspectorFullName: "Иванов И И", BrigadeID: 10, TasksCount: 2, ViolationsDetectedCount: 1, TotalDurationMinutes: 60},
+		{Day: tuesday, InspectorID: 1, InspectorFullName: "Иванов И И", BrigadeID: 11, TasksCount: 1, TotalDurationMinutes: 30},
+		{Day: monday, InspectorID: 2, InspectorFullName: "Петров П П", BrigadeID: 10, TasksCount: 2, TotalDurationMinutes: 50},
+	})
+
+	if len(performance) != 2 {
+		t.Fatalf("expected 2 inspectors, got %d", len(performance))
+	}
+
+	first := performance[0]
+	if first.InspectorID != 1 || first.TasksCount != 3 || first.ViolationsDetectedCount != 1 {
+		t.Fatalf("unexpected first inspector: %+v", first)
+	}
+
+	if first.AvgDurationMinutes != 30 {
+		t.Fatalf("expected average duration 30, got %v", first.AvgDurationMinutes)
+	}
+
+	if !slices.Equal(first.BrigadeIDs, []int{10, 11}) {
+		t.Fatalf("expected brigades [10 11], got %v", first.BrigadeIDs)
+	}
+
+	if len(first.Workload) != 2 || !first.Workload[0].Day.Equal(monday) || first.Workload[1].TasksCount != 1 {
+		t.Fatalf("unexpected workload: %+v", first.Workload)
+	}
+}
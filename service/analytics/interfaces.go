@@ -5,6 +5,7 @@ import (
 	"analytics-service/cluster/file"
 	"analytics-service/cluster/inspection"
 	"analytics-service/cluster/subscriber"
+	"analytics-service/service/auth"
 	"context"
 	"io"
 	"time"
@@ -14,10 +15,35 @@ import (
 )
 
 type Repository interface {
-	AddFinishedTask(ctx context.Context, t FinishedTask) error
-	GetFinishedTasksByPeriod(ctx context.Context, periodStart, periodEnd time.Time) ([]FinishedTask, error)
+	AddFinishedTasks(ctx context.Context, tasks []FinishedTask) error
+	AddFinishedTasksTo(ctx context.Context, table string, tasks []FinishedTask) error
+	CreateReplayTable(ctx context.Context, table string) error
+	DropReplayTable(ctx context.Context, table string) error
+	AddRawEvents(ctx context.Context, events []RawEvent) error
+	ForEachRawEvent(ctx context.Context, from, to time.Time, fn func(e RawEvent) error) error
+	GetExistingTaskIDs(ctx context.Context, ids []int) ([]int, error)
+	IsSubscriberErased(ctx context.Context, subscriberID int, tenant string) (bool, error)
+	GetFinishedTasksByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]FinishedTask, error)
+	GetInspectorDaysByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]InspectorDay, error)
+	GetTasksDailyByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]TasksDaily, error)
+	GetBrigadeDaysByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]BrigadeDay, error)
+	GetVisitDelaysByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]VisitDelay, error)
+	GetLimitationTurnaroundsByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]LimitationTurnaround, error)
+	GetOpenLimitations(ctx context.Context, scope auth.TenantScope, limitedBefore time.Time) ([]LimitationTurnaround, error)
+	GetObjectRefusalsByPeriod(ctx context.Context, scope auth.TenantScope, periodStart, periodEnd time.Time) ([]ObjectRefusals, error)
+	GetDeviceReadings(ctx context.Context, scope auth.TenantScope, deviceID int) ([]DeviceReading, error)
 	AddReport(ctx context.Context, r Report) (Report, error)
-	GetAllReports(ctx context.Context, page pagination.Pagination) ([]Report, error)
+	GetAllReports(ctx context.Context, scope auth.TenantScope, access ReportAccess, page pagination.Pagination) ([]Report, error)
+	HasReportFile(ctx context.Context, scope auth.TenantScope, access ReportAccess, reportID, fileID int) (bool, error)
+	AddQuarantinedTask(ctx context.Context, t QuarantinedTask) error
+	AddDeadLetter(ctx context.Context, d DeadLetter) error
+	GetDeadLetters(ctx context.Context, page pagination.Pagination) ([]DeadLetter, error)
+	GetQuarantinedTasks(ctx context.Context, id int, page pagination.Pagination) ([]QuarantinedTask, error)
+	UpdateQuarantinedTask(ctx context.Context, t QuarantinedTask) error
+	AddReplayJob(ctx context.Context, j ReplayJob) (ReplayJob, error)
+	UpdateReplayJob(ctx context.Context, j ReplayJob) error
+	GetReplayJobs(ctx context.Context, id int, page pagination.Pagination) ([]ReplayJob, error)
+	GetExpiredReplayJobs(ctx context.Context, finishedBefore time.Time) ([]ReplayJob, error)
 }
 
 type InspectionService interface {
@@ -26,13 +52,23 @@ type InspectionService interface {
 
 type BrigadeService interface {
 	GetBrigadeByID(ctx goctx.Context, id int) (brigade.Brigade, error)
+	Invalidate(ctx context.Context, id int) error
 }
 
 type SubscriberService interface {
 	GetLastContractByObjectID(ctx goctx.Context, objectID int) (subscriber.Contract, error)
+	Invalidate(ctx context.Context, objectID int) error
 }
 
 type FileService interface {
 	Upload(ctx goctx.Context, fileName string, file io.Reader) (file.File, error)
 	GetFilesByIDs(ctx goctx.Context, ids []int) ([]file.File, error)
 }
+
+// Clients are peer services used to enrich task events and to store report files.
+type Clients struct {
+	Inspection InspectionService
+	Brigade    BrigadeService
+	Subscriber SubscriberService
+	File       FileService
+}
@@ -5,41 +5,58 @@ import (
 	"analytics-service/cluster/inspection"
 	"analytics-service/cluster/subscriber"
 	"analytics-service/cluster/task"
+	"analytics-service/service/address"
+	"analytics-service/service/masking"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
 )
 
+// value keeps mapping of incomplete payloads from panicking, payloads are validated before mapping.
+func value[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+
+	return *p
+}
+
 func MapToFinishedTask(t task.Task, ins inspection.Inspection, brig brigade.Brigade, contract subscriber.Contract) FinishedTask {
 	return FinishedTask{
 		TaskID:      t.ID,
 		Comment:     t.Comment,
 		PlanVisitAt: t.PlanVisitAt,
-		StartedAt:   *t.StartedAt,
-		FinishedAt:  *t.FinishedAt,
+		StartedAt:   value(t.StartedAt),
+		FinishedAt:  value(t.FinishedAt),
 		Inspection:  MapInspectionToDomain(ins),
 		Brigade:     MapBrigadeToDomain(brig),
 		Object:      MapObjectToDomain(contract.Object),
 		Subscriber:  MapSubscriberToDomain(contract.Subscriber),
+		Contract:    MapContractToDomain(contract),
 	}
 }
 
 func MapInspectionToDomain(ins inspection.Inspection) Inspection {
 	return Inspection{
 		ID:                      ins.ID,
-		Type:                    *ins.Type,
-		Resolution:              *ins.Resolution,
+		Type:                    value(ins.Type),
+		Resolution:              value(ins.Resolution),
 		LimitReason:             ins.LimitReason,
-		Method:                  *ins.Method,
-		MethodBy:                *ins.MethodBy,
-		ReasonType:              *ins.ReasonType,
+		Method:                  value(ins.Method),
+		MethodBy:                value(ins.MethodBy),
+		ReasonType:              value(ins.ReasonType),
 		ReasonDescription:       ins.ReasonDescription,
-		IsRestrictionChecked:    *ins.IsRestrictionChecked,
-		IsViolationDetected:     *ins.IsViolationDetected,
-		IsExpenseAvailable:      *ins.IsExpenseAvailable,
+		IsRestrictionChecked:    value(ins.IsRestrictionChecked),
+		IsViolationDetected:     value(ins.IsViolationDetected),
+		IsExpenseAvailable:      value(ins.IsExpenseAvailable),
 		ViolationDescription:    ins.ViolationDescription,
-		IsUnauthorizedConsumers: *ins.IsUnauthorizedConsumers,
+		IsUnauthorizedConsumers: value(ins.IsUnauthorizedConsumers),
 		UnauthorizedDescription: ins.UnauthorizedDescription,
 		UnauthorizedExplanation: ins.UnauthorizedExplanation,
-		InspectAt:               *ins.InspectAt,
-		EnergyActionAt:          *ins.EnergyActionAt,
+		InspectAt:               value(ins.InspectAt),
+		EnergyActionAt:          value(ins.EnergyActionAt),
 		Devices:                 MapInspectedDeviceSliceToDomain(ins.InspectedDevices),
 	}
 }
@@ -96,6 +113,51 @@ func MapObjectToDomain(obj subscriber.Object) Object {
 		ID:            obj.ID,
 		Address:       obj.Address,
 		HaveAutomaton: obj.HaveAutomaton,
+		Devices:       MapDeviceSliceToDomain(obj.Devices),
+	}
+}
+
+func MapDeviceToDomain(device subscriber.Device) Device {
+	return Device{
+		ID:               device.ID,
+		Type:             device.Type,
+		Number:           device.Number,
+		PlaceType:        device.PlaceType,
+		PlaceDescription: device.PlaceDescription,
+		Seals:            MapSealSliceToDomain(device.Seals),
+	}
+}
+
+func MapDeviceSliceToDomain(devices []subscriber.Device) []Device {
+	result := make([]Device, 0, len(devices))
+	for _, device := range devices {
+		result = append(result, MapDeviceToDomain(device))
+	}
+
+	return result
+}
+
+func MapSealToDomain(seal subscriber.Seal) Seal {
+	return Seal{
+		ID:     seal.ID,
+		Number: seal.Number,
+		Place:  seal.Place,
+	}
+}
+
+func MapSealSliceToDomain(seals []subscriber.Seal) []Seal {
+	result := make([]Seal, 0, len(seals))
+	for _, seal := range seals {
+		result = append(result, MapSealToDomain(seal))
+	}
+
+	return result
+}
+
+func MapContractToDomain(contract subscriber.Contract) Contract {
+	return Contract{
+		Number:   contract.Number,
+		SignDate: contract.SignDate,
 	}
 }
 
@@ -113,3 +175,116 @@ func MapSubscriberToDomain(s subscriber.Subscriber) Subscriber {
 		Status:        s.Status,
 	}
 }
+
+// MaskFinishedTask masks the subscriber and the object address, the parsed address parts are cleared as they repeat it.
+func MaskFinishedTask(m *masking.Masker, policy masking.Policy, t FinishedTask) FinishedTask {
+	t.Subscriber = MaskSubscriber(m, policy, t.Subscriber)
+	t.Subscriber.AccountNumber = m.AccountNumber(policy, t.Subscriber.AccountNumber)
+	t.Object.Address = m.Address(policy, t.Object.Address)
+	if policy != masking.PolicyFull {
+		t.Object.AddressParts = address.Address{}
+	}
+
+	return t
+}
+
+func MaskFinishedTaskSlice(m *masking.Masker, policy masking.Policy, tasks []FinishedTask) []FinishedTask {
+	result := make([]FinishedTask, 0, len(tasks))
+	for _, t := range tasks {
+		result = append(result, MaskFinishedTask(m, policy, t))
+	}
+
+	return result
+}
+
+func MaskSubscriber(m *masking.Masker, policy masking.Policy, s Subscriber) Subscriber {
+	s.Surname = m.Surname(policy, s.Surname)
+	s.Name = m.Initial(policy, s.Name)
+	s.Patronymic = m.Initial(policy, s.Patronymic)
+	s.PhoneNumber = m.Phone(policy, s.PhoneNumber)
+	s.Email = m.Email(policy, s.Email)
+	s.INN = m.INN(policy, s.INN)
+	s.BirthDate = m.BirthDate(policy, s.BirthDate)
+
+	return s
+}
+
+func MaskLimitationTurnaroundSlice(m *masking.Masker, policy masking.Policy, turnarounds []LimitationTurnaround) []LimitationTurnaround {
+	for i := range turnarounds {
+		turnarounds[i].ObjectAddress = m.Address(policy, turnarounds[i].ObjectAddress)
+		turnarounds[i].SubscriberAccountNumber = m.AccountNumber(policy, turnarounds[i].SubscriberAccountNumber)
+	}
+
+	return turnarounds
+}
+
+func MaskObjectTurnaroundSlice(m *masking.Masker, policy masking.Policy, turnarounds []ObjectTurnaround) []ObjectTurnaround {
+	for i := range turnarounds {
+		turnarounds[i].ObjectAddress = m.Address(policy, turnarounds[i].ObjectAddress)
+	}
+
+	return turnarounds
+}
+
+func MaskDeviceHistory(m *masking.Masker, policy masking.Policy, h DeviceHistory) DeviceHistory {
+	for i := range h.Readings {
+		h.Readings[i].ObjectAddress = m.Address(policy, h.Readings[i].ObjectAddress)
+		h.Readings[i].SubscriberAccountNumber = m.AccountNumber(policy, h.Readings[i].SubscriberAccountNumber)
+	}
+
+	return h
+}
+
+func MapMessageToRawEvent(message kafka.Message, receivedAt time.Time) RawEvent {
+	headers := make(map[string]string, len(message.Headers))
+	for _, header := range message.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+
+	return RawEvent{
+		Topic:      message.Topic,
+		Partition:  message.Partition,
+		Offset:     message.Offset,
+		Key:        message.Key,
+		Headers:    headers,
+		Value:      message.Value,
+		ReceivedAt: receivedAt,
+	}
+}
+
+func MapRawEventToMessage(e RawEvent) kafka.Message {
+	headers := make([]kafka.Header, 0, len(e.Headers))
+	for key, value := range e.Headers {
+		headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+	}
+
+	return kafka.Message{
+		Topic:     e.Topic,
+		Partition: e.Partition,
+		Offset:    e.Offset,
+		Key:       e.Key,
+		Headers:   headers,
+		Value:     e.Value,
+		Time:      e.ReceivedAt,
+	}
+}
+
+// AnonymizeFinishedTask clears personal data of the subscriber the way an erasure anonymizes stored finished tasks:
+// the district of an unparsed address is kept, so the task stays in its district aggregates.
+func AnonymizeFinishedTask(t FinishedTask) FinishedTask {
+	t.Subscriber = Subscriber{
+		ID:        t.Subscriber.ID,
+		BirthDate: time.Unix(0, 0).UTC(),
+		Status:    t.Subscriber.Status,
+	}
+	t.Contract = Contract{}
+
+	parts := &t.Object.AddressParts
+	if parts.District == "" && parts.Street == "" {
+		parts.District, _, _ = strings.Cut(t.Object.Address, ",")
+	}
+	parts.Street, parts.House, parts.Flat = "", "", ""
+	t.Object.Address = ""
+
+	return t
+}
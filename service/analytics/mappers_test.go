@@ -0,0 +1,70 @@
+package analytics
+
+import (
+	"analytics-service/config"
+	"analytics-service/service/address"
+	"analytics-service/service/masking"
+	"testing"
+	"time"
+)
+
+func TestAnonymizeFinishedTask(t *testing.T) {
+	task := FinishedTask{
+		TaskID: 1,
+		Object: Object{
+			ID:      10,
+			Address: "Кировский р-н, ул. Ленина, 1",
+		},
+		Subscriber: Subscriber{ID: 5, AccountNumber: "123", Surname: "Иванов", PhoneNumber: "+79990000000"},
+		Contract:   Contract{Number: "K-1", SignDate: "2024-01-01"},
+		Tenant:     "ekb",
+	}
+
+	got := AnonymizeFinishedTask(task)
+	if got.Subscriber != (Subscriber{ID: 5, BirthDate: time.Unix(0, 0).UTC()}) || got.Contract != (Contract{}) {
+		t.Fatalf("personal data is kept: %+v %+v", got.Subscriber, got.Contract)
+	}
+	if got.Object.Address != "" || got.Object.AddressParts != (address.Address{District: "Кировский р-н"}) {
+		t.Fatalf("expected only the district of the address, got %+v", got.Object)
+	}
+	if got.TaskID != 1 || got.Object.ID != 10 || got.Tenant != "ekb" {
+		t.Fatalf("task is changed: %+v", got)
+	}
+
+	task.Object.AddressParts = address.Address{City: "Екатеринбург", Street: "ул. Ленина", House: "1"}
+	if got = AnonymizeFinishedTask(task); got.Object.AddressParts != (address.Address{City: "Екатеринбург"}) {
+		t.Fatalf("expected the parsed district to be kept, got %+v", got.Object.AddressParts)
+	}
+}
+
+func TestMaskFinishedTaskMasksBasicReportRow(t *testing.T) {
+	m, err := masking.NewMasker(config.Masking{DefaultPolicy: "partial", HashSalt: "salt"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	task := FinishedTask{
+		Object: Object{
+			Address:      "620000, г. Екатеринбург, ул. Ленина, д. 5, кв. 12",
+			AddressParts: address.Address{City: "Екатеринбург", Street: "ул. Ленина", House: "5", Flat: "12"},
+		},
+		Subscriber: Subscriber{AccountNumber: "6600123456", Surname: "Иванов", Name: "Петр"},
+	}
+
+	partial := MaskFinishedTask(m, masking.PolicyPartial, task)
+	if partial.Object.AddressParts != (address.Address{}) {
+		t.Fatalf("expected the address parts to be cleared, got %+v", partial.Object.AddressParts)
+	}
+
+	row := basicReportRow(1, partial)
+	if row[1] != "г. Екатеринбург, ул. Ленина" || row[2] != "И*** П." || row[3] != "******3456" {
+		t.Fatalf("unexpected partial row: %v", row)
+	}
+
+	row = basicReportRow(1, MaskFinishedTask(m, masking.PolicyHashed, task))
+	for _, i := range []int{1, 3} {
+		if v, _ := row[i].(string); v == "" || v == task.Object.Address || v == task.Subscriber.AccountNumber {
+			t.Fatalf("expected column %d to be hashed, got %q", i, v)
+		}
+	}
+}
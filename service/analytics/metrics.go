@@ -0,0 +1,127 @@
+package analytics
+
+import (
+	"analytics-service/cluster/task"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	upstreamInspection = "inspection"
+	upstreamBrigade    = "brigade"
+	upstreamSubscriber = "subscriber"
+
+	tableRawEvents     = "raw_events"
+	tableFinishedTasks = "finished_tasks"
+	tableReplayTasks   = "finished_tasks_replay"
+
+	eventTypeInvalid = "invalid"
+)
+
+var (
+	eventsConsumedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "analytics",
+		Subsystem: "ingestion",
+		Name:      "events_consumed_total",
+		Help:      "Consumed task events by event type, unparseable messages have type invalid.",
+	}, []string{"type"})
+
+	eventsProcessedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "analytics",
+		Subsystem: "ingestion",
+		Name:      "events_processed_total",
+		Help:      "Successfully handled task events by event type.",
+	}, []string{"type"})
+
+	eventsFailedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "analytics",
+		Subsystem: "ingestion",
+		Name:      "events_failed_total",
+		Help:      "Failed attempts to handle task events by event type and action (retry, skip, quarantine or dead_letter).",
+	}, []string{"type", "action"})
+
+	enrichmentDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "analytics",
+		Subsystem: "ingestion",
+		Name:      "enrichment_duration_seconds",
+		Help:      "Latency of upstream lookups made to enrich finished tasks by upstream service.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service"})
+
+	insertDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "analytics",
+		Subsystem: "ingestion",
+		Name:      "clickhouse_insert_duration_seconds",
+		Help:      "Latency of batched ClickHouse inserts by table, finished_tasks_replay covers all replay tables.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"table"})
+
+	// lastProcessedEventAt holds the date of the last handled event in unix nanoseconds.
+	lastProcessedEventAt atomic.Int64
+
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "analytics",
+		Subsystem: "ingestion",
+		Name:      "last_processed_event_age_seconds",
+		Help:      "Time passed since the date of the last handled task event, 0 until an event is handled.",
+	}, func() float64 {
+		at := lastProcessedEventAt.Load()
+		if at == 0 {
+			return 0
+		}
+
+		return time.Since(time.Unix(0, at)).Seconds()
+	})
+)
+
+func (a failureAction) Name() string {
+	switch a {
+	case failureRetry:
+		return "retry"
+	case failureSkip:
+		return "skip"
+	case failureQuarantine:
+		return "quarantine"
+	default:
+		return "dead_letter"
+	}
+}
+
+func observeDuration(h *prometheus.HistogramVec, label string, start time.Time) {
+	h.WithLabelValues(label).Observe(time.Since(start).Seconds())
+}
+
+// Events of a replay were counted when they were consumed, so replays don't touch event metrics. Otherwise the
+// counters would grow twice and the last processed event would move back in time.
+func (p *pipeline) countConsumed(eventType string) {
+	if !p.replay {
+		eventsConsumedTotal.WithLabelValues(eventType).Inc()
+	}
+}
+
+func (p *pipeline) countProcessed(event task.Event) {
+	if !p.replay {
+		eventsProcessedTotal.WithLabelValues(event.Type.Name()).Inc()
+		storeLatest(&lastProcessedEventAt, event.Date.UnixNano())
+	}
+}
+
+// storeLatest stores at unless v already holds a later time. Events are handled by several workers, so an older
+// event can finish after a newer one.
+func storeLatest(v *atomic.Int64, at int64) {
+	for {
+		current := v.Load()
+		if current >= at || v.CompareAndSwap(current, at) {
+			return
+		}
+	}
+}
+
+func (p *pipeline) countFailed(eventType string, action failureAction) {
+	if !p.replay {
+		eventsFailedTotal.WithLabelValues(eventType, action.Name()).Inc()
+	}
+}
@@ -0,0 +1,28 @@
+package analytics
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStoreLatestKeepsLaterTime(t *testing.T) {
+	var v atomic.Int64
+
+	var wg sync.WaitGroup
+	for at := int64(1); at <= 100; at++ {
+		wg.Go(func() {
+			storeLatest(&v, at)
+		})
+	}
+	wg.Wait()
+
+	if got := v.Load(); got != 100 {
+		t.Fatalf("expected the latest time 100, got %d", got)
+	}
+
+	storeLatest(&v, 50)
+	if got := v.Load(); got != 100 {
+		t.Fatalf("expected an earlier time to be ignored, got %d", got)
+	}
+}
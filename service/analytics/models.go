@@ -4,6 +4,9 @@ import (
 	"analytics-service/cluster/file"
 	"analytics-service/cluster/inspection"
 	"analytics-service/cluster/subscriber"
+	"analytics-service/service/address"
+	"analytics-service/service/masking"
+	"encoding/json"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -14,15 +17,55 @@ type ReportType int
 const (
 	ReportTypeUnknown ReportType = iota
 	ReportTypeBasic
+	ReportTypeInspectors
+	ReportTypePunctuality
+	ReportTypeWatchList
+	ReportTypeDevicePassport
+	ReportTypeComparison
 )
 
+// Name is also used as the masking scope of the report.
+func (t ReportType) Name() string {
+	switch t {
+	case ReportTypeBasic:
+		return "basic"
+	case ReportTypeInspectors:
+		return "inspectors"
+	case ReportTypePunctuality:
+		return "punctuality"
+	case ReportTypeWatchList:
+		return "watchlist"
+	case ReportTypeDevicePassport:
+		return "device_passport"
+	case ReportTypeComparison:
+		return "comparison"
+	default:
+		return "unknown"
+	}
+}
+
+var reportTypes = []ReportType{
+	ReportTypeBasic,
+	ReportTypeInspectors,
+	ReportTypePunctuality,
+	ReportTypeWatchList,
+	ReportTypeDevicePassport,
+	ReportTypeComparison,
+}
+
+// ReportAccess lists the masking policies of the reports of every type a caller may read.
+type ReportAccess map[ReportType][]masking.Policy
+
+// Report covers the Tenants of its creator, an empty list means all tenants.
 type Report struct {
-	ID          int         `json:"ID"`
-	Type        ReportType  `json:"Type"`
-	Files       []file.File `json:"Files"`
-	PeriodStart time.Time   `json:"PeriodStart"`
-	PeriodEnd   time.Time   `json:"PeriodEnd"`
-	CreatedAt   time.Time   `json:"CreatedAt"`
+	ID            int            `json:"ID"`
+	Type          ReportType     `json:"Type"`
+	Files         []file.File    `json:"Files"`
+	PeriodStart   time.Time      `json:"PeriodStart"`
+	PeriodEnd     time.Time      `json:"PeriodEnd"`
+	MaskingPolicy masking.Policy `json:"MaskingPolicy"`
+	Tenants       []string       `json:"Tenants"`
+	CreatedAt     time.Time      `json:"CreatedAt"`
 }
 
 type FinishedTask struct {
@@ -35,6 +78,8 @@ type FinishedTask struct {
 	Brigade     Brigade    `json:"Brigade"`
 	Object      Object     `json:"Object"`
 	Subscriber  Subscriber `json:"Subscriber"`
+	Contract    Contract   `json:"Contract"`
+	Tenant      string     `json:"Tenant"`
 }
 
 type Inspection struct {
@@ -82,9 +127,33 @@ type Inspector struct {
 }
 
 type Object struct {
-	ID            int    `json:"ID"`
-	Address       string `json:"Address"`
-	HaveAutomaton bool   `json:"HaveAutomaton"`
+	ID            int             `json:"ID"`
+	Address       string          `json:"Address"`
+	AddressParts  address.Address `json:"AddressParts"`
+	HaveAutomaton bool            `json:"HaveAutomaton"`
+	Devices       []Device        `json:"Devices"`
+}
+
+// Device is a meter installed at the object when the task was finished.
+type Device struct {
+	ID               int                        `json:"ID"`
+	Type             string                     `json:"Type"`
+	Number           string                     `json:"Number"`
+	PlaceType        subscriber.DevicePlaceType `json:"PlaceType"`
+	PlaceDescription string                     `json:"PlaceDescription"`
+	Seals            []Seal                     `json:"Seals"`
+}
+
+type Seal struct {
+	ID     int    `json:"ID"`
+	Number string `json:"Number"`
+	Place  string `json:"Place"`
+}
+
+// Contract is the last contract of the object when the task was finished, SignDate is kept as the subscriber service returns it.
+type Contract struct {
+	Number   string `json:"Number"`
+	SignDate string `json:"SignDate"`
 }
 
 type Subscriber struct {
@@ -99,3 +168,328 @@ type Subscriber struct {
 	BirthDate     time.Time         `json:"BirthDate"`
 	Status        subscriber.Status `json:"Status"`
 }
+
+// QuarantinedTask is a task event that failed validation, Payload is the raw Kafka message.
+type QuarantinedTask struct {
+	ID            int             `json:"ID"`
+	TaskID        int             `json:"TaskID"`
+	Payload       json.RawMessage `json:"Payload" swaggertype:"object"`
+	MissingFields []string        `json:"MissingFields"`
+	Reason        string          `json:"Reason"`
+	CreatedAt     time.Time       `json:"CreatedAt"`
+	RepairedAt    *time.Time      `json:"RepairedAt"`
+}
+
+// DeadLetter is a consumed message that couldn't be handled, Payload is the raw Kafka message. TaskID is 0 if the
+// message couldn't be parsed.
+type DeadLetter struct {
+	ID        int       `json:"ID"`
+	Topic     string    `json:"Topic"`
+	Partition int       `json:"Partition"`
+	Offset    int64     `json:"Offset"`
+	TaskID    int       `json:"TaskID"`
+	Payload   []byte    `json:"Payload"`
+	Reason    string    `json:"Reason"`
+	CreatedAt time.Time `json:"CreatedAt"`
+}
+
+type RepairResult struct {
+	Repaired     []int             `json:"Repaired"`
+	StillInvalid []QuarantinedTask `json:"StillInvalid"`
+	Failed       []RepairFailure   `json:"Failed"`
+}
+
+// RepairFailure is a quarantined task that couldn't be repaired for a reason other than missing data, it stays in
+// quarantine and is retried by the next repair.
+type RepairFailure struct {
+	ID     int    `json:"ID"`
+	Reason string `json:"Reason"`
+}
+
+// RawEvent is a consumed Kafka message as is.
+type RawEvent struct {
+	Topic      string
+	Partition  int
+	Offset     int64
+	Key        []byte
+	Headers    map[string]string
+	Value      []byte
+	ReceivedAt time.Time
+}
+
+type ReplayStatus string
+
+const (
+	ReplayStatusPending ReplayStatus = "pending"
+	ReplayStatusRunning ReplayStatus = "running"
+	ReplayStatusDone    ReplayStatus = "done"
+	ReplayStatusFailed  ReplayStatus = "failed"
+	ReplayStatusExpired ReplayStatus = "expired"
+)
+
+// ReplayJob re-runs task events received in [PeriodStart, PeriodEnd) into Table in the background. Events is the
+// number of replayed events, Error explains why a failed job stopped. Table is dropped once the job expires.
+type ReplayJob struct {
+	ID          int          `json:"ID"`
+	PeriodStart time.Time    `json:"PeriodStart"`
+	PeriodEnd   time.Time    `json:"PeriodEnd"`
+	Table       string       `json:"Table"`
+	Status      ReplayStatus `json:"Status"`
+	Events      int          `json:"Events"`
+	Error       string       `json:"Error"`
+	RequestedBy int          `json:"RequestedBy"`
+	CreatedAt   time.Time    `json:"CreatedAt"`
+	FinishedAt  *time.Time   `json:"FinishedAt"`
+}
+
+// InspectorDay is the output of an inspector in one brigade during one day.
+type InspectorDay struct {
+	Day                     time.Time
+	InspectorID             int
+	InspectorFullName       string
+	BrigadeID               int
+	TasksCount              int
+	ViolationsDetectedCount int
+	TotalDurationMinutes    int
+}
+
+type InspectorPerformance struct {
+	InspectorID             int                 `json:"InspectorID"`
+	FullName                string              `json:"FullName"`
+	TasksCount              int                 `json:"TasksCount"`
+	ViolationsDetectedCount int                 `json:"ViolationsDetectedCount"`
+	AvgDurationMinutes      float64             `json:"AvgDurationMinutes"`
+	BrigadeIDs              []int               `json:"BrigadeIDs"`
+	Workload                []InspectorWorkload `json:"Workload"`
+}
+
+type InspectorWorkload struct {
+	Day        time.Time `json:"Day"`
+	TasksCount int       `json:"TasksCount"`
+}
+
+// VisitDelay is how late a brigade started a task, negative DelayMinutes mean it started before PlanVisitAt.
+type VisitDelay struct {
+	Day          time.Time
+	TaskID       int
+	BrigadeID    int
+	Inspectors   []VisitInspector
+	DistrictName string
+	PlanVisitAt  time.Time
+	StartedAt    time.Time
+	DelayMinutes int
+}
+
+type VisitInspector struct {
+	ID       int
+	FullName string
+}
+
+// Punctuality groups visit delays by brigade, inspector, district and planned day.
+// A visit is on time if it started no more than EarlyToleranceMinutes before and LateToleranceMinutes after the plan.
+type Punctuality struct {
+	EarlyToleranceMinutes int                `json:"EarlyToleranceMinutes"`
+	LateToleranceMinutes  int                `json:"LateToleranceMinutes"`
+	Total                 PunctualityMetrics `json:"Total"`
+	ByBrigade             []PunctualityGroup `json:"ByBrigade"`
+	ByInspector           []PunctualityGroup `json:"ByInspector"`
+	ByDistrict            []PunctualityGroup `json:"ByDistrict"`
+	ByDay                 []PunctualityGroup `json:"ByDay"`
+}
+
+// PunctualityGroup is keyed by the brigade or inspector ID, the district name or the day in YYYY-MM-DD format.
+type PunctualityGroup struct {
+	Key     string             `json:"Key"`
+	Name    string             `json:"Name"`
+	Metrics PunctualityMetrics `json:"Metrics"`
+}
+
+type PunctualityMetrics struct {
+	TasksCount         int           `json:"TasksCount"`
+	OnTimeCount        int           `json:"OnTimeCount"`
+	EarlyCount         int           `json:"EarlyCount"`
+	LateCount          int           `json:"LateCount"`
+	OnTimeShare        float64       `json:"OnTimeShare"`
+	MedianDelayMinutes float64       `json:"MedianDelayMinutes"`
+	P90DelayMinutes    float64       `json:"P90DelayMinutes"`
+	Buckets            []DelayBucket `json:"Buckets"`
+}
+
+// DelayBucket counts visits with FromMinutes <= delay < ToMinutes, a nil bound is unlimited.
+type DelayBucket struct {
+	FromMinutes *int `json:"FromMinutes"`
+	ToMinutes   *int `json:"ToMinutes"`
+	TasksCount  int  `json:"TasksCount"`
+}
+
+// LimitationTurnaround pairs a successful limitation with the next successful resumption of the same object.
+// Resumption fields are nil while the object stays disconnected.
+type LimitationTurnaround struct {
+	ObjectID                int        `json:"ObjectID"`
+	ObjectAddress           string     `json:"ObjectAddress"`
+	SubscriberAccountNumber string     `json:"SubscriberAccountNumber"`
+	LimitationTaskID        int        `json:"LimitationTaskID"`
+	LimitedAt               time.Time  `json:"LimitedAt"`
+	ResumptionTaskID        *int       `json:"ResumptionTaskID"`
+	ResumedAt               *time.Time `json:"ResumedAt"`
+	DisconnectedMinutes     *int       `json:"DisconnectedMinutes"`
+}
+
+// ObjectRefusals counts limitation and resumption visits where the brigade wasn't let in.
+type ObjectRefusals struct {
+	ObjectID                int
+	ObjectAddress           string
+	LimitationRefusalsCount int
+	ResumptionRefusalsCount int
+}
+
+type ObjectTurnaround struct {
+	ObjectID                int     `json:"ObjectID"`
+	ObjectAddress           string  `json:"ObjectAddress"`
+	LimitationsCount        int     `json:"LimitationsCount"`
+	ResumedCount            int     `json:"ResumedCount"`
+	OpenCount               int     `json:"OpenCount"`
+	AvgDisconnectedHours    float64 `json:"AvgDisconnectedHours"`
+	MaxDisconnectedHours    float64 `json:"MaxDisconnectedHours"`
+	LimitationRefusalsCount int     `json:"LimitationRefusalsCount"`
+	ResumptionRefusalsCount int     `json:"ResumptionRefusalsCount"`
+}
+
+type WatchListRule string
+
+const (
+	WatchListRuleUnauthorizedAfterLimitation WatchListRule = "unauthorized_after_limitation"
+	WatchListRuleRepeatedViolations          WatchListRule = "repeated_violations"
+	WatchListRuleFrequentVisits              WatchListRule = "frequent_visits"
+)
+
+// WatchList is the ranked output of the recidivism rules, subscriber data is masked with MaskingPolicy.
+type WatchList struct {
+	PeriodStart   time.Time        `json:"PeriodStart"`
+	PeriodEnd     time.Time        `json:"PeriodEnd"`
+	MaskingPolicy masking.Policy   `json:"MaskingPolicy"`
+	Entries       []WatchListEntry `json:"Entries"`
+}
+
+// WatchListEntry is a subscriber at an object that fired at least one recidivism rule.
+// Entries are ranked by Score, the sum of the weights of all hits.
+type WatchListEntry struct {
+	Rank          int            `json:"Rank"`
+	Score         int            `json:"Score"`
+	ObjectID      int            `json:"ObjectID"`
+	ObjectAddress string         `json:"ObjectAddress"`
+	Subscriber    Subscriber     `json:"Subscriber"`
+	LastTaskAt    time.Time      `json:"LastTaskAt"`
+	Hits          []WatchListHit `json:"Hits"`
+}
+
+// WatchListHit is a fired rule with the tasks that support it.
+type WatchListHit struct {
+	Rule    WatchListRule `json:"Rule"`
+	TaskIDs []int         `json:"TaskIDs"`
+}
+
+type ReadingFlag string
+
+const (
+	ReadingFlagRollback ReadingFlag = "rollback"
+	ReadingFlagJump     ReadingFlag = "jump"
+)
+
+// DeviceReading is a meter reading taken during a task. Delta fields compare it with the previous reading of the device
+// and are nil for the first one.
+type DeviceReading struct {
+	DeviceID                int              `json:"DeviceID"`
+	InspectedDeviceID       int              `json:"InspectedDeviceID"`
+	ReadAt                  time.Time        `json:"ReadAt"`
+	Value                   decimal.Decimal  `json:"Value"`
+	ConsumptionKWh          decimal.Decimal  `json:"ConsumptionKWh"`
+	DeviceType              string           `json:"DeviceType"`
+	DeviceNumber            string           `json:"DeviceNumber"`
+	SealNumbers             []string         `json:"SealNumbers"`
+	TaskID                  int              `json:"TaskID"`
+	FinishedAt              time.Time        `json:"FinishedAt"`
+	ObjectID                int              `json:"ObjectID"`
+	ObjectAddress           string           `json:"ObjectAddress"`
+	SubscriberID            int              `json:"SubscriberID"`
+	SubscriberAccountNumber string           `json:"SubscriberAccountNumber"`
+	Delta                   *decimal.Decimal `json:"Delta"`
+	DaysSincePrevious       *float64         `json:"DaysSincePrevious"`
+	DailyDelta              *float64         `json:"DailyDelta"`
+	Flags                   []ReadingFlag    `json:"Flags"`
+}
+
+// DeviceHistory is the reading timeline of a device, the oldest reading first.
+type DeviceHistory struct {
+	DeviceID       int             `json:"DeviceID"`
+	FirstReadAt    *time.Time      `json:"FirstReadAt"`
+	LastReadAt     *time.Time      `json:"LastReadAt"`
+	TotalDelta     decimal.Decimal `json:"TotalDelta"`
+	RollbacksCount int             `json:"RollbacksCount"`
+	JumpsCount     int             `json:"JumpsCount"`
+	Readings       []DeviceReading `json:"Readings"`
+}
+
+// Period is the range of days from Start inclusive to End exclusive.
+type Period struct {
+	Start time.Time `json:"Start"`
+	End   time.Time `json:"End"`
+}
+
+// TasksDaily is the number of finished tasks in a day by inspection type.
+type TasksDaily struct {
+	Day                         time.Time
+	TasksCount                  int
+	LimitationCount             int
+	ResumptionCount             int
+	VerificationCount           int
+	UnauthorizedConnectionCount int
+	ViolationsDetectedCount     int
+	UnauthorizedConsumersCount  int
+	AvgDurationMinutes          float64
+}
+
+// BrigadeDay is the output of a brigade during one day.
+type BrigadeDay struct {
+	Day                        time.Time
+	BrigadeID                  int
+	TasksCount                 int
+	AvgDurationMinutes         float64
+	SuccessfulLimitationsCount int
+	SuccessfulResumptionsCount int
+	ViolationsDetectedCount    int
+}
+
+// ComparisonMode chooses the period the current one is compared with.
+type ComparisonMode string
+
+const (
+	// ComparisonModePrevious takes the preceding period of the same length, whole months are shifted by months.
+	ComparisonModePrevious ComparisonMode = "previous"
+	// ComparisonModeYear takes the same period a year earlier.
+	ComparisonModeYear ComparisonMode = "year"
+	// ComparisonModeCustom takes an explicitly given period.
+	ComparisonModeCustom ComparisonMode = "custom"
+)
+
+// ComparisonMetric is a KPI in both periods. DeltaPercent is relative to the previous value and nil if it is zero.
+type ComparisonMetric struct {
+	Name         string   `json:"Name"`
+	Current      float64  `json:"Current"`
+	Previous     float64  `json:"Previous"`
+	Delta        float64  `json:"Delta"`
+	DeltaPercent *float64 `json:"DeltaPercent"`
+}
+
+type ComparisonGroup struct {
+	Key     string             `json:"Key"`
+	Metrics []ComparisonMetric `json:"Metrics"`
+}
+
+type Comparison struct {
+	Current          Period             `json:"Current"`
+	Previous         Period             `json:"Previous"`
+	Total            []ComparisonMetric `json:"Total"`
+	ByInspectionType []ComparisonGroup  `json:"ByInspectionType"`
+	ByBrigade        []ComparisonGroup  `json:"ByBrigade"`
+}
@@ -0,0 +1,232 @@
+package analytics
+
+import (
+	"analytics-service/cluster/task"
+	"analytics-service/tracing"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sunshineOfficial/golib/golog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// pipeline ingests one stream of task events: the consumed topic or a replay of archived events. Every pipeline has
+// its own workers and batches, enrichment is shared through the service.
+type pipeline struct {
+	service       *Service
+	repository    Repository
+	replay        bool
+	workers       *keyedWorkers
+	rawEvents     *batcher[RawEvent]
+	finishedTasks *batcher[FinishedTask]
+}
+
+// newPipeline creates a pipeline, a replay one keeps the ingestion metrics of the consumed topic intact and reports
+// its inserts under tableReplayTasks.
+func newPipeline(s *Service, repository Repository, replay bool) *pipeline {
+	batchMaxAge := time.Duration(s.ingestion.BatchMaxAge)
+
+	finishedTasksTable := tableFinishedTasks
+	if replay {
+		finishedTasksTable = tableReplayTasks
+	}
+
+	return &pipeline{
+		service:       s,
+		repository:    repository,
+		replay:        replay,
+		workers:       newKeyedWorkers(s.ingestion.Concurrency, s.ingestion.QueueSize),
+		rawEvents:     newBatcher(tableRawEvents, repository.AddRawEvents, s.ingestion.BatchSize, batchMaxAge),
+		finishedTasks: newBatcher(finishedTasksTable, repository.AddFinishedTasks, s.ingestion.BatchSize, batchMaxAge),
+	}
+}
+
+// handler returns the function that takes consumed messages. Messages are archived unless they are replayed.
+// Expired batches are flushed until mainCtx is done.
+func (p *pipeline) handler(mainCtx context.Context, log golog.Logger) func(message kafka.Message, ack func()) {
+	go p.flushExpiredBatches(mainCtx, log)
+
+	return func(message kafka.Message, ack func()) {
+		if !p.replay {
+			ack = ackAfter(2, ack)
+
+			if err := p.rawEvents.Add(mainCtx, MapMessageToRawEvent(message, time.Now()), ack); err != nil {
+				log.Errorf("raw event (partition = %d, offset = %d) is kept for the next flush: %v",
+					message.Partition, message.Offset, err)
+			}
+		}
+
+		var event task.Event
+		err := json.Unmarshal(message.Value, &event)
+		if err != nil {
+			p.countConsumed(eventTypeInvalid)
+			p.countFailed(eventTypeInvalid, failureDeadLetter)
+			if p.deadLetter(mainCtx, log, message, 0, err) {
+				log.Errorf("failed to unmarshal task event (partition = %d, offset = %d), dead-lettered: %v",
+					message.Partition, message.Offset, err)
+				ack()
+			}
+			return
+		}
+
+		p.countConsumed(event.Type.Name())
+
+		p.workers.Submit(event.Task.ID, func() {
+			ctx, span := tracer.Start(tracing.ExtractKafka(mainCtx, message), "process task event", trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(
+					attribute.String("messaging.destination.name", message.Topic),
+					attribute.Int("messaging.destination.partition.id", message.Partition),
+					attribute.Int64("messaging.kafka.offset", message.Offset),
+					attribute.String("task.event.type", event.Type.Name()),
+					attribute.Int("task.id", event.Task.ID),
+				))
+			defer span.End()
+
+			p.processTaskEvent(ctx, log, event, message, ack)
+		})
+	}
+}
+
+// wait waits until all taken messages are handled, the handler must not be called after that.
+func (p *pipeline) wait() {
+	p.workers.Close()
+}
+
+// flush inserts raw events and finished tasks that are still waiting in batches.
+func (p *pipeline) flush(ctx context.Context) error {
+	var errs []error
+	if err := p.rawEvents.Flush(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("flush raw events: %w", err))
+	}
+
+	if err := p.finishedTasks.Flush(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("flush finished tasks: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *pipeline) flushExpiredBatches(ctx context.Context, log golog.Logger) {
+	ticker := time.NewTicker(max(time.Duration(p.service.ingestion.BatchMaxAge)/2, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.rawEvents.FlushExpired(ctx); err != nil {
+				log.Errorf("failed to flush raw events: %v", err)
+			}
+
+			if err := p.finishedTasks.FlushExpired(ctx); err != nil {
+				log.Errorf("failed to flush finished tasks: %v", err)
+			}
+		}
+	}
+}
+
+// processTaskEvent calls ack once the event is handled, skipped, quarantined or dead-lettered. Each attempt is limited
+// by kafkaSubscribeTimeout. An event interrupted by ctx cancellation on shutdown is not acked, so it's consumed again
+// after restart.
+func (p *pipeline) processTaskEvent(ctx context.Context, log golog.Logger, event task.Event, message kafka.Message, ack func()) {
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, kafkaSubscribeTimeout)
+		err := p.handleTaskEvent(attemptCtx, log, event, ack)
+		cancel()
+
+		if err == nil {
+			p.countProcessed(event)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		trace.SpanFromContext(ctx).RecordError(err)
+
+		action := classifyFailure(err)
+		p.countFailed(event.Type.Name(), action)
+
+		switch action {
+		case failureSkip:
+			log.Errorf("skipped task event (type = %d, task = %d): %v", event.Type, event.Task.ID, err)
+			ack()
+			return
+		case failureQuarantine:
+			quarantineErr := p.quarantineTaskEvent(ctx, event, message.Value, err)
+			if quarantineErr == nil {
+				log.Errorf("quarantined task event (type = %d, task = %d): %v", event.Type, event.Task.ID, err)
+				ack()
+				return
+			}
+
+			err = errors.Join(err, quarantineErr)
+		case failureRetry:
+			log.Errorf("failed to handle task event (type = %d, task = %d), attempt %d: %v", event.Type, event.Task.ID, attempt, err)
+
+			if waitErr := wait(ctx, eventRetryBackoff); waitErr != nil {
+				return
+			}
+
+			continue
+		}
+
+		if !p.deadLetter(ctx, log, message, event.Task.ID, err) {
+			return
+		}
+
+		log.Errorf("failed to handle task event (type = %d, task = %d), dead-lettered: %v", event.Type, event.Task.ID, err)
+		trace.SpanFromContext(ctx).SetStatus(codes.Error, "dead-lettered")
+		ack()
+		return
+	}
+}
+
+func (p *pipeline) handleTaskEvent(ctx context.Context, log golog.Logger, event task.Event, ack func()) error {
+	var err error
+
+	// Replayed events are old, they must not drop cached contracts and brigades of the current state.
+	switch event.Type {
+	case task.EventTypeAdd:
+		if !p.replay {
+			err = p.service.handleAddedTask(ctx, log, event.Task)
+		}
+	case task.EventTypeStart:
+		err = p.service.handleStartedTask(ctx, event.Task)
+	case task.EventTypeFinish:
+		return p.handleFinishedTask(ctx, log, event.Task, ack)
+	case task.EventTypeAssign:
+		if !p.replay {
+			err = p.service.handleAssignedTask(ctx, log, event.Task)
+		}
+	default:
+		err = fmt.Errorf("unknown event type: %v", event.Type)
+	}
+
+	if err == nil {
+		ack()
+	}
+
+	return err
+}
+
+// handleFinishedTask passes ack to the batch, the event is acknowledged once its row is inserted.
+func (p *pipeline) handleFinishedTask(ctx context.Context, log golog.Logger, t task.Task, ack func()) error {
+	finishedTask, err := p.service.enrichFinishedTask(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	if err = p.finishedTasks.Add(ctx, finishedTask, ack); err != nil {
+		log.Errorf("finished task %d is kept for the next flush: %v", t.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,324 @@
+package analytics
+
+import (
+	"analytics-service/cluster/file"
+	"analytics-service/service/auth"
+	"analytics-service/tracing"
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/golog"
+	"github.com/sunshineOfficial/golib/gotime"
+	"github.com/xuri/excelize/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// delayBucketBounds split visit delays in minutes into distribution buckets.
+var delayBucketBounds = []int{-60, -30, -15, 0, 15, 30, 60, 120}
+
+func (s *Service) GetPunctuality(ctx goctx.Context, periodStart, periodEnd time.Time) (Punctuality, error) {
+	periodStart, periodEnd, err := reportPeriod(periodStart, periodEnd)
+	if err != nil {
+		return Punctuality{}, err
+	}
+
+	delays, err := s.repository.GetVisitDelaysByPeriod(ctx, auth.FromContext(ctx).TenantScope(), periodStart, periodEnd)
+	if err != nil {
+		return Punctuality{}, fmt.Errorf("get visit delays: %w", err)
+	}
+
+	return ComputePunctuality(delays, time.Duration(s.punctuality.EarlyTolerance), time.Duration(s.punctuality.LateTolerance)), nil
+}
+
+// ComputePunctuality calculates lateness metrics of the visits in total and for every brigade, inspector, district and day.
+// A visit of a brigade with several inspectors counts for each of them.
+func ComputePunctuality(delays []VisitDelay, earlyTolerance, lateTolerance time.Duration) Punctuality {
+	early, late := int(earlyTolerance.Minutes()), int(lateTolerance.Minutes())
+
+	all := make([]int, 0, len(delays))
+	byBrigade := newDelayGroups()
+	byInspector := newDelayGroups()
+	byDistrict := newDelayGroups()
+	byDay := newDelayGroups()
+
+	for _, d := range delays {
+		all = append(all, d.DelayMinutes)
+		byBrigade.add(strconv.Itoa(d.BrigadeID), "", d.DelayMinutes)
+		byDistrict.add(d.DistrictName, d.DistrictName, d.DelayMinutes)
+		byDay.add(d.Day.Format(time.DateOnly), "", d.DelayMinutes)
+
+		for _, i := range d.Inspectors {
+			byInspector.add(strconv.Itoa(i.ID), i.FullName, d.DelayMinutes)
+		}
+	}
+
+	return Punctuality{
+		EarlyToleranceMinutes: early,
+		LateToleranceMinutes:  late,
+		Total:                 computePunctualityMetrics(all, early, late),
+		ByBrigade:             byBrigade.metrics(early, late),
+		ByInspector:           byInspector.metrics(early, late),
+		ByDistrict:            byDistrict.metrics(early, late),
+		ByDay:                 byDay.metrics(early, late),
+	}
+}
+
+type delayGroups struct {
+	keys   []string
+	names  map[string]string
+	delays map[string][]int
+}
+
+func newDelayGroups() *delayGroups {
+	return &delayGroups{
+		names:  make(map[string]string),
+		delays: make(map[string][]int),
+	}
+}
+
+func (g *delayGroups) add(key, name string, delay int) {
+	if _, ok := g.delays[key]; !ok {
+		g.keys = append(g.keys, key)
+	}
+
+	if name != "" {
+		g.names[key] = name
+	}
+
+	g.delays[key] = append(g.delays[key], delay)
+}
+
+// metrics returns the groups sorted by key, numeric keys are compared as numbers.
+func (g *delayGroups) metrics(early, late int) []PunctualityGroup {
+	slices.SortFunc(g.keys, func(a, b string) int {
+		aID, aErr := strconv.Atoi(a)
+		bID, bErr := strconv.Atoi(b)
+		if aErr == nil && bErr == nil {
+			return cmp.Compare(aID, bID)
+		}
+
+		return cmp.Compare(a, b)
+	})
+
+	result := make([]PunctualityGroup, 0, len(g.keys))
+	for _, key := range g.keys {
+		result = append(result, PunctualityGroup{
+			Key:     key,
+			Name:    g.names[key],
+			Metrics: computePunctualityMetrics(g.delays[key], early, late),
+		})
+	}
+
+	return result
+}
+
+func computePunctualityMetrics(delays []int, early, late int) PunctualityMetrics {
+	m := PunctualityMetrics{
+		TasksCount: len(delays),
+		Buckets:    newDelayBuckets(),
+	}
+	if len(delays) == 0 {
+		return m
+	}
+
+	sorted := slices.Clone(delays)
+	slices.Sort(sorted)
+
+	for _, delay := range sorted {
+		switch {
+		case delay < -early:
+			m.EarlyCount++
+		case delay > late:
+			m.LateCount++
+		default:
+			m.OnTimeCount++
+		}
+
+		i, _ := slices.BinarySearch(delayBucketBounds, delay+1)
+		m.Buckets[i].TasksCount++
+	}
+
+	m.OnTimeShare = round2(float64(m.OnTimeCount) / float64(m.TasksCount))
+	m.MedianDelayMinutes = round2(percentile(sorted, 0.5))
+	m.P90DelayMinutes = round2(percentile(sorted, 0.9))
+
+	return m
+}
+
+func newDelayBuckets() []DelayBucket {
+	buckets := make([]DelayBucket, 0, len(delayBucketBounds)+1)
+	for i := 0; i <= len(delayBucketBounds); i++ {
+		var b DelayBucket
+		if i > 0 {
+			from := delayBucketBounds[i-1]
+			b.FromMinutes = &from
+		}
+		if i < len(delayBucketBounds) {
+			to := delayBucketBounds[i]
+			b.ToMinutes = &to
+		}
+
+		buckets = append(buckets, b)
+	}
+
+	return buckets
+}
+
+// percentile interpolates linearly between the closest ranks of the sorted values.
+func percentile(sorted []int, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return float64(sorted[lower]) + (rank-float64(lower))*float64(sorted[upper]-sorted[lower])
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func (s *Service) CreatePunctualityReport(ctx goctx.Context, log golog.Logger, periodStart, periodEnd time.Time) (_ Report, err error) {
+	spanCtx, span := tracer.Start(ctx, "create punctuality report")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	ctx = goctx.Wrap(spanCtx)
+
+	periodStart, periodEnd, err = reportPeriod(periodStart, periodEnd)
+	if err != nil {
+		return Report{}, err
+	}
+
+	punctuality, err := s.GetPunctuality(ctx, periodStart, periodEnd)
+	if err != nil {
+		return Report{}, err
+	}
+	if punctuality.Total.TasksCount == 0 {
+		return Report{}, fmt.Errorf("no planned visits found from %s to %s", periodStart, periodEnd)
+	}
+
+	_, renderSpan := tracer.Start(ctx, "excelize render punctuality report",
+		trace.WithAttributes(attribute.Int("tasks", punctuality.Total.TasksCount)))
+	buf, err := renderPunctualityReport(log, punctuality)
+	tracing.End(renderSpan, err)
+	if err != nil {
+		return Report{}, err
+	}
+
+	fileName := fmt.Sprintf("Отчет по пунктуальности за %s-%s.xlsx",
+		periodStart.Format(gotime.DateOnlyNet), periodEnd.Format(gotime.DateOnlyNet))
+
+	uploadedFile, err := s.fileService.Upload(ctx, fileName, buf)
+	if err != nil {
+		return Report{}, fmt.Errorf("upload file: %w", err)
+	}
+
+	report := Report{
+		Type:          ReportTypePunctuality,
+		Files:         []file.File{uploadedFile},
+		PeriodStart:   periodStart,
+		PeriodEnd:     periodEnd,
+		MaskingPolicy: s.masker.Policy(ReportTypePunctuality.Name(), auth.FromContext(ctx).Role),
+		Tenants:       auth.FromContext(ctx).TenantScope().Tenants,
+	}
+
+	report, err = s.repository.AddReport(ctx, report)
+	if err != nil {
+		return Report{}, fmt.Errorf("add report: %w", err)
+	}
+
+	return report, nil
+}
+
+func renderPunctualityReport(log golog.Logger, p Punctuality) (*bytes.Buffer, error) {
+	f := excelize.NewFile()
+	defer func() {
+		if fErr := f.Close(); fErr != nil {
+			log.Errorf("close punctuality report file: %v", fErr)
+		}
+	}()
+
+	sheets := []struct {
+		name   string
+		title  string
+		groups []PunctualityGroup
+	}{
+		{name: "Итого", title: "Период", groups: []PunctualityGroup{{Key: "Всего", Metrics: p.Total}}},
+		{name: "По бригадам", title: "Бригада", groups: p.ByBrigade},
+		{name: "По инспекторам", title: "Инспектор", groups: p.ByInspector},
+		{name: "По районам", title: "Район", groups: p.ByDistrict},
+		{name: "По дням", title: "Дата", groups: p.ByDay},
+	}
+
+	for i, sheet := range sheets {
+		if i == 0 {
+			if err := f.SetSheetName(f.GetSheetName(0), sheet.name); err != nil {
+				return nil, fmt.Errorf("set sheet name: %w", err)
+			}
+		} else if _, err := f.NewSheet(sheet.name); err != nil {
+			return nil, fmt.Errorf("new sheet: %w", err)
+		}
+
+		header := []any{
+			sheet.title, "Задач", "Вовремя", "Раньше", "Позже", "Доля вовремя",
+			"Медиана опоздания, мин", "90-й перцентиль опоздания, мин",
+		}
+		for _, b := range p.Total.Buckets {
+			header = append(header, delayBucketName(b))
+		}
+
+		rows := [][]any{header}
+		for _, g := range sheet.groups {
+			title := g.Key
+			if g.Name != "" {
+				title = g.Name
+			}
+
+			row := []any{
+				title,
+				g.Metrics.TasksCount,
+				g.Metrics.OnTimeCount,
+				g.Metrics.EarlyCount,
+				g.Metrics.LateCount,
+				g.Metrics.OnTimeShare,
+				g.Metrics.MedianDelayMinutes,
+				g.Metrics.P90DelayMinutes,
+			}
+			for _, b := range g.Metrics.Buckets {
+				row = append(row, b.TasksCount)
+			}
+
+			rows = append(rows, row)
+		}
+
+		if err := setSheetRows(f, sheet.name, rows); err != nil {
+			return nil, err
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("write file to buffer: %w", err)
+	}
+
+	return buf, nil
+}
+
+func delayBucketName(b DelayBucket) string {
+	switch {
+	case b.FromMinutes == nil:
+		return fmt.Sprintf("меньше %d мин", *b.ToMinutes)
+	case b.ToMinutes == nil:
+		return fmt.Sprintf("%d мин и больше", *b.FromMinutes)
+	default:
+		return fmt.Sprintf("от %d до %d мин", *b.FromMinutes, *b.ToMinutes)
+	}
+}
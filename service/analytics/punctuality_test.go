@@ -0,0 +1,54 @@
+package analytics
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestComputePunctuality(t *testing.T) {
+	monday := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+	ivanov := VisitInspector{ID: 1, FullName: "Иванов И И"}
+	petrov := VisitInspector{ID: 2, FullName: "Петров П П"}
+
+	p := ComputePunctuality([]VisitDelay{
+		{Day: monday, TaskID: 1, BrigadeID: 10, Inspectors: []VisitInspector{ivanov, petrov}, DistrictName: "Центральный", DelayMinutes: -40},
+		{Day: monday, TaskID: 2, BrigadeID: 10, Inspectors: []VisitInspector{ivanov, petrov}, DistrictName: "Центральный", DelayMinutes: 5},
+		{Day: tuesday, TaskID: 3, BrigadeID: 2, Inspectors: []VisitInspector{ivanov}, DistrictName: "Заречный", DelayMinutes: 15},
+		{Day: tuesday, TaskID: 4, BrigadeID: 2, Inspectors: []VisitInspector{ivanov}, DistrictName: "Заречный", DelayMinutes: 90},
+	}, 30*time.Minute, 15*time.Minute)
+
+	total := p.Total
+	if total.TasksCount != 4 || total.OnTimeCount != 2 || total.EarlyCount != 1 || total.LateCount != 1 {
+		t.Fatalf("unexpected counts: %+v", total)
+	}
+
+	if total.OnTimeShare != 0.5 || total.MedianDelayMinutes != 10 || total.P90DelayMinutes != 67.5 {
+		t.Fatalf("unexpected share or percentiles: %+v", total)
+	}
+
+	bucketCounts := make([]int, 0, len(total.Buckets))
+	for _, b := range total.Buckets {
+		bucketCounts = append(bucketCounts, b.TasksCount)
+	}
+	if want := []int{0, 1, 0, 0, 1, 1, 0, 1, 0}; !slices.Equal(bucketCounts, want) {
+		t.Fatalf("expected buckets %v, got %v", want, bucketCounts)
+	}
+
+	if len(p.ByBrigade) != 2 || p.ByBrigade[0].Key != "2" || p.ByBrigade[1].Metrics.TasksCount != 2 {
+		t.Fatalf("unexpected brigade groups: %+v", p.ByBrigade)
+	}
+
+	if len(p.ByInspector) != 2 || p.ByInspector[0].Name != "Иванов И И" || p.ByInspector[0].Metrics.TasksCount != 4 {
+		t.Fatalf("unexpected inspector groups: %+v", p.ByInspector)
+	}
+
+	if len(p.ByDistrict) != 2 || p.ByDistrict[0].Key != "Заречный" || p.ByDistrict[0].Metrics.LateCount != 1 {
+		t.Fatalf("unexpected district groups: %+v", p.ByDistrict)
+	}
+
+	if len(p.ByDay) != 2 || p.ByDay[0].Key != "2025-03-03" || p.ByDay[0].Metrics.EarlyCount != 1 {
+		t.Fatalf("unexpected day groups: %+v", p.ByDay)
+	}
+}
@@ -0,0 +1,164 @@
+package analytics
+
+import (
+	"analytics-service/cluster/task"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+func (p *pipeline) quarantineTaskEvent(ctx context.Context, event task.Event, payload []byte, cause error) error {
+	quarantinedTask := QuarantinedTask{
+		TaskID:  event.Task.ID,
+		Payload: payload,
+		Reason:  cause.Error(),
+	}
+
+	var validationErr *ValidationError
+	if errors.As(cause, &validationErr) {
+		quarantinedTask.MissingFields = validationErr.MissingFields
+	}
+
+	if err := p.repository.AddQuarantinedTask(ctx, quarantinedTask); err != nil {
+		return fmt.Errorf("add quarantined task: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetQuarantinedTasks(ctx goctx.Context, page pagination.Pagination) ([]QuarantinedTask, error) {
+	tasks, err := s.repository.GetQuarantinedTasks(ctx, 0, page)
+	if err != nil {
+		return nil, fmt.Errorf("get quarantined tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// RepairQuarantinedTasks re-ingests quarantined tasks with fresh upstream data, id = 0 means all pending tasks.
+// Tasks that are still incomplete stay in quarantine with updated missing fields, tasks that fail for another reason
+// are reported in Failed and don't stop the rest. A task is marked repaired only after it's stored, and tasks that are
+// already in finished_tasks aren't inserted again, so a rerun after a partial failure doesn't duplicate them.
+func (s *Service) RepairQuarantinedTasks(ctx goctx.Context, id int) (RepairResult, error) {
+	quarantinedTasks, err := s.repository.GetQuarantinedTasks(ctx, id, pagination.Pagination{})
+	if err != nil {
+		return RepairResult{}, fmt.Errorf("get quarantined tasks: %w", err)
+	}
+
+	result := RepairResult{
+		Repaired:     []int{},
+		StillInvalid: []QuarantinedTask{},
+		Failed:       []RepairFailure{},
+	}
+	fail := func(quarantinedTask QuarantinedTask, err error) {
+		result.Failed = append(result.Failed, RepairFailure{ID: quarantinedTask.ID, Reason: err.Error()})
+	}
+
+	repaired := make([]QuarantinedTask, 0, len(quarantinedTasks))
+	finishedTasks := make([]FinishedTask, 0, len(quarantinedTasks))
+
+	for _, quarantinedTask := range quarantinedTasks {
+		var event task.Event
+		if err = json.Unmarshal(quarantinedTask.Payload, &event); err != nil {
+			fail(quarantinedTask, fmt.Errorf("unmarshal quarantined task: %w", err))
+			continue
+		}
+
+		finishedTask, enrichErr := s.enrichFinishedTask(ctx, event.Task)
+		if enrichErr != nil {
+			var validationErr *ValidationError
+			if !errors.As(enrichErr, &validationErr) {
+				fail(quarantinedTask, fmt.Errorf("enrich quarantined task: %w", enrichErr))
+				continue
+			}
+
+			quarantinedTask.MissingFields = validationErr.MissingFields
+			quarantinedTask.Reason = enrichErr.Error()
+
+			if err = s.repository.UpdateQuarantinedTask(ctx, quarantinedTask); err != nil {
+				fail(quarantinedTask, fmt.Errorf("update quarantined task: %w", err))
+				continue
+			}
+
+			result.StillInvalid = append(result.StillInvalid, quarantinedTask)
+			continue
+		}
+
+		repaired = append(repaired, quarantinedTask)
+		finishedTasks = append(finishedTasks, finishedTask)
+	}
+
+	if len(finishedTasks) == 0 {
+		return result, nil
+	}
+
+	stored, storeErr := s.addMissingFinishedTasks(ctx, finishedTasks)
+
+	now := time.Now()
+	for i, quarantinedTask := range repaired {
+		if !stored[i] {
+			fail(quarantinedTask, storeErr)
+			continue
+		}
+
+		quarantinedTask.RepairedAt = &now
+
+		if err = s.repository.UpdateQuarantinedTask(ctx, quarantinedTask); err != nil {
+			fail(quarantinedTask, fmt.Errorf("update quarantined task: %w", err))
+			continue
+		}
+
+		result.Repaired = append(result.Repaired, quarantinedTask.ID)
+	}
+
+	return result, nil
+}
+
+// addMissingFinishedTasks inserts the tasks that aren't in finished_tasks yet and reports for every task whether it's
+// stored, the error explains why the rest aren't.
+func (s *Service) addMissingFinishedTasks(ctx context.Context, tasks []FinishedTask) ([]bool, error) {
+	ids := make([]int, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.TaskID)
+	}
+
+	stored := make([]bool, len(tasks))
+
+	existingIDs, err := s.repository.GetExistingTaskIDs(ctx, ids)
+	if err != nil {
+		return stored, fmt.Errorf("get existing task ids: %w", err)
+	}
+
+	existing := make(map[int]bool, len(existingIDs))
+	for _, id := range existingIDs {
+		existing[id] = true
+	}
+
+	missing := make([]FinishedTask, 0, len(tasks))
+	for i, t := range tasks {
+		stored[i] = existing[t.TaskID]
+		if !existing[t.TaskID] {
+			existing[t.TaskID] = true
+			missing = append(missing, t)
+		}
+	}
+
+	if len(missing) == 0 {
+		return stored, nil
+	}
+
+	if err = s.repository.AddFinishedTasks(ctx, missing); err != nil {
+		return stored, fmt.Errorf("add finished tasks: %w", err)
+	}
+
+	for i := range stored {
+		stored[i] = true
+	}
+
+	return stored, nil
+}
@@ -0,0 +1,68 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+type taskStore struct {
+	Repository
+	stored map[int]bool
+	fail   bool
+	added  []int
+}
+
+func (r *taskStore) GetExistingTaskIDs(_ context.Context, ids []int) ([]int, error) {
+	var existing []int
+	for _, id := range ids {
+		if r.stored[id] {
+			existing = append(existing, id)
+		}
+	}
+
+	return existing, nil
+}
+
+func (r *taskStore) AddFinishedTasks(_ context.Context, tasks []FinishedTask) error {
+	if r.fail {
+		return errors.New("insert failed")
+	}
+
+	for _, t := range tasks {
+		r.stored[t.TaskID] = true
+		r.added = append(r.added, t.TaskID)
+	}
+
+	return nil
+}
+
+func TestAddMissingFinishedTasksSkipsStoredTasks(t *testing.T) {
+	repository := &taskStore{stored: map[int]bool{1: true}}
+	s := &Service{repository: repository}
+
+	stored, err := s.addMissingFinishedTasks(context.Background(), []FinishedTask{{TaskID: 1}, {TaskID: 2}, {TaskID: 2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(stored, []bool{true, true, true}) {
+		t.Fatalf("expected all tasks to be stored, got %v", stored)
+	}
+	if !slices.Equal(repository.added, []int{2}) {
+		t.Fatalf("expected only task 2 to be inserted once, got %v", repository.added)
+	}
+}
+
+func TestAddMissingFinishedTasksReportsFailedInsert(t *testing.T) {
+	repository := &taskStore{stored: map[int]bool{1: true}, fail: true}
+	s := &Service{repository: repository}
+
+	stored, err := s.addMissingFinishedTasks(context.Background(), []FinishedTask{{TaskID: 1}, {TaskID: 2}})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !slices.Equal(stored, []bool{true, false}) {
+		t.Fatalf("expected only the already stored task to be stored, got %v", stored)
+	}
+}
@@ -0,0 +1,241 @@
+package analytics
+
+import (
+	"analytics-service/service/auth"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/golog"
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+const (
+	maxQueuedReplays    = 8
+	replayUpdateTimeout = 15 * time.Second
+
+	// Replay tables are kept for comparison for replayTableTTL after the job finishes, expired ones are dropped
+	// every replayCleanupInterval.
+	replayTableTTL        = 7 * 24 * time.Hour
+	replayCleanupInterval = time.Hour
+)
+
+// replayRepository writes finished tasks of a replay into its own table and leaves the rest of the data as is.
+type replayRepository struct {
+	Repository
+	table string
+}
+
+func (r replayRepository) AddFinishedTasks(ctx context.Context, tasks []FinishedTask) error {
+	return r.AddFinishedTasksTo(ctx, r.table, tasks)
+}
+
+// AddDeadLetter does nothing, replayed events were dead-lettered when they were consumed.
+func (r replayRepository) AddDeadLetter(context.Context, DeadLetter) error {
+	return nil
+}
+
+// AddQuarantinedTask does nothing, replayed events were quarantined when they were consumed.
+func (r replayRepository) AddQuarantinedTask(context.Context, QuarantinedTask) error {
+	return nil
+}
+
+// replays runs queued replay jobs one at a time between StartReplays and StopReplays.
+type replays struct {
+	queue  chan ReplayJob
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Replay queues a job that re-runs archived messages received in [from, to) through the current ingestion pipeline.
+// Finished tasks are written into a new table, so the result can be compared with finished_tasks before it's used.
+// The table is dropped replayTableTTL after the job finishes. The job runs in the background, its status is returned
+// by GetReplayJobs.
+func (s *Service) Replay(ctx goctx.Context, from, to time.Time) (ReplayJob, error) {
+	if !from.Before(to) {
+		return ReplayJob{}, fmt.Errorf("from %s must be before to %s", from, to)
+	}
+
+	job, err := s.repository.AddReplayJob(ctx, ReplayJob{
+		PeriodStart: from,
+		PeriodEnd:   to,
+		Status:      ReplayStatusPending,
+		RequestedBy: auth.FromContext(ctx).UserID,
+	})
+	if err != nil {
+		return ReplayJob{}, fmt.Errorf("add replay job to db: %w", err)
+	}
+
+	select {
+	case s.replays.queue <- job:
+		return job, nil
+	default:
+	}
+
+	err = fmt.Errorf("more than %d replays are queued", maxQueuedReplays)
+
+	now := time.Now()
+	job.Status = ReplayStatusFailed
+	job.Error = err.Error()
+	job.FinishedAt = &now
+
+	if updateErr := s.repository.UpdateReplayJob(ctx, job); updateErr != nil {
+		err = errors.Join(err, fmt.Errorf("update replay job in db: %w", updateErr))
+	}
+
+	return ReplayJob{}, err
+}
+
+// GetReplayJobs returns the latest replay jobs first, id = 0 means any job.
+func (s *Service) GetReplayJobs(ctx goctx.Context, id int, page pagination.Pagination) ([]ReplayJob, error) {
+	if err := page.Validate(); err != nil {
+		return nil, fmt.Errorf("validate pagination: %w", err)
+	}
+
+	jobs, err := s.repository.GetReplayJobs(ctx, id, page)
+	if err != nil {
+		return nil, fmt.Errorf("get replay jobs from db: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// StartReplays runs queued replay jobs in the background until StopReplays is called.
+func (s *Service) StartReplays(ctx context.Context, log golog.Logger) {
+	ctx, s.replays.cancel = context.WithCancel(ctx)
+
+	s.replays.wg.Go(func() {
+		s.runReplays(ctx, log)
+	})
+}
+
+// StopReplays interrupts the running replay and waits for it, the interrupted and queued jobs are marked failed.
+func (s *Service) StopReplays() {
+	if s.replays.cancel == nil {
+		return
+	}
+
+	s.replays.cancel()
+	s.replays.wg.Wait()
+}
+
+func (s *Service) runReplays(ctx context.Context, log golog.Logger) {
+	ticker := time.NewTicker(replayCleanupInterval)
+	defer ticker.Stop()
+
+	s.dropExpiredReplays(ctx, log)
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case job := <-s.replays.queue:
+					s.finishReplay(ctx, log, job, fmt.Errorf("replay interrupted: %w", ctx.Err()))
+				default:
+					return
+				}
+			}
+		case job := <-s.replays.queue:
+			s.runReplay(ctx, log, job)
+		case <-ticker.C:
+			s.dropExpiredReplays(ctx, log)
+		}
+	}
+}
+
+// dropExpiredReplays drops the tables of jobs finished more than replayTableTTL ago and marks the jobs expired.
+func (s *Service) dropExpiredReplays(ctx context.Context, log golog.Logger) {
+	jobs, err := s.repository.GetExpiredReplayJobs(ctx, time.Now().Add(-replayTableTTL))
+	if err != nil {
+		log.Errorf("failed to get expired replay jobs: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if err = s.repository.DropReplayTable(ctx, job.Table); err != nil {
+			log.Errorf("failed to drop replay table %s: %v", job.Table, err)
+			continue
+		}
+
+		job.Status = ReplayStatusExpired
+		if err = s.repository.UpdateReplayJob(ctx, job); err != nil {
+			log.Errorf("failed to update replay job %d: %v", job.ID, err)
+		}
+	}
+}
+
+func (s *Service) runReplay(ctx context.Context, log golog.Logger, job ReplayJob) {
+	job.Table = fmt.Sprintf("finished_tasks_replay_%d", job.ID)
+	job.Status = ReplayStatusRunning
+
+	if err := s.repository.UpdateReplayJob(ctx, job); err != nil {
+		log.Errorf("failed to update replay job %d: %v", job.ID, err)
+	}
+
+	events, err := s.replay(ctx, log, job)
+	job.Events = events
+
+	s.finishReplay(ctx, log, job, err)
+}
+
+// replay runs the job through its own pipeline and returns the number of replayed events.
+func (s *Service) replay(ctx context.Context, log golog.Logger, job ReplayJob) (int, error) {
+	if err := s.repository.CreateReplayTable(ctx, job.Table); err != nil {
+		return 0, fmt.Errorf("create replay table: %w", err)
+	}
+
+	replayCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	p := newPipeline(s, replayRepository{Repository: s.repository, table: job.Table}, true)
+	handle := p.handler(replayCtx, log)
+
+	events := 0
+	err := s.repository.ForEachRawEvent(ctx, job.PeriodStart, job.PeriodEnd, func(e RawEvent) error {
+		handle(MapRawEventToMessage(e), func() {})
+		events++
+
+		return ctx.Err()
+	})
+
+	p.wait()
+
+	if err != nil {
+		return events, fmt.Errorf("read raw events: %w", err)
+	}
+
+	// Events interrupted by cancellation are dropped by the pipeline, so the table is incomplete.
+	if err = ctx.Err(); err != nil {
+		return events, fmt.Errorf("replay interrupted: %w", err)
+	}
+
+	if err = p.flush(ctx); err != nil {
+		return events, fmt.Errorf("flush replay batches: %w", err)
+	}
+
+	return events, nil
+}
+
+// finishReplay stores the outcome of the job, it's stored even if ctx is done.
+func (s *Service) finishReplay(ctx context.Context, log golog.Logger, job ReplayJob, err error) {
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = ReplayStatusDone
+
+	if err != nil {
+		job.Status = ReplayStatusFailed
+		job.Error = err.Error()
+		log.Errorf("replay job %d failed: %v", job.ID, err)
+	}
+
+	updateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), replayUpdateTimeout)
+	defer cancel()
+
+	if updateErr := s.repository.UpdateReplayJob(updateCtx, job); updateErr != nil {
+		log.Errorf("failed to update replay job %d: %v", job.ID, updateErr)
+	}
+}
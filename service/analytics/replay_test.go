@@ -0,0 +1,127 @@
+package analytics
+
+import (
+	"analytics-service/cluster/task"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sunshineOfficial/golib/golog"
+)
+
+type replayStore struct {
+	Repository
+	events  []RawEvent
+	expired []ReplayJob
+
+	mu      sync.Mutex
+	tables  []string
+	dropped []string
+	updates []ReplayJob
+}
+
+func (r *replayStore) CreateReplayTable(_ context.Context, table string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tables = append(r.tables, table)
+	return nil
+}
+
+func (r *replayStore) ForEachRawEvent(_ context.Context, _, _ time.Time, fn func(e RawEvent) error) error {
+	for _, e := range r.events {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *replayStore) AddRawEvents(context.Context, []RawEvent) error {
+	return nil
+}
+
+func (r *replayStore) AddFinishedTasksTo(context.Context, string, []FinishedTask) error {
+	return nil
+}
+
+func (r *replayStore) GetExpiredReplayJobs(context.Context, time.Time) ([]ReplayJob, error) {
+	return r.expired, nil
+}
+
+func (r *replayStore) DropReplayTable(_ context.Context, table string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dropped = append(r.dropped, table)
+	return nil
+}
+
+func (r *replayStore) UpdateReplayJob(_ context.Context, j ReplayJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.updates = append(r.updates, j)
+	return nil
+}
+
+func TestRunReplayStoresJobStatus(t *testing.T) {
+	brigadeID := 4
+	events := []task.Event{
+		{Type: task.EventTypeAdd, Task: task.Task{ID: 1, ObjectID: 2}},
+		{Type: task.EventTypeAssign, Task: task.Task{ID: 2, BrigadeID: &brigadeID}},
+		{Type: task.EventTypeStart, Task: task.Task{ID: 3}},
+	}
+
+	// The service has no clients, so the replay fails the test if it drops cached contracts or brigades.
+	repository := &replayStore{}
+	for i, event := range events {
+		event.Date = time.Now()
+		value, err := json.Marshal(event)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		repository.events = append(repository.events, RawEvent{Topic: "tasks", Offset: int64(i), Value: value})
+	}
+
+	lastProcessedAt := lastProcessedEventAt.Load()
+
+	s := &Service{repository: repository}
+	s.runReplay(context.Background(), golog.NewLogger("test"), ReplayJob{ID: 7, Status: ReplayStatusPending})
+
+	if len(repository.tables) != 1 || repository.tables[0] != "finished_tasks_replay_7" {
+		t.Fatalf("expected table finished_tasks_replay_7 to be created, got %v", repository.tables)
+	}
+	if len(repository.updates) != 2 {
+		t.Fatalf("expected the job to be updated when it starts and finishes, got %v", repository.updates)
+	}
+	if repository.updates[0].Status != ReplayStatusRunning {
+		t.Fatalf("expected the job to be running first, got %s", repository.updates[0].Status)
+	}
+
+	finished := repository.updates[1]
+	if finished.Status != ReplayStatusDone || finished.Events != 3 || finished.Error != "" || finished.FinishedAt == nil {
+		t.Fatalf("expected the job to be done with 3 events, got %+v", finished)
+	}
+	if lastProcessedEventAt.Load() != lastProcessedAt {
+		t.Fatal("expected replayed events not to move the last processed event")
+	}
+}
+
+func TestDropExpiredReplaysMarksJobsExpired(t *testing.T) {
+	repository := &replayStore{expired: []ReplayJob{{ID: 3, Table: "finished_tasks_replay_3", Status: ReplayStatusDone}}}
+
+	s := &Service{repository: repository}
+	s.dropExpiredReplays(context.Background(), golog.NewLogger("test"))
+
+	if len(repository.dropped) != 1 || repository.dropped[0] != "finished_tasks_replay_3" {
+		t.Fatalf("expected table finished_tasks_replay_3 to be dropped, got %v", repository.dropped)
+	}
+	if len(repository.updates) != 1 || repository.updates[0].Status != ReplayStatusExpired {
+		t.Fatalf("expected the job to be expired, got %+v", repository.updates)
+	}
+}
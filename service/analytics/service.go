@@ -1,25 +1,43 @@
 package analytics
 
 import (
+	"analytics-service/cluster/brigade"
 	"analytics-service/cluster/file"
 	"analytics-service/cluster/inspection"
+	"analytics-service/cluster/resilient"
+	"analytics-service/cluster/subscriber"
 	"analytics-service/cluster/task"
 	"analytics-service/config"
+	"analytics-service/service/address"
+	"analytics-service/service/auth"
+	"analytics-service/service/masking"
+	"analytics-service/service/tenant"
+	"analytics-service/tracing"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/segmentio/kafka-go"
 	"github.com/sunshineOfficial/golib/goctx"
-	"github.com/sunshineOfficial/golib/gokafka"
 	"github.com/sunshineOfficial/golib/golog"
 	"github.com/sunshineOfficial/golib/gotime"
 	"github.com/sunshineOfficial/golib/pagination"
 	"github.com/xuri/excelize/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 )
 
-const kafkaSubscribeTimeout = 2 * time.Minute
+const (
+	kafkaSubscribeTimeout    = 2 * time.Minute
+	eventRetryBackoff        = 5 * time.Second
+	defaultEnrichmentTimeout = 30 * time.Second
+)
+
+var tracer = otel.Tracer("analytics-service/service/analytics")
 
 type Service struct {
 	repository        Repository
@@ -27,32 +45,70 @@ type Service struct {
 	brigadeService    BrigadeService
 	subscriberService SubscriberService
 	fileService       FileService
+	masker            *masking.Masker
 	templates         config.Templates
+	ingestion         config.Ingestion
+	punctuality       config.Punctuality
+	recidivism        config.Recidivism
+	devices           config.Devices
+	addressParser     *address.Parser
+	tenantResolver    *tenant.Resolver
+	ingest            *pipeline
+	replays           replays
+}
+
+// Settings are the config sections used by the service.
+type Settings struct {
+	Templates   config.Templates
+	Ingestion   config.Ingestion
+	Punctuality config.Punctuality
+	Recidivism  config.Recidivism
+	Devices     config.Devices
+	Address     config.Address
+	Tenancy     config.Tenancy
 }
 
-func NewService(repository Repository, inspectionService InspectionService, brigadeService BrigadeService,
-	subscriberService SubscriberService, fileService FileService, templates config.Templates) *Service {
+func NewService(repository Repository, clients Clients, masker *masking.Masker, settings Settings) *Service {
+	if settings.Ingestion.EnrichmentTimeout <= 0 {
+		settings.Ingestion.EnrichmentTimeout = gotime.Duration(defaultEnrichmentTimeout)
+	}
+
 	return &Service{
 		repository:        repository,
-		inspectionService: inspectionService,
-		brigadeService:    brigadeService,
-		subscriberService: subscriberService,
-		fileService:       fileService,
-		templates:         templates,
+		inspectionService: clients.Inspection,
+		brigadeService:    clients.Brigade,
+		subscriberService: clients.Subscriber,
+		fileService:       clients.File,
+		masker:            masker,
+		templates:         settings.Templates,
+		ingestion:         settings.Ingestion,
+		punctuality:       settings.Punctuality,
+		recidivism:        settings.Recidivism,
+		devices:           settings.Devices,
+		addressParser:     address.NewParser(settings.Address),
+		tenantResolver:    tenant.NewResolver(settings.Tenancy),
+		replays:           replays{queue: make(chan ReplayJob, maxQueuedReplays)},
 	}
 }
 
-func (s *Service) CreateBasicReport(ctx goctx.Context, log golog.Logger, periodStart, periodEnd time.Time) (Report, error) {
-	periodStart = time.Date(periodStart.Year(), periodStart.Month(), periodStart.Day(), 0, 0, 0, 0, gotime.Moscow)
-	periodEnd = time.Date(periodEnd.Year(), periodEnd.Month(), periodEnd.Day(), 0, 0, 0, 0, gotime.Moscow)
+func (s *Service) CreateBasicReport(ctx goctx.Context, log golog.Logger, periodStart, periodEnd time.Time) (_ Report, err error) {
+	spanCtx, span := tracer.Start(ctx, "create basic report")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	ctx = goctx.Wrap(spanCtx)
+
+	periodStart, periodEnd, err = reportPeriod(periodStart, periodEnd)
+	if err != nil {
+		return Report{}, err
+	}
 
 	fmt.Printf("period start: %v, end: %v\n", periodStart, periodEnd)
 
-	if days := gotime.Days(periodEnd, periodStart); days < 1 {
-		return Report{}, fmt.Errorf("period days must be positive, got: %f", days)
-	}
+	scope := auth.FromContext(ctx).TenantScope()
 
-	tasks, err := s.repository.GetFinishedTasksByPeriod(ctx, periodStart, periodEnd)
+	tasks, err := s.repository.GetFinishedTasksByPeriod(ctx, scope, periodStart, periodEnd)
 	if err != nil {
 		return Report{}, fmt.Errorf("get finished tasks: %w", err)
 	}
@@ -60,7 +116,12 @@ func (s *Service) CreateBasicReport(ctx goctx.Context, log golog.Logger, periodS
 		return Report{}, fmt.Errorf("no finished tasks found from %s to %s", periodStart, periodEnd)
 	}
 
+	maskingPolicy := s.masker.Policy(ReportTypeBasic.Name(), auth.FromContext(ctx).Role)
+	tasks = MaskFinishedTaskSlice(s.masker, maskingPolicy, tasks)
+
+	_, openSpan := tracer.Start(ctx, "excelize open template")
 	f, err := excelize.OpenFile(s.templates.BasicReport)
+	tracing.End(openSpan, err)
 	if err != nil {
 		return Report{}, fmt.Errorf("open template file: %w", err)
 	}
@@ -71,63 +132,24 @@ func (s *Service) CreateBasicReport(ctx goctx.Context, log golog.Logger, periodS
 		}
 	}()
 
+	_, fillSpan := tracer.Start(ctx, "excelize fill rows", trace.WithAttributes(attribute.Int("rows", len(tasks))))
 	sheet := f.GetSheetName(0)
 	for i, t := range tasks {
 		cell, cellErr := excelize.CoordinatesToCellName(1, i+2)
 		if cellErr != nil {
+			tracing.End(fillSpan, cellErr)
 			return Report{}, fmt.Errorf("coordinates to cell name: %w", cellErr)
 		}
 
-		workType := ""
-		workResult := ""
-		switch t.Inspection.Type {
-		case inspection.TypeResumption:
-			workType = "Возобновление"
-
-			if t.Inspection.Resolution == inspection.ResolutionResumed {
-				workResult = "Возобновление"
-			} else {
-				workResult = "Недопуск"
-			}
-
-		case inspection.TypeLimitation:
-			workType = "Отключение"
-
-			if t.Inspection.Resolution != inspection.ResolutionLimited {
-				workResult = "Отключение"
-			} else {
-				workResult = "Недопуск"
-			}
-
-		default:
-			workType = "Контроль ранее введенного ограничения"
-
-			if t.Inspection.IsViolationDetected {
-				workResult = "Нарушено"
-			} else {
-				workResult = "Не нарушено"
-			}
-		}
-
-		inspectors := make([]string, 0, len(t.Brigade.Inspectors))
-		for _, inspector := range t.Brigade.Inspectors {
-			inspectors = append(inspectors, fullFIO(inspector.Surname, inspector.Name, inspector.Patronymic))
-		}
-
-		err = f.SetSheetRow(sheet, cell, &[]any{
-			i + 1,
-			t.Object.Address,
-			fullFIO(t.Subscriber.Surname, t.Subscriber.Name, t.Subscriber.Patronymic),
-			t.Subscriber.AccountNumber,
-			t.StartedAt.In(gotime.Moscow).Format(gotime.DateTimeNet),
-			t.FinishedAt.In(gotime.Moscow).Format(gotime.DateTimeNet),
-			workType,
-			workResult,
-			strings.Join(inspectors, ", "),
-		})
+		row := basicReportRow(i+1, t)
+		err = f.SetSheetRow(sheet, cell, &row)
 	}
 
+	fillSpan.End()
+
+	_, writeSpan := tracer.Start(ctx, "excelize write buffer")
 	buf, err := f.WriteToBuffer()
+	tracing.End(writeSpan, err)
 	if err != nil {
 		return Report{}, fmt.Errorf("write file to buffer: %w", err)
 	}
@@ -140,10 +162,12 @@ func (s *Service) CreateBasicReport(ctx goctx.Context, log golog.Logger, periodS
 	}
 
 	report := Report{
-		Type:        ReportTypeBasic,
-		Files:       []file.File{uploadedFile},
-		PeriodStart: periodStart,
-		PeriodEnd:   periodEnd,
+		Type:          ReportTypeBasic,
+		Files:         []file.File{uploadedFile},
+		PeriodStart:   periodStart,
+		PeriodEnd:     periodEnd,
+		MaskingPolicy: maskingPolicy,
+		Tenants:       scope.Tenants,
 	}
 
 	report, err = s.repository.AddReport(ctx, report)
@@ -154,22 +178,78 @@ func (s *Service) CreateBasicReport(ctx goctx.Context, log golog.Logger, periodS
 	return report, nil
 }
 
-func fullFIO(surname, name, patronymic string) string {
-	result := fmt.Sprintf("%s %s", surname, name)
+// basicReportRow is the n-th row of the basic report, the task is masked beforehand.
+func basicReportRow(n int, t FinishedTask) []any {
+	workType := ""
+	workResult := ""
+	switch t.Inspection.Type {
+	case inspection.TypeResumption:
+		workType = "Возобновление"
+
+		if t.Inspection.Resolution == inspection.ResolutionResumed {
+			workResult = "Возобновление"
+		} else {
+			workResult = "Недопуск"
+		}
+
+	case inspection.TypeLimitation:
+		workType = "Отключение"
+
+		if t.Inspection.Resolution != inspection.ResolutionLimited {
+			workResult = "Отключение"
+		} else {
+			workResult = "Недопуск"
+		}
+
+	default:
+		workType = "Контроль ранее введенного ограничения"
+
+		if t.Inspection.IsViolationDetected {
+			workResult = "Нарушено"
+		} else {
+			workResult = "Не нарушено"
+		}
+	}
 
-	if len(patronymic) > 0 {
-		result = fmt.Sprintf("%s %s", result, patronymic)
+	inspectors := make([]string, 0, len(t.Brigade.Inspectors))
+	for _, inspector := range t.Brigade.Inspectors {
+		inspectors = append(inspectors, fullFIO(inspector.Surname, inspector.Name, inspector.Patronymic))
 	}
 
-	return result
+	return []any{
+		n,
+		t.Object.Address,
+		fullFIO(t.Subscriber.Surname, t.Subscriber.Name, t.Subscriber.Patronymic),
+		t.Subscriber.AccountNumber,
+		t.StartedAt.In(gotime.Moscow).Format(gotime.DateTimeNet),
+		t.FinishedAt.In(gotime.Moscow).Format(gotime.DateTimeNet),
+		workType,
+		workResult,
+		strings.Join(inspectors, ", "),
+	}
 }
 
+func fullFIO(surname, name, patronymic string) string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{surname, name, patronymic} {
+		if len(part) > 0 {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// GetAllReports leaves file URLs out, they are issued one by one by GetReportFile, so every download is audited.
+// Reports masked less strictly than the caller's own reports of the type would be are left out.
 func (s *Service) GetAllReports(ctx goctx.Context, page pagination.Pagination) ([]Report, error) {
 	if err := page.Validate(); err != nil {
 		return nil, fmt.Errorf("validate pagination: %w", err)
 	}
 
-	reports, err := s.repository.GetAllReports(ctx, page)
+	caller := auth.FromContext(ctx)
+
+	reports, err := s.repository.GetAllReports(ctx, caller.TenantScope(), s.reportAccess(caller.Role), page)
 	if err != nil {
 		return nil, fmt.Errorf("get all reports from db: %w", err)
 	}
@@ -198,6 +278,7 @@ func (s *Service) GetAllReports(ctx goctx.Context, page pagination.Pagination) (
 				return nil, fmt.Errorf("report %d file %d not found", report.ID, reportFile.ID)
 			}
 
+			f.URL = ""
 			reports[i].Files[j] = f
 		}
 	}
@@ -205,44 +286,112 @@ func (s *Service) GetAllReports(ctx goctx.Context, page pagination.Pagination) (
 	return reports, nil
 }
 
-func (s *Service) SubscriberOnTaskEvent(mainCtx context.Context, log golog.Logger) gokafka.Subscriber {
-	return func(message gokafka.Message, err error) {
-		ctx, cancel := context.WithTimeout(mainCtx, kafkaSubscribeTimeout)
-		defer cancel()
+// GetReportFile returns a file of the report with its download URL, the report is checked like in GetAllReports.
+func (s *Service) GetReportFile(ctx goctx.Context, reportID, fileID int) (file.File, error) {
+	caller := auth.FromContext(ctx)
 
-		if err != nil {
-			log.Errorf("got error on task event: %v", err)
-			return
-		}
+	ok, err := s.repository.HasReportFile(ctx, caller.TenantScope(), s.reportAccess(caller.Role), reportID, fileID)
+	if err != nil {
+		return file.File{}, fmt.Errorf("check report file in db: %w", err)
+	}
+	if !ok {
+		return file.File{}, fmt.Errorf("report %d file %d not found", reportID, fileID)
+	}
 
-		var event task.Event
-		err = json.Unmarshal(message.Value, &event)
-		if err != nil {
-			log.Errorf("failed to unmarshal task event: %v", err)
-			return
-		}
+	files, err := s.fileService.GetFilesByIDs(ctx, []int{fileID})
+	if err != nil {
+		return file.File{}, fmt.Errorf("get files by ids: %w", err)
+	}
+	if len(files) == 0 {
+		return file.File{}, fmt.Errorf("file %d not found", fileID)
+	}
 
-		switch event.Type {
-		case task.EventTypeAdd:
-			err = s.handleAddedTask(ctx, event.Task)
-		case task.EventTypeStart:
-			err = s.handleStartedTask(ctx, event.Task)
-		case task.EventTypeFinish:
-			err = s.handleFinishedTask(ctx, event.Task)
-		case task.EventTypeAssign:
-			err = nil
-		default:
-			err = fmt.Errorf("unknown event type: %v", event.Type)
-		}
+	return files[0], nil
+}
 
-		if err != nil {
-			log.Errorf("failed to handle task event (type = %d): %v", event.Type, err)
-			return
-		}
+// SubscriberOnTaskEvent handles up to ingestion.Concurrency events at once. Events of the same task are handled
+// in the order they were consumed. Every message is archived as is, so history can be replayed later.
+// WaitTaskEvents and FlushBatches must be called after the consumer is stopped.
+func (s *Service) SubscriberOnTaskEvent(mainCtx context.Context, log golog.Logger) func(message kafka.Message, ack func()) {
+	s.ingest = newPipeline(s, s.repository, false)
+
+	return s.ingest.handler(mainCtx, log)
+}
+
+// WaitTaskEvents waits until all consumed task events are handled.
+func (s *Service) WaitTaskEvents() {
+	if s.ingest != nil {
+		s.ingest.wait()
+	}
+}
+
+// FlushBatches inserts raw events and finished tasks that are still waiting in batches.
+func (s *Service) FlushBatches(ctx context.Context) error {
+	if s.ingest == nil {
+		return nil
+	}
+
+	return s.ingest.flush(ctx)
+}
+
+type failureAction int
+
+const (
+	failureDeadLetter failureAction = iota
+	failureRetry
+	failureSkip
+	failureQuarantine
+)
+
+// classifyFailure decides what to do with an event that failed to be handled: missing upstream data won't appear
+// on retry, incomplete payloads wait for a repair, unavailable peers may recover, anything else is dead-lettered.
+func classifyFailure(err error) failureAction {
+	var validationErr *ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return failureQuarantine
+	case errors.Is(err, resilient.ErrNotFound):
+		return failureSkip
+	case errors.Is(err, resilient.ErrUnavailable):
+		return failureRetry
+	default:
+		return failureDeadLetter
 	}
 }
 
-func (s *Service) handleAddedTask(ctx context.Context, t task.Task) error {
+func wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// handleAddedTask drops the cached contract of the object, a new task usually follows a contract change.
+// A failed invalidation doesn't fail the event, the contract expires with the cache TTL anyway.
+func (s *Service) handleAddedTask(ctx context.Context, log golog.Logger, t task.Task) error {
+	if err := s.subscriberService.Invalidate(ctx, t.ObjectID); err != nil {
+		log.Errorf("failed to invalidate cached contract of object %d: %v", t.ObjectID, err)
+	}
+
+	return nil
+}
+
+// handleAssignedTask drops the cached brigade, its composition may have changed since it was cached.
+func (s *Service) handleAssignedTask(ctx context.Context, log golog.Logger, t task.Task) error {
+	if t.BrigadeID == nil {
+		return nil
+	}
+
+	if err := s.brigadeService.Invalidate(ctx, *t.BrigadeID); err != nil {
+		log.Errorf("failed to invalidate cached brigade %d: %v", *t.BrigadeID, err)
+	}
+
 	return nil
 }
 
@@ -250,34 +399,97 @@ func (s *Service) handleStartedTask(ctx context.Context, t task.Task) error {
 	return nil
 }
 
-func (s *Service) handleFinishedTask(ctx context.Context, t task.Task) error {
+func (s *Service) enrichFinishedTask(ctx context.Context, t task.Task) (FinishedTask, error) {
 	if t.Status != task.StatusDone {
-		return fmt.Errorf("invalid task status: %v", t.Status)
+		return FinishedTask{}, fmt.Errorf("invalid task status: %v", t.Status)
 	}
 
-	goCtx := goctx.Wrap(ctx)
-
-	ins, err := s.inspectionService.GetInspectionByTaskID(goCtx, t.ID)
-	if err != nil {
-		return fmt.Errorf("get inspection by task id: %w", err)
+	if err := ValidateFinishedTask(t); err != nil {
+		return FinishedTask{}, err
 	}
 
-	brig, err := s.brigadeService.GetBrigadeByID(goCtx, *t.BrigadeID)
-	if err != nil {
-		return fmt.Errorf("get brigade by id: %w", err)
+	enrichCtx, cancel := context.WithTimeout(ctx, time.Duration(s.ingestion.EnrichmentTimeout))
+	defer cancel()
+
+	// Lookups don't depend on each other, so they run concurrently and the first failure cancels the rest.
+	g, gCtx := errgroup.WithContext(enrichCtx)
+	goCtx := goctx.Wrap(gCtx)
+
+	var (
+		ins      inspection.Inspection
+		brig     brigade.Brigade
+		contract subscriber.Contract
+	)
+
+	g.Go(func() (err error) {
+		defer observeDuration(enrichmentDuration, upstreamInspection, time.Now())
+
+		if ins, err = s.inspectionService.GetInspectionByTaskID(goCtx, t.ID); err != nil {
+			return fmt.Errorf("get inspection by task id: %w", err)
+		}
+
+		return nil
+	})
+
+	g.Go(func() (err error) {
+		defer observeDuration(enrichmentDuration, upstreamBrigade, time.Now())
+
+		if brig, err = s.brigadeService.GetBrigadeByID(goCtx, *t.BrigadeID); err != nil {
+			return fmt.Errorf("get brigade by id: %w", err)
+		}
+
+		return nil
+	})
+
+	g.Go(func() (err error) {
+		defer observeDuration(enrichmentDuration, upstreamSubscriber, time.Now())
+
+		if contract, err = s.subscriberService.GetLastContractByObjectID(goCtx, t.ObjectID); err != nil {
+			return fmt.Errorf("get contract by object id: %w", err)
+		}
+
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return FinishedTask{}, err
 	}
 
-	contract, err := s.subscriberService.GetLastContractByObjectID(goCtx, t.ObjectID)
-	if err != nil {
-		return fmt.Errorf("get contract by object id: %w", err)
+	if err := ValidateInspection(ins); err != nil {
+		return FinishedTask{}, err
 	}
 
 	finishedTask := MapToFinishedTask(t, ins, brig, contract)
+	finishedTask.Object.AddressParts = s.addressParser.Parse(finishedTask.Object.Address)
+	finishedTask.Tenant = s.tenantResolver.Resolve(finishedTask.Brigade.ID, finishedTask.Object.AddressParts)
 
-	err = s.repository.AddFinishedTask(goCtx, finishedTask)
+	erased, err := s.repository.IsSubscriberErased(ctx, finishedTask.Subscriber.ID, finishedTask.Tenant)
 	if err != nil {
-		return fmt.Errorf("add finished task: %w", err)
+		return FinishedTask{}, fmt.Errorf("check subscriber erasure: %w", err)
+	}
+	if erased {
+		// Tasks finished or replayed after an erasure would bring the data back otherwise.
+		finishedTask = AnonymizeFinishedTask(finishedTask)
+	}
+
+	return finishedTask, nil
+}
+
+// InvalidateContract drops the cached contract of the object, so the next task of the object fetches it again.
+func (s *Service) InvalidateContract(ctx context.Context, objectID int) error {
+	if err := s.subscriberService.Invalidate(ctx, objectID); err != nil {
+		return fmt.Errorf("invalidate contract of object %d: %w", objectID, err)
 	}
 
 	return nil
 }
+
+// reportAccess allows the role reports of every type masked at least as strictly as its own reports of the type.
+func (s *Service) reportAccess(role auth.Role) ReportAccess {
+	access := make(ReportAccess, len(reportTypes))
+	for _, t := range reportTypes {
+		access[t] = masking.AtLeastAsStrict(s.masker.Policy(t.Name(), role))
+	}
+
+	return access
+}
@@ -0,0 +1,109 @@
+package analytics
+
+import (
+	"analytics-service/service/auth"
+	"analytics-service/service/masking"
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sunshineOfficial/golib/goctx"
+)
+
+// GetObjectTurnaround sums up the turnaround per object, addresses are masked according to the caller role.
+func (s *Service) GetObjectTurnaround(ctx goctx.Context, periodStart, periodEnd time.Time) ([]ObjectTurnaround, error) {
+	periodStart, periodEnd, err := reportPeriod(periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := auth.FromContext(ctx).TenantScope()
+
+	turnarounds, err := s.repository.GetLimitationTurnaroundsByPeriod(ctx, scope, periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("get limitation turnarounds: %w", err)
+	}
+
+	refusals, err := s.repository.GetObjectRefusalsByPeriod(ctx, scope, periodStart, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("get object refusals: %w", err)
+	}
+
+	policy := s.masker.Policy(masking.ScopeAPI, auth.FromContext(ctx).Role)
+
+	return MaskObjectTurnaroundSlice(s.masker, policy, AggregateObjectTurnaround(turnarounds, refusals)), nil
+}
+
+// GetOpenLimitations returns limitations that weren't resumed for more than olderThanDays days, the oldest first.
+// Addresses and account numbers are masked according to the caller role.
+func (s *Service) GetOpenLimitations(ctx goctx.Context, olderThanDays int) ([]LimitationTurnaround, error) {
+	if olderThanDays < 0 {
+		return nil, fmt.Errorf("olderThanDays must not be negative, got: %d", olderThanDays)
+	}
+
+	limitations, err := s.repository.GetOpenLimitations(ctx, auth.FromContext(ctx).TenantScope(), time.Now().AddDate(0, 0, -olderThanDays))
+	if err != nil {
+		return nil, fmt.Errorf("get open limitations: %w", err)
+	}
+
+	policy := s.masker.Policy(masking.ScopeAPI, auth.FromContext(ctx).Role)
+
+	return MaskLimitationTurnaroundSlice(s.masker, policy, limitations), nil
+}
+
+// AggregateObjectTurnaround sums up limitations and refusals of access per object.
+// Only resumed limitations count towards the time disconnected.
+func AggregateObjectTurnaround(turnarounds []LimitationTurnaround, refusals []ObjectRefusals) []ObjectTurnaround {
+	byID := make(map[int]*ObjectTurnaround)
+	totalMinutes := make(map[int]int)
+
+	object := func(id int, address string) *ObjectTurnaround {
+		o, ok := byID[id]
+		if !ok {
+			o = &ObjectTurnaround{ObjectID: id}
+			byID[id] = o
+		}
+
+		if address != "" {
+			o.ObjectAddress = address
+		}
+
+		return o
+	}
+
+	for _, t := range turnarounds {
+		o := object(t.ObjectID, t.ObjectAddress)
+		o.LimitationsCount++
+
+		if t.DisconnectedMinutes == nil {
+			o.OpenCount++
+			continue
+		}
+
+		o.ResumedCount++
+		totalMinutes[t.ObjectID] += *t.DisconnectedMinutes
+		o.MaxDisconnectedHours = max(o.MaxDisconnectedHours, round2(float64(*t.DisconnectedMinutes)/60))
+	}
+
+	for _, r := range refusals {
+		o := object(r.ObjectID, r.ObjectAddress)
+		o.LimitationRefusalsCount += r.LimitationRefusalsCount
+		o.ResumptionRefusalsCount += r.ResumptionRefusalsCount
+	}
+
+	result := make([]ObjectTurnaround, 0, len(byID))
+	for id, o := range byID {
+		if o.ResumedCount > 0 {
+			o.AvgDisconnectedHours = round2(float64(totalMinutes[id]) / float64(o.ResumedCount) / 60)
+		}
+
+		result = append(result, *o)
+	}
+
+	slices.SortFunc(result, func(a, b ObjectTurnaround) int {
+		return cmp.Compare(a.ObjectID, b.ObjectID)
+	})
+
+	return result
+}
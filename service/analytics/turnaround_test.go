@@ -0,0 +1,40 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregateObjectTurnaround(t *testing.T) {
+	limitedAt := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	resumed := func(minutes int) *int { return &minutes }
+
+	objects := AggregateObjectTurnaround(
+		[]LimitationTurnaround{
+			{ObjectID: 2, ObjectAddress: "ул. Ленина, 1", LimitationTaskID: 1, LimitedAt: limitedAt, DisconnectedMinutes: resumed(60)},
+			{ObjectID: 2, ObjectAddress: "ул. Ленина, 1", LimitationTaskID: 2, LimitedAt: limitedAt, DisconnectedMinutes: resumed(180)},
+			{ObjectID: 2, ObjectAddress: "ул. Ленина, 1", LimitationTaskID: 3, LimitedAt: limitedAt},
+		},
+		[]ObjectRefusals{
+			{ObjectID: 1, ObjectAddress: "ул. Мира, 5", LimitationRefusalsCount: 2},
+			{ObjectID: 2, ResumptionRefusalsCount: 1},
+		},
+	)
+
+	if len(objects) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(objects))
+	}
+
+	if o := objects[0]; o.ObjectID != 1 || o.ObjectAddress != "ул. Мира, 5" || o.LimitationsCount != 0 || o.LimitationRefusalsCount != 2 {
+		t.Fatalf("unexpected first object: %+v", o)
+	}
+
+	o := objects[1]
+	if o.LimitationsCount != 3 || o.ResumedCount != 2 || o.OpenCount != 1 || o.ResumptionRefusalsCount != 1 {
+		t.Fatalf("unexpected second object counts: %+v", o)
+	}
+
+	if o.AvgDisconnectedHours != 2 || o.MaxDisconnectedHours != 3 || o.ObjectAddress != "ул. Ленина, 1" {
+		t.Fatalf("unexpected second object durations: %+v", o)
+	}
+}
@@ -0,0 +1,60 @@
+package analytics
+
+import (
+	"analytics-service/cluster/inspection"
+	"analytics-service/cluster/task"
+	"strings"
+)
+
+// ValidationError lists required fields that are missing in upstream payloads. Events failing validation
+// are quarantined instead of being retried.
+type ValidationError struct {
+	MissingFields []string
+}
+
+func (e *ValidationError) Error() string {
+	return "missing required fields: " + strings.Join(e.MissingFields, ", ")
+}
+
+type requiredFields []string
+
+func (f *requiredFields) check(name string, present bool) {
+	if !present {
+		*f = append(*f, name)
+	}
+}
+
+func (f requiredFields) err() error {
+	if len(f) == 0 {
+		return nil
+	}
+
+	return &ValidationError{MissingFields: f}
+}
+
+// ValidateFinishedTask must pass before any lookups, the brigade is fetched by Task.BrigadeID.
+func ValidateFinishedTask(t task.Task) error {
+	var f requiredFields
+	f.check("Task.BrigadeID", t.BrigadeID != nil)
+	f.check("Task.StartedAt", t.StartedAt != nil)
+	f.check("Task.FinishedAt", t.FinishedAt != nil)
+
+	return f.err()
+}
+
+func ValidateInspection(ins inspection.Inspection) error {
+	var f requiredFields
+	f.check("Inspection.Type", ins.Type != nil)
+	f.check("Inspection.Resolution", ins.Resolution != nil)
+	f.check("Inspection.Method", ins.Method != nil)
+	f.check("Inspection.MethodBy", ins.MethodBy != nil)
+	f.check("Inspection.ReasonType", ins.ReasonType != nil)
+	f.check("Inspection.IsRestrictionChecked", ins.IsRestrictionChecked != nil)
+	f.check("Inspection.IsViolationDetected", ins.IsViolationDetected != nil)
+	f.check("Inspection.IsExpenseAvailable", ins.IsExpenseAvailable != nil)
+	f.check("Inspection.IsUnauthorizedConsumers", ins.IsUnauthorizedConsumers != nil)
+	f.check("Inspection.InspectAt", ins.InspectAt != nil)
+	f.check("Inspection.EnergyActionAt", ins.EnergyActionAt != nil)
+
+	return f.err()
+}
@@ -0,0 +1,50 @@
+package analytics
+
+import (
+	"analytics-service/cluster/brigade"
+	"analytics-service/cluster/inspection"
+	"analytics-service/cluster/subscriber"
+	"analytics-service/cluster/task"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestValidateInspectionReportsMissingFields(t *testing.T) {
+	inspectionType := inspection.TypeLimitation
+	now := time.Now()
+
+	err := ValidateInspection(inspection.Inspection{
+		Type:      &inspectionType,
+		InspectAt: &now,
+	})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	if slices.Contains(validationErr.MissingFields, "Inspection.Type") {
+		t.Fatalf("Inspection.Type is present but reported missing: %v", validationErr.MissingFields)
+	}
+
+	if !slices.Contains(validationErr.MissingFields, "Inspection.Resolution") {
+		t.Fatalf("Inspection.Resolution is missing but not reported: %v", validationErr.MissingFields)
+	}
+
+	if len(validationErr.MissingFields) != 9 {
+		t.Fatalf("expected 9 missing fields, got %v", validationErr.MissingFields)
+	}
+}
+
+func TestMapToFinishedTaskDoesNotPanicOnIncompletePayload(t *testing.T) {
+	if err := ValidateFinishedTask(task.Task{ID: 1}); err == nil {
+		t.Fatalf("expected validation error for incomplete task")
+	}
+
+	finishedTask := MapToFinishedTask(task.Task{ID: 1}, inspection.Inspection{}, brigade.Brigade{}, subscriber.Contract{})
+	if finishedTask.TaskID != 1 {
+		t.Fatalf("expected task id 1, got %d", finishedTask.TaskID)
+	}
+}
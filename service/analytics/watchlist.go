@@ -0,0 +1,369 @@
+package analytics
+
+import (
+	"analytics-service/cluster/file"
+	"analytics-service/cluster/inspection"
+	"analytics-service/config"
+	"analytics-service/service/auth"
+	"analytics-service/service/masking"
+	"analytics-service/tracing"
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/golog"
+	"github.com/sunshineOfficial/golib/gotime"
+	"github.com/xuri/excelize/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	watchListSheet = "Список наблюдения"
+	hitsSheet      = "Срабатывания правил"
+)
+
+// Weight is the contribution of a hit of the rule to the watch-list score.
+func (r WatchListRule) Weight() int {
+	switch r {
+	case WatchListRuleUnauthorizedAfterLimitation:
+		return 5
+	case WatchListRuleRepeatedViolations:
+		return 3
+	case WatchListRuleFrequentVisits:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func (r WatchListRule) Title() string {
+	switch r {
+	case WatchListRuleUnauthorizedAfterLimitation:
+		return "Несанкционированное подключение после ограничения"
+	case WatchListRuleRepeatedViolations:
+		return "Повторные нарушения"
+	case WatchListRuleFrequentVisits:
+		return "Частые выезды за квартал"
+	default:
+		return "Неизвестно"
+	}
+}
+
+func (s *Service) GetWatchList(ctx goctx.Context, periodStart, periodEnd time.Time) (WatchList, error) {
+	watchList, err := s.detectWatchList(ctx, periodStart, periodEnd)
+	if err != nil {
+		return WatchList{}, err
+	}
+
+	watchList.MaskingPolicy = s.masker.Policy(masking.ScopeAPI, auth.FromContext(ctx).Role)
+	watchList.Entries = s.maskWatchList(watchList.MaskingPolicy, watchList.Entries)
+
+	return watchList, nil
+}
+
+func (s *Service) detectWatchList(ctx goctx.Context, periodStart, periodEnd time.Time) (WatchList, error) {
+	periodStart, periodEnd, err := reportPeriod(periodStart, periodEnd)
+	if err != nil {
+		return WatchList{}, err
+	}
+
+	tasks, err := s.repository.GetFinishedTasksByPeriod(ctx, auth.FromContext(ctx).TenantScope(), periodStart, periodEnd)
+	if err != nil {
+		return WatchList{}, fmt.Errorf("get finished tasks: %w", err)
+	}
+
+	return WatchList{
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Entries:     DetectWatchList(tasks, s.recidivism),
+	}, nil
+}
+
+func (s *Service) maskWatchList(policy masking.Policy, entries []WatchListEntry) []WatchListEntry {
+	for i := range entries {
+		entries[i].Subscriber = MaskSubscriber(s.masker, policy, entries[i].Subscriber)
+		entries[i].Subscriber.AccountNumber = s.masker.AccountNumber(policy, entries[i].Subscriber.AccountNumber)
+		entries[i].ObjectAddress = s.masker.Address(policy, entries[i].ObjectAddress)
+	}
+
+	return entries
+}
+
+// DetectWatchList applies the recidivism rules to the tasks of every subscriber at every object:
+//   - at least MinUnauthorizedAfterLimitation unauthorized connections are found after a successful limitation;
+//   - at least MinViolations violations are detected;
+//   - the object is visited more than MaxVisitsPerQuarter times in a calendar quarter, one hit per quarter.
+//
+// Visits are counted per object whoever the subscriber was, their hits go to the entry of the object's last subscriber.
+// A rule with a zero threshold is disabled. Entries are ranked by score, then by the number of supporting tasks.
+func DetectWatchList(tasks []FinishedTask, settings config.Recidivism) []WatchListEntry {
+	byObject := make(map[int][]FinishedTask)
+	for _, t := range tasks {
+		byObject[t.Object.ID] = append(byObject[t.Object.ID], t)
+	}
+
+	entries := make([]WatchListEntry, 0)
+	for _, objectTasks := range byObject {
+		slices.SortFunc(objectTasks, func(a, b FinishedTask) int {
+			return cmp.Or(a.FinishedAt.Compare(b.FinishedAt), cmp.Compare(a.TaskID, b.TaskID))
+		})
+
+		var subscriberIDs []int
+		bySubscriber := make(map[int][]FinishedTask)
+		for _, t := range objectTasks {
+			if _, ok := bySubscriber[t.Subscriber.ID]; !ok {
+				subscriberIDs = append(subscriberIDs, t.Subscriber.ID)
+			}
+			bySubscriber[t.Subscriber.ID] = append(bySubscriber[t.Subscriber.ID], t)
+		}
+
+		lastSubscriberID := objectTasks[len(objectTasks)-1].Subscriber.ID
+		visitHits := detectFrequentVisits(objectTasks, settings)
+
+		for _, subscriberID := range subscriberIDs {
+			group := bySubscriber[subscriberID]
+
+			hits := detectWatchListHits(group, settings)
+			if subscriberID == lastSubscriberID {
+				hits = append(hits, visitHits...)
+			}
+			if len(hits) == 0 {
+				continue
+			}
+
+			last := group[len(group)-1]
+			entry := WatchListEntry{
+				ObjectID:      last.Object.ID,
+				ObjectAddress: last.Object.Address,
+				Subscriber:    last.Subscriber,
+				LastTaskAt:    last.FinishedAt,
+				Hits:          hits,
+			}
+			for _, h := range hits {
+				entry.Score += h.Rule.Weight()
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b WatchListEntry) int {
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			cmp.Compare(supportingTasks(b), supportingTasks(a)),
+			b.LastTaskAt.Compare(a.LastTaskAt),
+			cmp.Compare(a.ObjectID, b.ObjectID),
+			cmp.Compare(a.Subscriber.ID, b.Subscriber.ID),
+		)
+	})
+
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	return entries
+}
+
+// detectWatchListHits expects the tasks of one subscriber at one object sorted by FinishedAt.
+func detectWatchListHits(tasks []FinishedTask, settings config.Recidivism) []WatchListHit {
+	var (
+		hits               []WatchListHit
+		lastLimitationID   int
+		afterLimitationIDs []int
+		unauthorizedCount  int
+		violationIDs       []int
+	)
+
+	for _, t := range tasks {
+		if t.Inspection.Type == inspection.TypeLimitation && t.Inspection.Resolution == inspection.ResolutionLimited {
+			lastLimitationID = t.TaskID
+		}
+
+		unauthorized := t.Inspection.IsUnauthorizedConsumers ||
+			(t.Inspection.Type == inspection.TypeUnauthorizedConnection && t.Inspection.IsViolationDetected)
+		if unauthorized && lastLimitationID != 0 {
+			if !slices.Contains(afterLimitationIDs, lastLimitationID) {
+				afterLimitationIDs = append(afterLimitationIDs, lastLimitationID)
+			}
+			afterLimitationIDs = append(afterLimitationIDs, t.TaskID)
+			unauthorizedCount++
+		}
+
+		if t.Inspection.IsViolationDetected {
+			violationIDs = append(violationIDs, t.TaskID)
+		}
+	}
+
+	if settings.MinUnauthorizedAfterLimitation > 0 && unauthorizedCount >= settings.MinUnauthorizedAfterLimitation {
+		hits = append(hits, WatchListHit{Rule: WatchListRuleUnauthorizedAfterLimitation, TaskIDs: afterLimitationIDs})
+	}
+
+	if settings.MinViolations > 0 && len(violationIDs) >= settings.MinViolations {
+		hits = append(hits, WatchListHit{Rule: WatchListRuleRepeatedViolations, TaskIDs: violationIDs})
+	}
+
+	return hits
+}
+
+// detectFrequentVisits expects all tasks at one object sorted by FinishedAt.
+func detectFrequentVisits(tasks []FinishedTask, settings config.Recidivism) []WatchListHit {
+	if settings.MaxVisitsPerQuarter <= 0 {
+		return nil
+	}
+
+	var (
+		hits            []WatchListHit
+		quarters        []time.Time
+		visitsByQuarter = make(map[time.Time][]int)
+	)
+
+	for _, t := range tasks {
+		quarter := quarterStart(t.FinishedAt)
+		if _, ok := visitsByQuarter[quarter]; !ok {
+			quarters = append(quarters, quarter)
+		}
+		visitsByQuarter[quarter] = append(visitsByQuarter[quarter], t.TaskID)
+	}
+
+	for _, quarter := range quarters {
+		if visits := visitsByQuarter[quarter]; len(visits) > settings.MaxVisitsPerQuarter {
+			hits = append(hits, WatchListHit{Rule: WatchListRuleFrequentVisits, TaskIDs: visits})
+		}
+	}
+
+	return hits
+}
+
+func quarterStart(t time.Time) time.Time {
+	t = t.In(gotime.Moscow)
+	return time.Date(t.Year(), (t.Month()-1)/3*3+1, 1, 0, 0, 0, 0, gotime.Moscow)
+}
+
+func supportingTasks(e WatchListEntry) int {
+	ids := make(map[int]struct{})
+	for _, h := range e.Hits {
+		for _, id := range h.TaskIDs {
+			ids[id] = struct{}{}
+		}
+	}
+
+	return len(ids)
+}
+
+func (s *Service) CreateWatchListReport(ctx goctx.Context, log golog.Logger, periodStart, periodEnd time.Time) (_ Report, err error) {
+	spanCtx, span := tracer.Start(ctx, "create watch-list report")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	ctx = goctx.Wrap(spanCtx)
+
+	watchList, err := s.detectWatchList(ctx, periodStart, periodEnd)
+	if err != nil {
+		return Report{}, err
+	}
+
+	policy := s.masker.Policy(ReportTypeWatchList.Name(), auth.FromContext(ctx).Role)
+	entries := s.maskWatchList(policy, watchList.Entries)
+
+	_, renderSpan := tracer.Start(ctx, "excelize render watch-list report", trace.WithAttributes(attribute.Int("rows", len(entries))))
+	buf, err := renderWatchListReport(log, entries)
+	tracing.End(renderSpan, err)
+	if err != nil {
+		return Report{}, err
+	}
+
+	fileName := fmt.Sprintf("Список наблюдения за %s-%s.xlsx",
+		watchList.PeriodStart.Format(gotime.DateOnlyNet), watchList.PeriodEnd.Format(gotime.DateOnlyNet))
+
+	uploadedFile, err := s.fileService.Upload(ctx, fileName, buf)
+	if err != nil {
+		return Report{}, fmt.Errorf("upload file: %w", err)
+	}
+
+	report := Report{
+		Type:          ReportTypeWatchList,
+		Files:         []file.File{uploadedFile},
+		PeriodStart:   watchList.PeriodStart,
+		PeriodEnd:     watchList.PeriodEnd,
+		MaskingPolicy: policy,
+		Tenants:       auth.FromContext(ctx).TenantScope().Tenants,
+	}
+
+	report, err = s.repository.AddReport(ctx, report)
+	if err != nil {
+		return Report{}, fmt.Errorf("add report: %w", err)
+	}
+
+	return report, nil
+}
+
+// renderWatchListReport writes an empty list too, so the security department gets a report every week.
+func renderWatchListReport(log golog.Logger, entries []WatchListEntry) (*bytes.Buffer, error) {
+	f := excelize.NewFile()
+	defer func() {
+		if fErr := f.Close(); fErr != nil {
+			log.Errorf("close watch-list report file: %v", fErr)
+		}
+	}()
+
+	if err := f.SetSheetName(f.GetSheetName(0), watchListSheet); err != nil {
+		return nil, fmt.Errorf("set sheet name: %w", err)
+	}
+
+	if _, err := f.NewSheet(hitsSheet); err != nil {
+		return nil, fmt.Errorf("new sheet: %w", err)
+	}
+
+	rows := [][]any{{"Место", "Баллы", "Объект", "Адрес", "Лицевой счет", "Абонент", "Телефон", "Последний выезд", "Правила"}}
+	hitRows := [][]any{{"Место", "Объект", "Лицевой счет", "Правило", "Задачи"}}
+
+	for _, e := range entries {
+		rules := make([]string, 0, len(e.Hits))
+		for _, h := range e.Hits {
+			if title := h.Rule.Title(); !slices.Contains(rules, title) {
+				rules = append(rules, title)
+			}
+
+			taskIDs := make([]string, 0, len(h.TaskIDs))
+			for _, id := range h.TaskIDs {
+				taskIDs = append(taskIDs, strconv.Itoa(id))
+			}
+
+			hitRows = append(hitRows, []any{e.Rank, e.ObjectID, e.Subscriber.AccountNumber, h.Rule.Title(), strings.Join(taskIDs, ", ")})
+		}
+
+		rows = append(rows, []any{
+			e.Rank,
+			e.Score,
+			e.ObjectID,
+			e.ObjectAddress,
+			e.Subscriber.AccountNumber,
+			strings.TrimSpace(strings.Join([]string{e.Subscriber.Surname, e.Subscriber.Name, e.Subscriber.Patronymic}, " ")),
+			e.Subscriber.PhoneNumber,
+			e.LastTaskAt.In(gotime.Moscow).Format(gotime.DateOnlyNet),
+			strings.Join(rules, "; "),
+		})
+	}
+
+	if err := setSheetRows(f, watchListSheet, rows); err != nil {
+		return nil, err
+	}
+
+	if err := setSheetRows(f, hitsSheet, hitRows); err != nil {
+		return nil, err
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("write file to buffer: %w", err)
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,118 @@
+package analytics
+
+import (
+	"analytics-service/cluster/inspection"
+	"analytics-service/config"
+	"analytics-service/service/masking"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDetectWatchList(t *testing.T) {
+	day := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	task := func(id, objectID, subscriberID, days int, i Inspection) FinishedTask {
+		return FinishedTask{
+			TaskID:     id,
+			FinishedAt: day.AddDate(0, 0, days),
+			Inspection: i,
+			Object:     Object{ID: objectID},
+			Subscriber: Subscriber{ID: subscriberID},
+		}
+	}
+
+	limited := Inspection{Type: inspection.TypeLimitation, Resolution: inspection.ResolutionLimited}
+	unauthorized := Inspection{Type: inspection.TypeUnauthorizedConnection, IsViolationDetected: true}
+	verification := Inspection{Type: inspection.TypeVerification}
+
+	entries := DetectWatchList([]FinishedTask{
+		task(4, 1, 10, 30, unauthorized),
+		task(1, 1, 10, 0, unauthorized),
+		task(2, 1, 10, 10, limited),
+		task(5, 2, 20, 1, verification),
+		task(6, 2, 20, 2, verification),
+		task(7, 2, 20, 3, verification),
+		task(8, 2, 20, 100, verification),
+		task(9, 3, 30, 0, verification),
+	}, config.Recidivism{MaxVisitsPerQuarter: 2, MinViolations: 2, MinUnauthorizedAfterLimitation: 1})
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+
+	first := entries[0]
+	if first.Rank != 1 || first.ObjectID != 1 || first.Score != 10 || len(first.Hits) != 3 {
+		t.Fatalf("unexpected first entry: %+v", first)
+	}
+
+	if hit := first.Hits[0]; hit.Rule != WatchListRuleUnauthorizedAfterLimitation || !slices.Equal(hit.TaskIDs, []int{2, 4}) {
+		t.Fatalf("unexpected unauthorized hit: %+v", hit)
+	}
+
+	if hit := first.Hits[1]; hit.Rule != WatchListRuleRepeatedViolations || !slices.Equal(hit.TaskIDs, []int{1, 4}) {
+		t.Fatalf("unexpected violations hit: %+v", hit)
+	}
+
+	if hit := first.Hits[2]; hit.Rule != WatchListRuleFrequentVisits || !slices.Equal(hit.TaskIDs, []int{1, 2, 4}) {
+		t.Fatalf("unexpected visits hit: %+v", hit)
+	}
+
+	second := entries[1]
+	if second.Rank != 2 || second.ObjectID != 2 || len(second.Hits) != 1 || !slices.Equal(second.Hits[0].TaskIDs, []int{5, 6, 7}) {
+		t.Fatalf("unexpected second entry: %+v", second)
+	}
+}
+
+func TestDetectWatchListCountsVisitsPerObject(t *testing.T) {
+	day := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	task := func(id, subscriberID, days int, i Inspection) FinishedTask {
+		return FinishedTask{
+			TaskID:     id,
+			FinishedAt: day.AddDate(0, 0, days),
+			Inspection: i,
+			Object:     Object{ID: 1},
+			Subscriber: Subscriber{ID: subscriberID},
+		}
+	}
+
+	limited := Inspection{Type: inspection.TypeLimitation, Resolution: inspection.ResolutionLimited}
+	unauthorized := Inspection{Type: inspection.TypeUnauthorizedConnection, IsViolationDetected: true}
+
+	// The contract moved from subscriber 10 to subscriber 20, the object is still visited 4 times in the quarter.
+	entries := DetectWatchList([]FinishedTask{
+		task(1, 10, 0, limited),
+		task(2, 10, 5, unauthorized),
+		task(3, 20, 10, limited),
+		task(4, 20, 15, limited),
+	}, config.Recidivism{MaxVisitsPerQuarter: 3, MinUnauthorizedAfterLimitation: 2})
+
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %+v", entries)
+	}
+
+	entry := entries[0]
+	if entry.Subscriber.ID != 20 || len(entry.Hits) != 1 {
+		t.Fatalf("expected only the frequent visits hit for the last subscriber, got %+v", entry)
+	}
+
+	if hit := entry.Hits[0]; hit.Rule != WatchListRuleFrequentVisits || !slices.Equal(hit.TaskIDs, []int{1, 2, 3, 4}) {
+		t.Fatalf("unexpected visits hit: %+v", hit)
+	}
+}
+
+func TestMaskWatchListMasksAddressAndAccountNumber(t *testing.T) {
+	m, err := masking.NewMasker(config.Masking{DefaultPolicy: "partial", HashSalt: "salt"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s := &Service{masker: m}
+	entries := s.maskWatchList(masking.PolicyPartial, []WatchListEntry{{
+		ObjectAddress: "г. Екатеринбург, ул. Ленина, д. 5",
+		Subscriber:    Subscriber{AccountNumber: "6600123456"},
+	}})
+
+	if e := entries[0]; e.ObjectAddress != "г. Екатеринбург, ул. Ленина" || e.Subscriber.AccountNumber != "******3456" {
+		t.Fatalf("unexpected masked entry: %+v", e)
+	}
+}
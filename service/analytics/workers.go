@@ -0,0 +1,47 @@
+package analytics
+
+import "sync"
+
+// keyedWorkers runs jobs concurrently, jobs with the same key always go to the same worker and keep their order.
+type keyedWorkers struct {
+	queues []chan func()
+	wg     sync.WaitGroup
+}
+
+func newKeyedWorkers(workers, queueSize int) *keyedWorkers {
+	w := &keyedWorkers{
+		queues: make([]chan func(), max(workers, 1)),
+	}
+
+	for i := range w.queues {
+		queue := make(chan func(), queueSize)
+		w.queues[i] = queue
+
+		w.wg.Go(func() {
+			for job := range queue {
+				job()
+			}
+		})
+	}
+
+	return w
+}
+
+// Submit blocks while the queue of the key's worker is full, which slows down the consumer.
+func (w *keyedWorkers) Submit(key int, job func()) {
+	index := key % len(w.queues)
+	if index < 0 {
+		index += len(w.queues)
+	}
+
+	w.queues[index] <- job
+}
+
+// Close waits for all submitted jobs. Submit must not be called after Close.
+func (w *keyedWorkers) Close() {
+	for _, queue := range w.queues {
+		close(queue)
+	}
+
+	w.wg.Wait()
+}
@@ -0,0 +1,70 @@
+package analytics
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyedWorkersKeepOrderOfKey(t *testing.T) {
+	w := newKeyedWorkers(4, 2)
+
+	var mu sync.Mutex
+	got := make(map[int][]int)
+	for i := range 100 {
+		key := i % 3
+		w.Submit(key, func() {
+			mu.Lock()
+			defer mu.Unlock()
+
+			got[key] = append(got[key], i)
+		})
+	}
+	w.Close()
+
+	for key, jobs := range got {
+		if !slices.IsSorted(jobs) {
+			t.Fatalf("expected jobs of key %d in submission order, got %v", key, jobs)
+		}
+	}
+	if n := len(got[0]) + len(got[1]) + len(got[2]); n != 100 {
+		t.Fatalf("expected 100 jobs to run, got %d", n)
+	}
+}
+
+func TestKeyedWorkersRunKeysConcurrently(t *testing.T) {
+	w := newKeyedWorkers(2, 1)
+	defer w.Close()
+
+	blocked := make(chan struct{})
+	w.Submit(0, func() {
+		<-blocked
+	})
+
+	done := make(chan struct{})
+	w.Submit(1, func() {
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the job of another worker to run while the first one is blocked")
+	}
+	close(blocked)
+}
+
+func TestKeyedWorkersAcceptNegativeKeys(t *testing.T) {
+	w := newKeyedWorkers(3, 1)
+
+	ran := make(chan struct{}, 1)
+	w.Submit(-7, func() {
+		ran <- struct{}{}
+	})
+	w.Close()
+
+	if len(ran) != 1 {
+		t.Fatal("expected the job of a negative key to run")
+	}
+}
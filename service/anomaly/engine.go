@@ -0,0 +1,262 @@
+package anomaly
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+type seriesKey struct {
+	subscriberID int
+	objectID     int
+}
+
+// Evaluate applies the enabled rules to the monthly consumption of every subscriber at every object
+// and returns anomalies of the months starting from from. Earlier months are only used as history.
+func Evaluate(rules []Rule, consumption []MonthlyConsumption, from time.Time) []Anomaly {
+	var (
+		keys      []seriesKey
+		series    = make(map[seriesKey][]MonthlyConsumption)
+		lastMonth time.Time
+	)
+
+	for _, c := range consumption {
+		key := seriesKey{subscriberID: c.SubscriberID, objectID: c.ObjectID}
+		if _, ok := series[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		series[key] = append(series[key], c)
+
+		if c.Month.After(lastMonth) {
+			lastMonth = c.Month
+		}
+	}
+
+	var anomalies []Anomaly
+	for _, key := range keys {
+		points := series[key]
+		slices.SortFunc(points, func(a, b MonthlyConsumption) int {
+			return a.Month.Compare(b.Month)
+		})
+
+		for _, rule := range rules {
+			if !rule.Enabled {
+				continue
+			}
+
+			for _, a := range evaluateRule(rule, points, lastMonth) {
+				if !a.Month.Before(from) {
+					anomalies = append(anomalies, a)
+				}
+			}
+		}
+	}
+
+	return anomalies
+}
+
+// assignTenants gives anomalies stored before tenancy the tenant of the latest month of their series, the tenant of
+// finished tasks is backfilled in ClickHouse. Anomalies of series without a tenant yet are left out.
+func assignTenants(anomalies []Anomaly, consumption []MonthlyConsumption) []Anomaly {
+	latest := make(map[seriesKey]MonthlyConsumption)
+	for _, c := range consumption {
+		if c.Tenant == "" {
+			continue
+		}
+
+		key := seriesKey{subscriberID: c.SubscriberID, objectID: c.ObjectID}
+		if l, ok := latest[key]; !ok || c.Month.After(l.Month) {
+			latest[key] = c
+		}
+	}
+
+	var tenanted []Anomaly
+	for _, a := range anomalies {
+		if c, ok := latest[seriesKey{subscriberID: a.SubscriberID, objectID: a.ObjectID}]; ok {
+			a.Tenant = c.Tenant
+			tenanted = append(tenanted, a)
+		}
+	}
+
+	return tenanted
+}
+
+func evaluateRule(rule Rule, points []MonthlyConsumption, lastMonth time.Time) []Anomaly {
+	switch rule.Kind {
+	case RuleKindZScore:
+		return evaluateZScore(rule, points)
+	case RuleKindIQR:
+		return evaluateIQR(rule, points)
+	case RuleKindSeasonal:
+		return evaluateSeasonal(rule, points)
+	case RuleKindZeroStreak:
+		return evaluateZeroStreak(rule, points, lastMonth)
+	default:
+		return nil
+	}
+}
+
+func evaluateZScore(rule Rule, points []MonthlyConsumption) []Anomaly {
+	var anomalies []Anomaly
+	for i := max(rule.Params.MinHistory, 2); i < len(points); i++ {
+		mean, std := meanStd(values(points[:i]))
+		if std == 0 {
+			continue
+		}
+
+		if z := (points[i].ConsumptionKWh - mean) / std; math.Abs(z) >= rule.Params.Threshold {
+			anomalies = append(anomalies, newAnomaly(rule, points[i], points[i].ConsumptionKWh, &mean, math.Abs(z)))
+		}
+	}
+
+	return anomalies
+}
+
+func evaluateIQR(rule Rule, points []MonthlyConsumption) []Anomaly {
+	var anomalies []Anomaly
+	for i := max(rule.Params.MinHistory, 4); i < len(points); i++ {
+		history := values(points[:i])
+		slices.Sort(history)
+
+		q1, median, q3 := percentile(history, 0.25), percentile(history, 0.5), percentile(history, 0.75)
+		iqr := q3 - q1
+		if iqr == 0 {
+			continue
+		}
+
+		v := points[i].ConsumptionKWh
+		lower, upper := q1-rule.Params.K*iqr, q3+rule.Params.K*iqr
+
+		var distance float64
+		switch {
+		case v < lower:
+			distance = lower - v
+		case v > upper:
+			distance = v - upper
+		default:
+			continue
+		}
+
+		anomalies = append(anomalies, newAnomaly(rule, points[i], v, &median, distance/iqr))
+	}
+
+	return anomalies
+}
+
+func evaluateSeasonal(rule Rule, points []MonthlyConsumption) []Anomaly {
+	byMonth := make(map[time.Time]float64, len(points))
+	for _, p := range points {
+		byMonth[p.Month] = p.ConsumptionKWh
+	}
+
+	var anomalies []Anomaly
+	for _, p := range points {
+		lastYear, ok := byMonth[p.Month.AddDate(-1, 0, 0)]
+		if !ok || lastYear <= 0 {
+			continue
+		}
+
+		if ratio := (p.ConsumptionKWh - lastYear) / lastYear; math.Abs(ratio) >= rule.Params.Threshold {
+			anomalies = append(anomalies, newAnomaly(rule, p, p.ConsumptionKWh, &lastYear, math.Abs(ratio)))
+		}
+	}
+
+	return anomalies
+}
+
+// evaluateZeroStreak looks for gaps between months with consumption and after the last of them up to lastMonth,
+// the latest month in the data. The anomaly is recorded at the last month of the streak, Score is its length.
+func evaluateZeroStreak(rule Rule, points []MonthlyConsumption, lastMonth time.Time) []Anomaly {
+	minMonths := max(rule.Params.MinMonths, 1)
+
+	var anomalies []Anomaly
+	for i, p := range points {
+		next := lastMonth.AddDate(0, 1, 0)
+		if i+1 < len(points) {
+			next = points[i+1].Month
+		}
+
+		streak := monthsBetween(p.Month, next) - 1
+		if streak < minMonths {
+			continue
+		}
+
+		mean, _ := meanStd(values(points[:i+1]))
+		zero := p
+		zero.Month = next.AddDate(0, -1, 0)
+
+		anomalies = append(anomalies, newAnomaly(rule, zero, 0, &mean, float64(streak)))
+	}
+
+	return anomalies
+}
+
+func newAnomaly(rule Rule, p MonthlyConsumption, consumption float64, expected *float64, score float64) Anomaly {
+	if expected != nil {
+		rounded := round2(*expected)
+		expected = &rounded
+	}
+
+	return Anomaly{
+		RuleID:                  rule.ID,
+		RuleName:                rule.Name,
+		RuleKind:                rule.Kind,
+		Month:                   p.Month,
+		SubscriberID:            p.SubscriberID,
+		SubscriberAccountNumber: p.SubscriberAccountNumber,
+		ObjectID:                p.ObjectID,
+		ObjectAddress:           p.ObjectAddress,
+		DistrictName:            p.DistrictName,
+		Tenant:                  p.Tenant,
+		ConsumptionKWh:          round2(consumption),
+		ExpectedKWh:             expected,
+		Score:                   round2(score),
+		Status:                  StatusNew,
+	}
+}
+
+func values(points []MonthlyConsumption) []float64 {
+	result := make([]float64, 0, len(points))
+	for _, p := range points {
+		result = append(result, p.ConsumptionKWh)
+	}
+
+	return result
+}
+
+func meanStd(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
+
+// percentile interpolates linearly between the closest ranks of the sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+func monthsBetween(a, b time.Time) int {
+	return (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
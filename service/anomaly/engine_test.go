@@ -0,0 +1,89 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluate(t *testing.T) {
+	month := func(year int, m time.Month) time.Time {
+		return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	var consumption []MonthlyConsumption
+	for i, kwh := range []float64{100, 110, 90, 105, 95, 100, 400} {
+		consumption = append(consumption, MonthlyConsumption{Month: month(2024, time.Month(i+1)), SubscriberID: 1, ObjectID: 10, ConsumptionKWh: kwh})
+	}
+	consumption = append(consumption,
+		MonthlyConsumption{Month: month(2024, time.January), SubscriberID: 2, ObjectID: 20, ConsumptionKWh: 50},
+		MonthlyConsumption{Month: month(2025, time.January), SubscriberID: 2, ObjectID: 20, ConsumptionKWh: 20},
+	)
+
+	rules := []Rule{
+		{ID: 1, Kind: RuleKindZScore, Enabled: true, Params: RuleParams{Threshold: 3, MinHistory: 6}},
+		{ID: 2, Kind: RuleKindIQR, Enabled: true, Params: RuleParams{K: 1.5, MinHistory: 6}},
+		{ID: 3, Kind: RuleKindSeasonal, Enabled: true, Params: RuleParams{Threshold: 0.5}},
+		{ID: 4, Kind: RuleKindZeroStreak, Enabled: true, Params: RuleParams{MinMonths: 3}},
+		{ID: 5, Kind: RuleKindZScore, Params: RuleParams{Threshold: 0.1}},
+	}
+
+	anomalies := Evaluate(rules, consumption, month(2024, time.July))
+
+	byRule := make(map[int][]Anomaly)
+	for _, a := range anomalies {
+		byRule[a.RuleID] = append(byRule[a.RuleID], a)
+	}
+
+	if got := byRule[1]; len(got) != 1 || !got[0].Month.Equal(month(2024, time.July)) || got[0].Score < 3 || *got[0].ExpectedKWh != 100 {
+		t.Fatalf("unexpected z-score anomalies: %+v", got)
+	}
+
+	if got := byRule[2]; len(got) != 1 || got[0].SubscriberID != 1 || got[0].ConsumptionKWh != 400 {
+		t.Fatalf("unexpected iqr anomalies: %+v", got)
+	}
+
+	if got := byRule[3]; len(got) != 1 || got[0].SubscriberID != 2 || got[0].Score != 0.6 || *got[0].ExpectedKWh != 50 {
+		t.Fatalf("unexpected seasonal anomalies: %+v", got)
+	}
+
+	// The data ends in January 2025: subscriber 1 has no consumption from August to January,
+	// subscriber 2 from February to December, the streak starts before July, but ends after it.
+	zero := byRule[4]
+	if len(zero) != 2 {
+		t.Fatalf("expected 2 zero streaks, got %+v", zero)
+	}
+
+	if a := zero[0]; a.SubscriberID != 1 || a.Score != 6 || a.ConsumptionKWh != 0 || !a.Month.Equal(month(2025, time.January)) {
+		t.Fatalf("unexpected first zero streak: %+v", a)
+	}
+
+	if a := zero[1]; a.SubscriberID != 2 || a.Score != 11 || !a.Month.Equal(month(2024, time.December)) {
+		t.Fatalf("unexpected second zero streak: %+v", a)
+	}
+
+	if len(byRule[5]) != 0 {
+		t.Fatalf("disabled rule fired: %+v", byRule[5])
+	}
+}
+
+func TestAssignTenants(t *testing.T) {
+	month := func(m time.Month) time.Time {
+		return time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	consumption := []MonthlyConsumption{
+		{Month: month(time.March), SubscriberID: 1, ObjectID: 10, Tenant: "region"},
+		{Month: month(time.January), SubscriberID: 1, ObjectID: 10, Tenant: "ekb"},
+		{Month: month(time.April), SubscriberID: 1, ObjectID: 10},
+		{Month: month(time.January), SubscriberID: 2, ObjectID: 20},
+	}
+	anomalies := []Anomaly{
+		{ID: 1, Month: month(time.January), SubscriberID: 1, ObjectID: 10},
+		{ID: 2, Month: month(time.January), SubscriberID: 2, ObjectID: 20},
+	}
+
+	tenanted := assignTenants(anomalies, consumption)
+	if len(tenanted) != 1 || tenanted[0].ID != 1 || tenanted[0].Tenant != "region" {
+		t.Fatalf("expected anomaly 1 in the tenant of the latest month, got %+v", tenanted)
+	}
+}
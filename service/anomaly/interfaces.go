@@ -0,0 +1,19 @@
+package anomaly
+
+import (
+	"analytics-service/service/auth"
+	"context"
+	"time"
+
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+type Repository interface {
+	GetRules(ctx context.Context) ([]Rule, error)
+	GetMonthlyConsumption(ctx context.Context, before time.Time) ([]MonthlyConsumption, error)
+	UpsertAnomalies(ctx context.Context, from time.Time, anomalies []Anomaly) (int, error)
+	GetAnomalies(ctx context.Context, scope auth.TenantScope, filter Filter, page pagination.Pagination) ([]Anomaly, error)
+	GetUntenantedAnomalies(ctx context.Context) ([]Anomaly, error)
+	UpdateAnomalyTenants(ctx context.Context, anomalies []Anomaly) error
+	UpdateAnomalyStatus(ctx context.Context, scope auth.TenantScope, id int, status Status, comment string, triagedBy int) error
+}
@@ -0,0 +1,12 @@
+package anomaly
+
+import "analytics-service/service/masking"
+
+func MaskAnomalySlice(m *masking.Masker, policy masking.Policy, anomalies []Anomaly) []Anomaly {
+	for i := range anomalies {
+		anomalies[i].SubscriberAccountNumber = m.AccountNumber(policy, anomalies[i].SubscriberAccountNumber)
+		anomalies[i].ObjectAddress = m.Address(policy, anomalies[i].ObjectAddress)
+	}
+
+	return anomalies
+}
@@ -0,0 +1,112 @@
+package anomaly
+
+import (
+	"fmt"
+	"time"
+)
+
+type RuleKind string
+
+const (
+	RuleKindZScore     RuleKind = "z_score"
+	RuleKindIQR        RuleKind = "iqr"
+	RuleKindSeasonal   RuleKind = "seasonal"
+	RuleKindZeroStreak RuleKind = "zero_streak"
+)
+
+// Rule is an anomaly rule configured in Postgres, Params used depend on Kind.
+type Rule struct {
+	ID        int        `json:"ID"`
+	Name      string     `json:"Name"`
+	Kind      RuleKind   `json:"Kind"`
+	Params    RuleParams `json:"Params"`
+	Enabled   bool       `json:"Enabled"`
+	UpdatedAt time.Time  `json:"UpdatedAt"`
+}
+
+// RuleParams:
+//   - z_score flags a month whose z-score against the previous months is at least Threshold;
+//   - iqr flags a month outside K interquartile ranges of the previous months;
+//   - seasonal flags a month that deviates from the same month last year by at least Threshold, 0.5 is 50%;
+//   - zero_streak flags MinMonths or more months in a row without consumption.
+//
+// z_score and iqr need at least MinHistory previous months.
+type RuleParams struct {
+	Threshold  float64 `json:"threshold,omitempty"`
+	K          float64 `json:"k,omitempty"`
+	MinHistory int     `json:"minHistory,omitempty"`
+	MinMonths  int     `json:"minMonths,omitempty"`
+}
+
+type Status string
+
+const (
+	StatusNew       Status = "new"
+	StatusConfirmed Status = "confirmed"
+	StatusDismissed Status = "dismissed"
+)
+
+func ParseStatus(s string) (Status, error) {
+	switch status := Status(s); status {
+	case StatusNew, StatusConfirmed, StatusDismissed:
+		return status, nil
+	default:
+		return "", fmt.Errorf("unknown anomaly status %q", s)
+	}
+}
+
+// MonthlyConsumption is a row of v_bi_consumption_monthly, months without positive consumption are absent.
+type MonthlyConsumption struct {
+	Month                   time.Time
+	SubscriberID            int
+	SubscriberAccountNumber string
+	ObjectID                int
+	ObjectAddress           string
+	DistrictName            string
+	Tenant                  string
+	ConsumptionKWh          float64
+}
+
+// Anomaly is a month of a subscriber at an object flagged by a rule. Re-evaluation updates only anomalies
+// that weren't triaged yet.
+type Anomaly struct {
+	ID                      int        `json:"ID"`
+	RuleID                  int        `json:"RuleID"`
+	RuleName                string     `json:"RuleName"`
+	RuleKind                RuleKind   `json:"RuleKind"`
+	Month                   time.Time  `json:"Month"`
+	SubscriberID            int        `json:"SubscriberID"`
+	SubscriberAccountNumber string     `json:"SubscriberAccountNumber"`
+	ObjectID                int        `json:"ObjectID"`
+	ObjectAddress           string     `json:"ObjectAddress"`
+	DistrictName            string     `json:"DistrictName"`
+	Tenant                  string     `json:"Tenant"`
+	ConsumptionKWh          float64    `json:"ConsumptionKWh"`
+	ExpectedKWh             *float64   `json:"ExpectedKWh"`
+	Score                   float64    `json:"Score"`
+	Status                  Status     `json:"Status"`
+	Comment                 string     `json:"Comment"`
+	TriagedBy               *int       `json:"TriagedBy"`
+	TriagedAt               *time.Time `json:"TriagedAt"`
+	CreatedAt               time.Time  `json:"CreatedAt"`
+	UpdatedAt               time.Time  `json:"UpdatedAt"`
+}
+
+// Filter fields are ignored when zero.
+type Filter struct {
+	ID       int
+	Status   Status
+	RuleID   int
+	ObjectID int
+}
+
+// EvaluationResult counts flagged anomalies, Removed new anomalies that weren't flagged again and Tenanted anomalies
+// stored before tenancy that got a tenant.
+type EvaluationResult struct {
+	From      time.Time `json:"From"`
+	Rules     int       `json:"Rules"`
+	Series    int       `json:"Series"`
+	Anomalies int       `json:"Anomalies"`
+	Removed   int       `json:"Removed"`
+	Tenanted  int       `json:"Tenanted"`
+}
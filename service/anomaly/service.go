@@ -0,0 +1,131 @@
+package anomaly
+
+import (
+	"analytics-service/config"
+	"analytics-service/service/auth"
+	"analytics-service/service/masking"
+	"fmt"
+	"time"
+
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/gotime"
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+// Service evaluates anomaly rules over monthly consumption and keeps the results for triage by analysts.
+type Service struct {
+	repository Repository
+	masker     *masking.Masker
+	settings   config.Anomaly
+}
+
+func NewService(repository Repository, masker *masking.Masker, settings config.Anomaly) *Service {
+	return &Service{
+		repository: repository,
+		masker:     masker,
+		settings:   settings,
+	}
+}
+
+// Evaluate flags the last LookbackMonths months including the current one, the whole consumption history is used.
+// New anomalies of these months that aren't flagged anymore, e.g. after a rule change, are removed.
+// Anomalies stored before tenancy get the tenant of their series.
+func (s *Service) Evaluate(ctx goctx.Context) (EvaluationResult, error) {
+	now := time.Now().In(gotime.Moscow)
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := currentMonth.AddDate(0, -max(s.settings.LookbackMonths-1, 0), 0)
+
+	rules, err := s.repository.GetRules(ctx)
+	if err != nil {
+		return EvaluationResult{}, fmt.Errorf("get rules: %w", err)
+	}
+
+	consumption, err := s.repository.GetMonthlyConsumption(ctx, currentMonth.AddDate(0, 1, 0))
+	if err != nil {
+		return EvaluationResult{}, fmt.Errorf("get monthly consumption: %w", err)
+	}
+
+	anomalies := Evaluate(rules, consumption, from)
+
+	removed, err := s.repository.UpsertAnomalies(ctx, from, anomalies)
+	if err != nil {
+		return EvaluationResult{}, fmt.Errorf("upsert anomalies: %w", err)
+	}
+
+	untenanted, err := s.repository.GetUntenantedAnomalies(ctx)
+	if err != nil {
+		return EvaluationResult{}, fmt.Errorf("get untenanted anomalies: %w", err)
+	}
+
+	tenanted := assignTenants(untenanted, consumption)
+	if len(tenanted) > 0 {
+		if err = s.repository.UpdateAnomalyTenants(ctx, tenanted); err != nil {
+			return EvaluationResult{}, fmt.Errorf("update anomaly tenants: %w", err)
+		}
+	}
+
+	result := EvaluationResult{
+		From:      from,
+		Series:    countSeries(consumption),
+		Anomalies: len(anomalies),
+		Removed:   removed,
+		Tenanted:  len(tenanted),
+	}
+	for _, r := range rules {
+		if r.Enabled {
+			result.Rules++
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Service) GetRules(ctx goctx.Context) ([]Rule, error) {
+	rules, err := s.repository.GetRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (s *Service) GetAnomalies(ctx goctx.Context, filter Filter, page pagination.Pagination) ([]Anomaly, error) {
+	caller := auth.FromContext(ctx)
+
+	anomalies, err := s.repository.GetAnomalies(ctx, caller.TenantScope(), filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("get anomalies: %w", err)
+	}
+
+	return MaskAnomalySlice(s.masker, s.masker.Policy(masking.ScopeAPI, caller.Role), anomalies), nil
+}
+
+// Triage sets the status of the anomaly on behalf of the caller.
+func (s *Service) Triage(ctx goctx.Context, id int, status Status, comment string) (Anomaly, error) {
+	caller := auth.FromContext(ctx)
+	scope := caller.TenantScope()
+
+	err := s.repository.UpdateAnomalyStatus(ctx, scope, id, status, comment, caller.UserID)
+	if err != nil {
+		return Anomaly{}, fmt.Errorf("update anomaly status: %w", err)
+	}
+
+	anomalies, err := s.repository.GetAnomalies(ctx, scope, Filter{ID: id}, pagination.Pagination{})
+	if err != nil {
+		return Anomaly{}, fmt.Errorf("get anomalies: %w", err)
+	}
+	if len(anomalies) == 0 {
+		return Anomaly{}, fmt.Errorf("anomaly %d not found", id)
+	}
+
+	return MaskAnomalySlice(s.masker, s.masker.Policy(masking.ScopeAPI, caller.Role), anomalies)[0], nil
+}
+
+func countSeries(consumption []MonthlyConsumption) int {
+	keys := make(map[seriesKey]struct{})
+	for _, c := range consumption {
+		keys[seriesKey{subscriberID: c.SubscriberID, objectID: c.ObjectID}] = struct{}{}
+	}
+
+	return len(keys)
+}
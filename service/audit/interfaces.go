@@ -0,0 +1,12 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+type Repository interface {
+	AddRecords(ctx context.Context, records []Record) error
+	GetRecords(ctx context.Context, filter Filter, page pagination.Pagination) ([]Record, error)
+}
@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"analytics-service/service/auth"
+	"time"
+)
+
+type Action string
+
+const (
+	ActionReportGenerate  Action = "report_generate"
+	ActionReportAccess    Action = "report_access"
+	ActionReportDownload  Action = "report_download"
+	ActionWatchListAccess Action = "watch_list_access"
+	ActionSubscriberErase Action = "subscriber_erase"
+)
+
+type Record struct {
+	ID         int               `json:"ID"`
+	ActorID    int               `json:"ActorID"`
+	ActorRole  auth.Role         `json:"ActorRole"`
+	Action     Action            `json:"Action"`
+	ReportID   *int              `json:"ReportID"`
+	Parameters map[string]string `json:"Parameters"`
+	ClientIP   string            `json:"ClientIP"`
+	CreatedAt  time.Time         `json:"CreatedAt"`
+}
+
+// Filter fields with zero values are ignored.
+type Filter struct {
+	ActorID  int
+	Action   Action
+	ReportID int
+	From     *time.Time
+	To       *time.Time
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"analytics-service/service/auth"
+	"fmt"
+
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+type Service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) *Service {
+	return &Service{
+		repository: repository,
+	}
+}
+
+// Record writes one audit record per report on behalf of the caller from ctx.
+// Without report IDs a single record is written.
+func (s *Service) Record(ctx goctx.Context, action Action, reportIDs []int, parameters map[string]string) error {
+	caller := auth.FromContext(ctx)
+
+	newRecord := func(reportID *int) Record {
+		return Record{
+			ActorID:    caller.UserID,
+			ActorRole:  caller.Role,
+			Action:     action,
+			ReportID:   reportID,
+			Parameters: parameters,
+			ClientIP:   caller.ClientIP,
+		}
+	}
+
+	records := make([]Record, 0, max(len(reportIDs), 1))
+	for _, id := range reportIDs {
+		records = append(records, newRecord(&id))
+	}
+	if len(records) == 0 {
+		records = append(records, newRecord(nil))
+	}
+
+	if err := s.repository.AddRecords(ctx, records); err != nil {
+		return fmt.Errorf("add audit records: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetRecords(ctx goctx.Context, filter Filter, page pagination.Pagination) ([]Record, error) {
+	if err := page.Validate(); err != nil {
+		return nil, fmt.Errorf("validate pagination: %w", err)
+	}
+
+	records, err := s.repository.GetRecords(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("get audit records from db: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+type Role string
+
+const (
+	RoleUnknown    Role = ""
+	RoleAdmin      Role = "admin"
+	RoleAnalyst    Role = "analyst"
+	RoleContractor Role = "contractor"
+	RoleSystem     Role = "system"
+)
+
+type Caller struct {
+	UserID   int      `json:"UserID"`
+	Role     Role     `json:"Role"`
+	ClientIP string   `json:"ClientIP"`
+	Tenants  []string `json:"Tenants"`
+}
+
+// System is used for work that is not started by a user, e.g. cron jobs.
+func System() Caller {
+	return Caller{Role: RoleSystem}
+}
+
+// SystemFor is used for system work limited to one tenant, e.g. branch reports.
+func SystemFor(tenant string) Caller {
+	return Caller{Role: RoleSystem, Tenants: []string{tenant}}
+}
+
+// TenantScope is the set of tenants the caller may access. All is set instead of listing tenants.
+type TenantScope struct {
+	All     bool
+	Tenants []string
+}
+
+// TenantScope gives admins and system work without tenants access to all tenants, other callers access only their
+// tenants, so a caller without tenants sees nothing.
+func (c Caller) TenantScope() TenantScope {
+	if len(c.Tenants) == 0 && (c.Role == RoleAdmin || c.Role == RoleSystem) {
+		return TenantScope{All: true}
+	}
+
+	return TenantScope{Tenants: slices.Clone(c.Tenants)}
+}
+
+func (s TenantScope) Allows(tenant string) bool {
+	return s.All || slices.Contains(s.Tenants, tenant)
+}
+
+type callerKey struct{}
+
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// FromContext returns the caller stored in ctx or an unknown caller if there is none.
+func FromContext(ctx context.Context) Caller {
+	caller, ok := ctx.Value(callerKey{}).(Caller)
+	if !ok {
+		return Caller{}
+	}
+
+	return caller
+}
@@ -2,6 +2,8 @@ package cron
 
 import (
 	"analytics-service/service/analytics"
+	"analytics-service/service/anomaly"
+	"analytics-service/service/audit"
 	"time"
 
 	"github.com/sunshineOfficial/golib/goctx"
@@ -10,4 +12,17 @@ import (
 
 type AnalyticsService interface {
 	CreateBasicReport(ctx goctx.Context, log golog.Logger, periodStart, periodEnd time.Time) (analytics.Report, error)
+	CreateWatchListReport(ctx goctx.Context, log golog.Logger, periodStart, periodEnd time.Time) (analytics.Report, error)
+}
+
+type AuditService interface {
+	Record(ctx goctx.Context, action audit.Action, reportIDs []int, parameters map[string]string) error
+}
+
+type KPIService interface {
+	Collect(ctx goctx.Context) error
+}
+
+type AnomalyService interface {
+	Evaluate(ctx goctx.Context) (anomaly.EvaluationResult, error)
 }
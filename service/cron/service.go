@@ -2,9 +2,12 @@ package cron
 
 import (
 	"analytics-service/config"
+	"analytics-service/service/audit"
+	"analytics-service/service/auth"
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -17,14 +20,36 @@ import (
 type Service struct {
 	scheduler        gocron.Scheduler
 	settings         config.Cron
+	tenants          []config.Tenant
 	analyticsService AnalyticsService
+	auditService     AuditService
+	kpiService       KPIService
+	anomalyService   AnomalyService
 	running          *atomic.Bool
 }
 
-func NewService(settings config.Cron, analyticsService AnalyticsService) *Service {
+// Settings are the config sections used by the service.
+type Settings struct {
+	Cron    config.Cron
+	Tenants []config.Tenant
+}
+
+// reportSchedule is when the reports of a tenant are created. Reports of the system caller cover all tenants.
+type reportSchedule struct {
+	caller              auth.Caller
+	dailyReportTime     time.Time
+	watchListReportTime time.Time
+}
+
+func NewService(settings Settings, analyticsService AnalyticsService, auditService AuditService, kpiService KPIService,
+	anomalyService AnomalyService) *Service {
 	return &Service{
-		settings:         settings,
+		settings:         settings.Cron,
+		tenants:          settings.Tenants,
 		analyticsService: analyticsService,
+		auditService:     auditService,
+		kpiService:       kpiService,
+		anomalyService:   anomalyService,
 		running:          &atomic.Bool{},
 	}
 }
@@ -36,9 +61,14 @@ func (s *Service) Start(ctx context.Context, log golog.Logger) error {
 
 	s.running.Store(true)
 
-	dailyReportTime, err := time.Parse(gotime.TimeOnlyNet, s.settings.DailyReportTime)
+	schedules, err := s.reportSchedules()
 	if err != nil {
-		return fmt.Errorf("parse daily report time: %w", err)
+		return err
+	}
+
+	anomalyEvaluationTime, err := time.Parse(gotime.TimeOnlyNet, s.settings.AnomalyEvaluationTime)
+	if err != nil {
+		return fmt.Errorf("parse anomaly evaluation time: %w", err)
 	}
 
 	s.scheduler, err = gocron.NewScheduler(
@@ -51,22 +81,123 @@ func (s *Service) Start(ctx context.Context, log golog.Logger) error {
 		return fmt.Errorf("create cron scheduler: %w", err)
 	}
 
+	for _, schedule := range schedules {
+		if err = s.addReportJobs(ctx, log, schedule); err != nil {
+			return err
+		}
+	}
+
+	anomalyJob, err := s.scheduler.NewJob(
+		gocron.DailyJob(
+			1,
+			gocron.NewAtTimes(
+				gocron.NewAtTime(uint(anomalyEvaluationTime.Hour()), uint(anomalyEvaluationTime.Minute()), 0),
+			),
+		),
+		gocron.NewTask(s.anomalyTask, ctx, log.WithTags("anomalyTask")),
+	)
+	if err != nil {
+		return fmt.Errorf("create anomaly job: %w", err)
+	}
+
+	// Singleton mode skips a refresh while the previous one is still running, so slow queries don't pile up.
+	kpiJob, err := s.scheduler.NewJob(
+		gocron.DurationJob(time.Duration(s.settings.KPIRefreshInterval)),
+		gocron.NewTask(s.kpiTask, ctx, log.WithTags("kpiTask")),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		gocron.WithStartAt(gocron.WithStartImmediately()),
+	)
+	if err != nil {
+		return fmt.Errorf("create kpi job: %w", err)
+	}
+
+	s.scheduler.Start()
+
+	log.Debugf("started anomaly job %s", anomalyJob.ID())
+	log.Debugf("started kpi job %s", kpiJob.ID())
+
+	return nil
+}
+
+// reportSchedules gives every tenant its own report jobs, the tenant times fall back to the common ones.
+// Without tenants the reports are created once for all of them.
+func (s *Service) reportSchedules() ([]reportSchedule, error) {
+	if len(s.tenants) == 0 {
+		schedule, err := parseReportSchedule(auth.System(), s.settings.DailyReportTime, s.settings.WatchListReportTime)
+		if err != nil {
+			return nil, err
+		}
+
+		return []reportSchedule{schedule}, nil
+	}
+
+	schedules := make([]reportSchedule, 0, len(s.tenants))
+	for _, t := range s.tenants {
+		dailyReportTime, watchListReportTime := t.DailyReportTime, t.WatchListReportTime
+		if dailyReportTime == "" {
+			dailyReportTime = s.settings.DailyReportTime
+		}
+		if watchListReportTime == "" {
+			watchListReportTime = s.settings.WatchListReportTime
+		}
+
+		schedule, err := parseReportSchedule(auth.SystemFor(t.ID), dailyReportTime, watchListReportTime)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", t.ID, err)
+		}
+
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
+
+func parseReportSchedule(caller auth.Caller, dailyReportTime, watchListReportTime string) (reportSchedule, error) {
+	daily, err := time.Parse(gotime.TimeOnlyNet, dailyReportTime)
+	if err != nil {
+		return reportSchedule{}, fmt.Errorf("parse daily report time: %w", err)
+	}
+
+	watchList, err := time.Parse(gotime.TimeOnlyNet, watchListReportTime)
+	if err != nil {
+		return reportSchedule{}, fmt.Errorf("parse watch-list report time: %w", err)
+	}
+
+	return reportSchedule{caller: caller, dailyReportTime: daily, watchListReportTime: watchList}, nil
+}
+
+func (s *Service) addReportJobs(ctx context.Context, log golog.Logger, schedule reportSchedule) error {
+	log = log.WithTags(schedule.caller.Tenants...)
+
 	reportJob, err := s.scheduler.NewJob(
 		gocron.DailyJob(
 			1,
 			gocron.NewAtTimes(
-				gocron.NewAtTime(uint(dailyReportTime.Hour()), uint(dailyReportTime.Minute()), 0),
+				gocron.NewAtTime(uint(schedule.dailyReportTime.Hour()), uint(schedule.dailyReportTime.Minute()), 0),
 			),
 		),
-		gocron.NewTask(s.dailyReportTask, ctx, log.WithTags("dailyReportTask")),
+		gocron.NewTask(s.dailyReportTask, ctx, log.WithTags("dailyReportTask"), schedule.caller),
 	)
 	if err != nil {
 		return fmt.Errorf("create report job: %w", err)
 	}
 
-	s.scheduler.Start()
+	watchListJob, err := s.scheduler.NewJob(
+		gocron.WeeklyJob(
+			1,
+			gocron.NewWeekdays(time.Monday),
+			gocron.NewAtTimes(
+				gocron.NewAtTime(uint(schedule.watchListReportTime.Hour()), uint(schedule.watchListReportTime.Minute()), 0),
+			),
+		),
+		gocron.NewTask(s.watchListReportTask, ctx, log.WithTags("watchListReportTask"), schedule.caller),
+	)
+	if err != nil {
+		return fmt.Errorf("create watch-list report job: %w", err)
+	}
 
 	log.Debugf("started report job %s", reportJob.ID())
+	log.Debugf("started watch-list report job %s", watchListJob.ID())
 
 	return nil
 }
@@ -85,19 +216,85 @@ func (s *Service) Stop() error {
 	return nil
 }
 
-func (s *Service) dailyReportTask(ctx context.Context, log golog.Logger) {
+func (s *Service) dailyReportTask(ctx context.Context, log golog.Logger, caller auth.Caller) {
 	log.Debugf("start daily report task at %s", time.Now())
 
-	wrappedCtx, cancel := goctx.Wrap(ctx).WithTimeout(time.Duration(s.settings.TaskTimeout))
+	wrappedCtx, cancel := goctx.Wrap(auth.WithCaller(ctx, caller)).WithTimeout(time.Duration(s.settings.TaskTimeout))
 	defer cancel()
 
 	now := time.Now()
 
-	report, err := s.analyticsService.CreateBasicReport(wrappedCtx, log, now, now.AddDate(0, 0, 1))
+	periodStart, periodEnd := now, now.AddDate(0, 0, 1)
+
+	report, err := s.analyticsService.CreateBasicReport(wrappedCtx, log, periodStart, periodEnd)
 	if err != nil {
 		log.Errorf("failed to create daily basic report: %v", err)
 		return
 	}
 
+	err = s.auditService.Record(wrappedCtx, audit.ActionReportGenerate, []int{report.ID}, map[string]string{
+		"periodStart":   periodStart.Format(time.DateOnly),
+		"periodEnd":     periodEnd.Format(time.DateOnly),
+		"maskingPolicy": string(report.MaskingPolicy),
+		"job":           "dailyReportTask",
+		"tenants":       strings.Join(caller.Tenants, ","),
+	})
+	if err != nil {
+		log.Errorf("failed to record audit for daily report %d: %v", report.ID, err)
+	}
+
 	log.Debugf("created daily report %q at %v", report.Files[0].FileName, report.CreatedAt)
 }
+
+func (s *Service) watchListReportTask(ctx context.Context, log golog.Logger, caller auth.Caller) {
+	log.Debugf("start watch-list report task at %s", time.Now())
+
+	wrappedCtx, cancel := goctx.Wrap(auth.WithCaller(ctx, caller)).WithTimeout(time.Duration(s.settings.TaskTimeout))
+	defer cancel()
+
+	periodEnd := time.Now().AddDate(0, 0, 1)
+	periodStart := periodEnd.Add(-time.Duration(s.settings.WatchListLookback))
+
+	report, err := s.analyticsService.CreateWatchListReport(wrappedCtx, log, periodStart, periodEnd)
+	if err != nil {
+		log.Errorf("failed to create weekly watch-list report: %v", err)
+		return
+	}
+
+	err = s.auditService.Record(wrappedCtx, audit.ActionReportGenerate, []int{report.ID}, map[string]string{
+		"type":          report.Type.Name(),
+		"periodStart":   periodStart.Format(time.DateOnly),
+		"periodEnd":     periodEnd.Format(time.DateOnly),
+		"maskingPolicy": string(report.MaskingPolicy),
+		"job":           "watchListReportTask",
+		"tenants":       strings.Join(caller.Tenants, ","),
+	})
+	if err != nil {
+		log.Errorf("failed to record audit for watch-list report %d: %v", report.ID, err)
+	}
+
+	log.Debugf("created watch-list report %q at %v", report.Files[0].FileName, report.CreatedAt)
+}
+
+func (s *Service) anomalyTask(ctx context.Context, log golog.Logger) {
+	wrappedCtx, cancel := goctx.Wrap(auth.WithCaller(ctx, auth.System())).WithTimeout(time.Duration(s.settings.TaskTimeout))
+	defer cancel()
+
+	result, err := s.anomalyService.Evaluate(wrappedCtx)
+	if err != nil {
+		log.Errorf("failed to evaluate anomalies: %v", err)
+		return
+	}
+
+	log.Debugf("evaluated %d anomaly rules over %d series from %s, flagged %d anomalies",
+		result.Rules, result.Series, result.From.Format(time.DateOnly), result.Anomalies)
+}
+
+func (s *Service) kpiTask(ctx context.Context, log golog.Logger) {
+	wrappedCtx, cancel := goctx.Wrap(auth.WithCaller(ctx, auth.System())).WithTimeout(time.Duration(s.settings.TaskTimeout))
+	defer cancel()
+
+	if err := s.kpiService.Collect(wrappedCtx); err != nil {
+		log.Errorf("failed to collect kpi: %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package erasure
+
+import (
+	"analytics-service/service/auth"
+	"context"
+
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+type Repository interface {
+	GetObjectIDs(ctx context.Context, scope auth.TenantScope, subscriberID int) ([]int, error)
+	AnonymizeFinishedTasks(ctx context.Context, scope auth.TenantScope, subscriberID int) error
+	AnonymizeAnomalies(ctx context.Context, scope auth.TenantScope, subscriberID int) (int, error)
+	AddRequest(ctx context.Context, request Request) (Request, error)
+	UpdateRequest(ctx context.Context, request Request) error
+	GetRequests(ctx context.Context, scope auth.TenantScope, page pagination.Pagination) ([]Request, error)
+}
+
+// ContractCache keeps the last contracts of objects used to enrich finished tasks.
+type ContractCache interface {
+	InvalidateContract(ctx context.Context, objectID int) error
+}
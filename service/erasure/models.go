@@ -0,0 +1,24 @@
+package erasure
+
+import "time"
+
+type RequestStatus string
+
+const (
+	RequestStatusPending RequestStatus = "pending"
+	RequestStatusDone    RequestStatus = "done"
+)
+
+// Request is a subscriber erasure request, it's pending until the data is anonymized. Anomalies is the number of
+// anonymized anomalies, finished tasks are anonymized by ClickHouse in the background. Tenants are the tenants
+// of the requester, an empty list means all tenants.
+type Request struct {
+	ID           int           `json:"ID"`
+	SubscriberID int           `json:"SubscriberID"`
+	Reason       string        `json:"Reason"`
+	RequestedBy  int           `json:"RequestedBy"`
+	Tenants      []string      `json:"Tenants"`
+	Status       RequestStatus `json:"Status"`
+	Anomalies    int           `json:"Anomalies"`
+	CreatedAt    time.Time     `json:"CreatedAt"`
+}
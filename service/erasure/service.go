@@ -0,0 +1,87 @@
+package erasure
+
+import (
+	"analytics-service/service/auth"
+	"fmt"
+
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/pagination"
+)
+
+// Service erases personal data of subscribers on request. Rows are anonymized instead of deleted, so aggregate
+// metrics are kept intact.
+type Service struct {
+	repository Repository
+	contracts  ContractCache
+}
+
+func NewService(repository Repository, contracts ContractCache) *Service {
+	return &Service{
+		repository: repository,
+		contracts:  contracts,
+	}
+}
+
+// Erase records the request and anonymizes the subscriber in the finished tasks, replay tables included, and anomalies
+// of the caller's tenants. The request is recorded first, ingestion anonymizes new tasks of the subscriber from that moment on.
+// Cached contracts of the subscriber's objects are dropped, they hold the personal data too. The request is left
+// pending if the erasure fails, erasing the subscriber again retries it.
+func (s *Service) Erase(ctx goctx.Context, subscriberID int, reason string) (Request, error) {
+	if subscriberID <= 0 {
+		return Request{}, fmt.Errorf("invalid subscriber id %d", subscriberID)
+	}
+
+	caller := auth.FromContext(ctx)
+	scope := caller.TenantScope()
+
+	request, err := s.repository.AddRequest(ctx, Request{
+		SubscriberID: subscriberID,
+		Reason:       reason,
+		RequestedBy:  caller.UserID,
+		Tenants:      scope.Tenants,
+		Status:       RequestStatusPending,
+	})
+	if err != nil {
+		return Request{}, fmt.Errorf("add erasure request to db: %w", err)
+	}
+
+	objectIDs, err := s.repository.GetObjectIDs(ctx, scope, subscriberID)
+	if err != nil {
+		return Request{}, fmt.Errorf("get object ids: %w", err)
+	}
+
+	if err = s.repository.AnonymizeFinishedTasks(ctx, scope, subscriberID); err != nil {
+		return Request{}, fmt.Errorf("anonymize finished tasks: %w", err)
+	}
+
+	request.Anomalies, err = s.repository.AnonymizeAnomalies(ctx, scope, subscriberID)
+	if err != nil {
+		return Request{}, fmt.Errorf("anonymize anomalies: %w", err)
+	}
+
+	for _, objectID := range objectIDs {
+		if err = s.contracts.InvalidateContract(ctx, objectID); err != nil {
+			return Request{}, fmt.Errorf("invalidate contract: %w", err)
+		}
+	}
+
+	request.Status = RequestStatusDone
+	if err = s.repository.UpdateRequest(ctx, request); err != nil {
+		return Request{}, fmt.Errorf("update erasure request in db: %w", err)
+	}
+
+	return request, nil
+}
+
+func (s *Service) GetRequests(ctx goctx.Context, page pagination.Pagination) ([]Request, error) {
+	if err := page.Validate(); err != nil {
+		return nil, fmt.Errorf("validate pagination: %w", err)
+	}
+
+	requests, err := s.repository.GetRequests(ctx, auth.FromContext(ctx).TenantScope(), page)
+	if err != nil {
+		return nil, fmt.Errorf("get erasure requests from db: %w", err)
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,191 @@
+package forecast
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// season is the length of the weekly cycle of the daily series.
+	season = 7
+
+	// MinHistoryDays is the shortest history both methods can be fitted on.
+	MinHistoryDays = 2 * season
+
+	// confidence is the level of prediction intervals, z is the matching standard normal quantile.
+	confidence = 0.95
+	z          = 1.96
+)
+
+// Series turns the daily counts from from to to into a dense series per type, days without tasks are zeros.
+func Series(days []TasksDaily, from, to time.Time) map[TaskType][]float64 {
+	n := int(to.Sub(from).Hours() / 24)
+
+	series := make(map[TaskType][]float64, len(TaskTypes))
+	for _, t := range TaskTypes {
+		series[t] = make([]float64, n)
+	}
+
+	for _, d := range days {
+		i := int(d.Day.Sub(from).Hours() / 24)
+		if i < 0 || i >= n {
+			continue
+		}
+
+		series[TaskTypeLimitation][i] = float64(d.LimitationCount)
+		series[TaskTypeResumption][i] = float64(d.ResumptionCount)
+		series[TaskTypeVerification][i] = float64(d.VerificationCount)
+		series[TaskTypeUnauthorizedConnection][i] = float64(d.UnauthorizedConnectionCount)
+		series[TaskTypeTotal][i] = float64(d.LimitationCount + d.ResumptionCount + d.VerificationCount + d.UnauthorizedConnectionCount)
+	}
+
+	return series
+}
+
+// Predict forecasts horizon days following the history, the first of them is start. The history must be
+// at least MinHistoryDays long. Intervals widen with the horizon and are cut at zero like the predictions.
+func Predict(method Method, history []float64, start time.Time, horizon int, alpha float64) []Prediction {
+	var values, sigmas []float64
+	switch method {
+	case MethodSeasonalNaive:
+		values, sigmas = seasonalNaive(history, horizon)
+	default:
+		values, sigmas = exponentialSmoothing(history, horizon, alpha)
+	}
+
+	predictions := make([]Prediction, 0, horizon)
+	for h := range horizon {
+		predictions = append(predictions, Prediction{
+			Day:   start.AddDate(0, 0, h),
+			Value: round2(max(values[h], 0)),
+			Lower: round2(max(values[h]-z*sigmas[h], 0)),
+			Upper: round2(max(values[h]+z*sigmas[h], 0)),
+		})
+	}
+
+	return predictions
+}
+
+// seasonalNaive takes the error spread from the differences between the same weekdays of consecutive weeks.
+func seasonalNaive(history []float64, horizon int) ([]float64, []float64) {
+	n := len(history)
+
+	var squares float64
+	for t := season; t < n; t++ {
+		e := history[t] - history[t-season]
+		squares += e * e
+	}
+	sigma := math.Sqrt(squares / float64(n-season))
+
+	values := make([]float64, 0, horizon)
+	sigmas := make([]float64, 0, horizon)
+	for h := range horizon {
+		values = append(values, history[n-season+h%season])
+		sigmas = append(sigmas, sigma*math.Sqrt(float64(h/season+1)))
+	}
+
+	return values, sigmas
+}
+
+// exponentialSmoothing removes the average weekday offsets from the history, smooths the remaining level
+// with the alpha factor and adds the offsets back. The error spread comes from one-step-ahead in-sample errors.
+func exponentialSmoothing(history []float64, horizon int, alpha float64) ([]float64, []float64) {
+	n := len(history)
+	offsets := weekdayOffsets(history)
+
+	level := history[0] - offsets[0]
+
+	var squares float64
+	for t := 1; t < n; t++ {
+		e := history[t] - offsets[t%season] - level
+		squares += e * e
+		level += alpha * e
+	}
+	sigma := math.Sqrt(squares / float64(n-1))
+
+	values := make([]float64, 0, horizon)
+	sigmas := make([]float64, 0, horizon)
+	for h := range horizon {
+		values = append(values, level+offsets[(n+h)%season])
+		sigmas = append(sigmas, sigma*math.Sqrt(1+float64(h)*alpha*alpha))
+	}
+
+	return values, sigmas
+}
+
+// weekdayOffsets returns the mean of every position in the weekly cycle minus the overall mean.
+func weekdayOffsets(history []float64) []float64 {
+	var (
+		sums   = make([]float64, season)
+		counts = make([]float64, season)
+		total  float64
+	)
+
+	for t, v := range history {
+		sums[t%season] += v
+		counts[t%season]++
+		total += v
+	}
+
+	mean := total / float64(len(history))
+
+	offsets := make([]float64, season)
+	for i := range offsets {
+		if counts[i] > 0 {
+			offsets[i] = sums[i]/counts[i] - mean
+		}
+	}
+
+	return offsets
+}
+
+// Evaluate compares the predictions with the actual counts of the same days.
+func Evaluate(method Method, actual []float64, predictions []Prediction) Accuracy {
+	accuracy := Accuracy{
+		Method: method,
+		Points: make([]BacktestPoint, 0, len(predictions)),
+	}
+	if len(predictions) == 0 {
+		return accuracy
+	}
+
+	var absolute, squares, percents, nonZero, covered float64
+	for i, p := range predictions {
+		e := actual[i] - p.Value
+		absolute += math.Abs(e)
+		squares += e * e
+
+		if actual[i] != 0 {
+			percents += math.Abs(e) / actual[i]
+			nonZero++
+		}
+
+		if p.Lower <= actual[i] && actual[i] <= p.Upper {
+			covered++
+		}
+
+		accuracy.Points = append(accuracy.Points, BacktestPoint{
+			Day:    p.Day,
+			Actual: int(actual[i]),
+			Value:  p.Value,
+			Lower:  p.Lower,
+			Upper:  p.Upper,
+		})
+	}
+
+	n := float64(len(predictions))
+	accuracy.MAE = round2(absolute / n)
+	accuracy.RMSE = round2(math.Sqrt(squares / n))
+	accuracy.Coverage = round2(covered / n)
+
+	if nonZero > 0 {
+		mape := round2(percents / nonZero)
+		accuracy.MAPE = &mape
+	}
+
+	return accuracy
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,86 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+)
+
+// weekly repeats the same weekly pattern, so both methods must predict it exactly.
+func weekly(weeks int) []float64 {
+	pattern := []float64{10, 12, 11, 13, 9, 2, 1}
+
+	history := make([]float64, 0, weeks*len(pattern))
+	for range weeks {
+		history = append(history, pattern...)
+	}
+
+	return history
+}
+
+func TestPredictRepeatsWeeklyPattern(t *testing.T) {
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	history := weekly(4)
+
+	for _, method := range Methods {
+		predictions := Predict(method, history, start, 9, 0.3)
+		if len(predictions) != 9 {
+			t.Fatalf("%s: expected 9 predictions, got %d", method, len(predictions))
+		}
+
+		for h, p := range predictions {
+			if want := history[h%season]; p.Value != want || p.Lower != want || p.Upper != want {
+				t.Fatalf("%s: unexpected prediction %d: %+v, want %v", method, h, p, want)
+			}
+		}
+
+		if !predictions[8].Day.Equal(start.AddDate(0, 0, 8)) {
+			t.Fatalf("%s: unexpected day %v", method, predictions[8].Day)
+		}
+	}
+}
+
+func TestPredictIntervalsWidenWithHorizon(t *testing.T) {
+	history := weekly(4)
+	history[20] += 6
+
+	for _, method := range Methods {
+		predictions := Predict(method, history, time.Time{}, 14, 0.3)
+
+		first, nextWeek := predictions[0], predictions[7]
+		if first.Upper-first.Value <= 0 || nextWeek.Upper-nextWeek.Value <= first.Upper-first.Value {
+			t.Fatalf("%s: expected widening intervals, got %+v and %+v", method, first, nextWeek)
+		}
+	}
+}
+
+func TestSeriesFillsMissingDays(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	series := Series([]TasksDaily{
+		{Day: from.AddDate(0, 0, 1), LimitationCount: 2, ResumptionCount: 1},
+		{Day: from.AddDate(0, 0, 5), VerificationCount: 3},
+	}, from, from.AddDate(0, 0, 4))
+
+	if got := series[TaskTypeTotal]; len(got) != 4 || got[0] != 0 || got[1] != 3 {
+		t.Fatalf("unexpected total series: %v", got)
+	}
+
+	if got := series[TaskTypeVerification]; got[3] != 0 {
+		t.Fatalf("expected days outside the range to be skipped: %v", got)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	accuracy := Evaluate(MethodSeasonalNaive, []float64{10, 0}, []Prediction{
+		{Value: 8, Lower: 5, Upper: 11},
+		{Value: 2, Lower: 1, Upper: 3},
+	})
+
+	if accuracy.MAE != 2 || accuracy.RMSE != 2 || accuracy.Coverage != 0.5 {
+		t.Fatalf("unexpected accuracy: %+v", accuracy)
+	}
+
+	if accuracy.MAPE == nil || *accuracy.MAPE != 0.2 {
+		t.Fatalf("expected MAPE over days with tasks only, got %v", accuracy.MAPE)
+	}
+}
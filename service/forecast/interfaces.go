@@ -0,0 +1,11 @@
+package forecast
+
+import (
+	"analytics-service/service/auth"
+	"context"
+	"time"
+)
+
+type Repository interface {
+	GetTasksDaily(ctx context.Context, scope auth.TenantScope, from, to time.Time) ([]TasksDaily, error)
+}
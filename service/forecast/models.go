@@ -0,0 +1,98 @@
+package forecast
+
+import "time"
+
+// TaskType names a forecasted series, the values match inspection types of finished tasks.
+type TaskType string
+
+const (
+	TaskTypeLimitation             TaskType = "limitation"
+	TaskTypeResumption             TaskType = "resumption"
+	TaskTypeVerification           TaskType = "verification"
+	TaskTypeUnauthorizedConnection TaskType = "unauthorized_connection"
+	TaskTypeTotal                  TaskType = "total"
+)
+
+var TaskTypes = []TaskType{
+	TaskTypeLimitation,
+	TaskTypeResumption,
+	TaskTypeVerification,
+	TaskTypeUnauthorizedConnection,
+	TaskTypeTotal,
+}
+
+type Method string
+
+const (
+	// MethodSeasonalNaive repeats the value of the same weekday a week earlier.
+	MethodSeasonalNaive Method = "seasonal_naive"
+	// MethodExponentialSmoothing smooths the level of the series with weekday offsets removed.
+	MethodExponentialSmoothing Method = "exponential_smoothing"
+)
+
+var Methods = []Method{MethodSeasonalNaive, MethodExponentialSmoothing}
+
+// TasksDaily is the number of finished tasks of every type in a day.
+type TasksDaily struct {
+	Day                         time.Time
+	LimitationCount             int
+	ResumptionCount             int
+	VerificationCount           int
+	UnauthorizedConnectionCount int
+}
+
+type Prediction struct {
+	Day   time.Time `json:"Day"`
+	Value float64   `json:"Value"`
+	Lower float64   `json:"Lower"`
+	Upper float64   `json:"Upper"`
+}
+
+type SeriesForecast struct {
+	Type        TaskType     `json:"Type"`
+	Predictions []Prediction `json:"Predictions"`
+}
+
+// Forecast predicts task counts of the Days days starting from From. Lower and Upper bound the prediction
+// interval with the Confidence level.
+type Forecast struct {
+	Method      Method           `json:"Method"`
+	HistoryFrom time.Time        `json:"HistoryFrom"`
+	From        time.Time        `json:"From"`
+	Days        int              `json:"Days"`
+	Confidence  float64          `json:"Confidence"`
+	Series      []SeriesForecast `json:"Series"`
+}
+
+type BacktestPoint struct {
+	Day    time.Time `json:"Day"`
+	Actual int       `json:"Actual"`
+	Value  float64   `json:"Value"`
+	Lower  float64   `json:"Lower"`
+	Upper  float64   `json:"Upper"`
+}
+
+// Accuracy compares predictions with actual counts. MAPE skips days without tasks and is nil if there are none,
+// Coverage is the share of actual counts inside the prediction interval.
+type Accuracy struct {
+	Method   Method          `json:"Method"`
+	MAE      float64         `json:"MAE"`
+	RMSE     float64         `json:"RMSE"`
+	MAPE     *float64        `json:"MAPE"`
+	Coverage float64         `json:"Coverage"`
+	Points   []BacktestPoint `json:"Points"`
+}
+
+type SeriesBacktest struct {
+	Type     TaskType   `json:"Type"`
+	Accuracy []Accuracy `json:"Accuracy"`
+}
+
+// Backtest fits the methods on the history before TestFrom and forecasts the Days days that followed.
+type Backtest struct {
+	HistoryFrom time.Time        `json:"HistoryFrom"`
+	TestFrom    time.Time        `json:"TestFrom"`
+	Days        int              `json:"Days"`
+	Confidence  float64          `json:"Confidence"`
+	Series      []SeriesBacktest `json:"Series"`
+}
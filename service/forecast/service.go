@@ -0,0 +1,118 @@
+package forecast
+
+import (
+	"analytics-service/config"
+	"analytics-service/service/auth"
+	"fmt"
+	"time"
+
+	"github.com/sunshineOfficial/golib/goctx"
+)
+
+// Service forecasts the daily workload of brigades by inspection type from the history of finished tasks.
+type Service struct {
+	repository Repository
+	settings   config.Forecast
+}
+
+func NewService(repository Repository, settings config.Forecast) *Service {
+	return &Service{
+		repository: repository,
+		settings:   settings,
+	}
+}
+
+// Forecast predicts the next days days starting from today, the history ends yesterday.
+func (s *Service) Forecast(ctx goctx.Context, days int) (Forecast, error) {
+	if err := s.validate(days); err != nil {
+		return Forecast{}, err
+	}
+
+	from := today()
+	historyFrom := from.AddDate(0, 0, -s.settings.HistoryDays)
+
+	tasks, err := s.repository.GetTasksDaily(ctx, auth.FromContext(ctx).TenantScope(), historyFrom, from)
+	if err != nil {
+		return Forecast{}, fmt.Errorf("get tasks daily: %w", err)
+	}
+
+	series := Series(tasks, historyFrom, from)
+
+	forecast := Forecast{
+		Method:      MethodExponentialSmoothing,
+		HistoryFrom: historyFrom,
+		From:        from,
+		Days:        days,
+		Confidence:  confidence,
+		Series:      make([]SeriesForecast, 0, len(TaskTypes)),
+	}
+	for _, t := range TaskTypes {
+		forecast.Series = append(forecast.Series, SeriesForecast{
+			Type:        t,
+			Predictions: Predict(forecast.Method, series[t], from, days, s.settings.Alpha),
+		})
+	}
+
+	return forecast, nil
+}
+
+// Backtest hides the last days days before today, forecasts them with every method from the history
+// of HistoryDays days before and reports the errors.
+func (s *Service) Backtest(ctx goctx.Context, days int) (Backtest, error) {
+	if err := s.validate(days); err != nil {
+		return Backtest{}, err
+	}
+
+	to := today()
+	testFrom := to.AddDate(0, 0, -days)
+	historyFrom := testFrom.AddDate(0, 0, -s.settings.HistoryDays)
+
+	tasks, err := s.repository.GetTasksDaily(ctx, auth.FromContext(ctx).TenantScope(), historyFrom, to)
+	if err != nil {
+		return Backtest{}, fmt.Errorf("get tasks daily: %w", err)
+	}
+
+	series := Series(tasks, historyFrom, to)
+
+	backtest := Backtest{
+		HistoryFrom: historyFrom,
+		TestFrom:    testFrom,
+		Days:        days,
+		Confidence:  confidence,
+		Series:      make([]SeriesBacktest, 0, len(TaskTypes)),
+	}
+	for _, t := range TaskTypes {
+		history, actual := series[t][:s.settings.HistoryDays], series[t][s.settings.HistoryDays:]
+
+		result := SeriesBacktest{Type: t, Accuracy: make([]Accuracy, 0, len(Methods))}
+		for _, m := range Methods {
+			predictions := Predict(m, history, testFrom, days, s.settings.Alpha)
+			result.Accuracy = append(result.Accuracy, Evaluate(m, actual, predictions))
+		}
+
+		backtest.Series = append(backtest.Series, result)
+	}
+
+	return backtest, nil
+}
+
+func (s *Service) validate(days int) error {
+	if days < 1 || days > s.settings.MaxDays {
+		return fmt.Errorf("days must be from 1 to %d, got: %d", s.settings.MaxDays, days)
+	}
+
+	if s.settings.HistoryDays < MinHistoryDays {
+		return fmt.Errorf("history must be at least %d days long, got: %d", MinHistoryDays, s.settings.HistoryDays)
+	}
+
+	if s.settings.Alpha <= 0 || s.settings.Alpha > 1 {
+		return fmt.Errorf("alpha must be in (0, 1], got: %v", s.settings.Alpha)
+	}
+
+	return nil
+}
+
+// today is the start of the current day in UTC, the days of the BI views are UTC dates.
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,25 @@
+package forecast
+
+import (
+	"analytics-service/config"
+	"testing"
+)
+
+func TestValidateChecksAlpha(t *testing.T) {
+	for _, tc := range []struct {
+		alpha float64
+		valid bool
+	}{
+		{alpha: 0, valid: false},
+		{alpha: -0.1, valid: false},
+		{alpha: 0.3, valid: true},
+		{alpha: 1, valid: true},
+		{alpha: 1.5, valid: false},
+	} {
+		s := NewService(nil, config.Forecast{HistoryDays: MinHistoryDays, Alpha: tc.alpha, MaxDays: 7})
+
+		if err := s.validate(7); (err == nil) != tc.valid {
+			t.Errorf("alpha %v: expected valid = %v, got error %v", tc.alpha, tc.valid, err)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/segmentio/kafka-go"
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/gohttp"
+)
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingFunc adapts a function to Pinger.
+type PingFunc func(ctx context.Context) error
+
+func (f PingFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+type sqlChecker struct {
+	name string
+	db   *sqlx.DB
+}
+
+func NewSQLChecker(name string, db *sqlx.DB) Checker {
+	return sqlChecker{name: name, db: db}
+}
+
+func (c sqlChecker) Name() string {
+	return c.name
+}
+
+func (c sqlChecker) Check(ctx context.Context, details map[string]string) error {
+	if err := c.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("c.db.PingContext: %w", err)
+	}
+
+	return nil
+}
+
+type pingChecker struct {
+	name   string
+	pinger Pinger
+}
+
+func NewPingChecker(name string, pinger Pinger) Checker {
+	return pingChecker{name: name, pinger: pinger}
+}
+
+func (c pingChecker) Name() string {
+	return c.name
+}
+
+func (c pingChecker) Check(ctx context.Context, details map[string]string) error {
+	if err := c.pinger.Ping(ctx); err != nil {
+		return fmt.Errorf("c.pinger.Ping: %w", err)
+	}
+
+	return nil
+}
+
+type kafkaChecker struct {
+	client  *kafka.Client
+	topic   string
+	groupID string
+	maxLag  int64
+}
+
+// NewKafkaChecker checks that brokers are reachable and the consumer group lag on topic is at most maxLag.
+// Lag is not checked if maxLag is 0.
+func NewKafkaChecker(brokers []string, topic, groupID string, maxLag int64) Checker {
+	return kafkaChecker{
+		client:  &kafka.Client{Addr: kafka.TCP(brokers...)},
+		topic:   topic,
+		groupID: groupID,
+		maxLag:  maxLag,
+	}
+}
+
+func (c kafkaChecker) Name() string {
+	return "kafka"
+}
+
+func (c kafkaChecker) Check(ctx context.Context, details map[string]string) error {
+	metadata, err := c.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{c.topic}})
+	if err != nil {
+		return fmt.Errorf("c.client.Metadata: %w", err)
+	}
+	if len(metadata.Topics) == 0 {
+		return fmt.Errorf("topic %q not found", c.topic)
+	}
+	if metadata.Topics[0].Error != nil {
+		return fmt.Errorf("topic %q metadata: %w", c.topic, metadata.Topics[0].Error)
+	}
+
+	partitions := make([]int, 0, len(metadata.Topics[0].Partitions))
+	lastOffsetRequests := make([]kafka.OffsetRequest, 0, len(metadata.Topics[0].Partitions))
+	for _, p := range metadata.Topics[0].Partitions {
+		partitions = append(partitions, p.ID)
+		lastOffsetRequests = append(lastOffsetRequests, kafka.LastOffsetOf(p.ID))
+	}
+
+	committed, err := c.client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: c.groupID,
+		Topics:  map[string][]int{c.topic: partitions},
+	})
+	if err != nil {
+		return fmt.Errorf("c.client.OffsetFetch: %w", err)
+	}
+	if committed.Error != nil {
+		return fmt.Errorf("offset fetch: %w", committed.Error)
+	}
+
+	last, err := c.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{c.topic: lastOffsetRequests},
+	})
+	if err != nil {
+		return fmt.Errorf("c.client.ListOffsets: %w", err)
+	}
+
+	lag, err := ConsumerLag(committed.Topics[c.topic], last.Topics[c.topic])
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for partition, partitionLag := range lag {
+		details["lag."+strconv.Itoa(partition)] = strconv.FormatInt(partitionLag, 10)
+		total += partitionLag
+	}
+	details["lag"] = strconv.FormatInt(total, 10)
+
+	if c.maxLag > 0 && total > c.maxLag {
+		return fmt.Errorf("consumer lag %d exceeds %d", total, c.maxLag)
+	}
+
+	return nil
+}
+
+// ConsumerLag returns lag per partition. A partition without a committed offset lags by all of its messages.
+func ConsumerLag(committed []kafka.OffsetFetchPartition, last []kafka.PartitionOffsets) (map[int]int64, error) {
+	committedByPartition := make(map[int]int64, len(committed))
+	for _, p := range committed {
+		if p.Error != nil {
+			return nil, fmt.Errorf("partition %d committed offset: %w", p.Partition, p.Error)
+		}
+
+		committedByPartition[p.Partition] = p.CommittedOffset
+	}
+
+	lag := make(map[int]int64, len(last))
+	for _, p := range last {
+		if p.Error != nil {
+			return nil, fmt.Errorf("partition %d last offset: %w", p.Partition, p.Error)
+		}
+
+		offset, ok := committedByPartition[p.Partition]
+		if !ok || offset < 0 {
+			offset = p.FirstOffset
+		}
+
+		lag[p.Partition] = max(p.LastOffset-offset, 0)
+	}
+
+	return lag, nil
+}
+
+type informationalChecker struct {
+	Checker
+}
+
+// Informational reports the dependency without failing readiness when it's down. The service keeps serving
+// without it, and restarting replicas because of it wouldn't bring it back.
+func Informational(checker Checker) Checker {
+	return informationalChecker{Checker: checker}
+}
+
+type httpChecker struct {
+	name    string
+	client  gohttp.Client
+	baseURL string
+}
+
+// NewHTTPChecker treats any response below 500 as healthy: the peer is reachable and serving requests.
+func NewHTTPChecker(name string, client gohttp.Client, baseURL string) Checker {
+	return httpChecker{name: name, client: client, baseURL: baseURL}
+}
+
+func (c httpChecker) Name() string {
+	return c.name
+}
+
+func (c httpChecker) Check(ctx context.Context, details map[string]string) error {
+	rq, err := gohttp.NewRequest(goctx.Wrap(ctx), http.MethodGet, c.baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("NewRequest: %w", err)
+	}
+
+	rs, err := c.client.Do(rq)
+	if rs != nil && rs.Body != nil {
+		err = errors.Join(err, rs.Body.Close())
+	}
+	if err != nil {
+		return fmt.Errorf("c.client.Do: %w", err)
+	}
+
+	details["statusCode"] = strconv.Itoa(rs.StatusCode)
+	if rs.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("got status code %d", rs.StatusCode)
+	}
+
+	return nil
+}
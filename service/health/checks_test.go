@@ -0,0 +1,31 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestConsumerLag(t *testing.T) {
+	committed := []kafka.OffsetFetchPartition{
+		{Partition: 0, CommittedOffset: 90},
+		{Partition: 1, CommittedOffset: -1},
+	}
+	last := []kafka.PartitionOffsets{
+		{Partition: 0, FirstOffset: 0, LastOffset: 100},
+		{Partition: 1, FirstOffset: 20, LastOffset: 50},
+		{Partition: 2, FirstOffset: 5, LastOffset: 5},
+	}
+
+	lag, err := ConsumerLag(committed, last)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[int]int64{0: 10, 1: 30, 2: 0}
+	for partition, want := range expected {
+		if got := lag[partition]; got != want {
+			t.Fatalf("partition %d: expected lag %d, got %d", partition, want, got)
+		}
+	}
+}
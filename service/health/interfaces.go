@@ -0,0 +1,9 @@
+package health
+
+import "context"
+
+// Checker checks one dependency. It may put extra information into details, which is returned even if the check fails.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context, details map[string]string) error
+}
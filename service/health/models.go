@@ -0,0 +1,22 @@
+package health
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+type Report struct {
+	Status       Status       `json:"Status"`
+	Dependencies []Dependency `json:"Dependencies"`
+}
+
+type Dependency struct {
+	Name          string            `json:"Name"`
+	Status        Status            `json:"Status"`
+	Informational bool              `json:"Informational"`
+	LatencyMS     float64           `json:"LatencyMS"`
+	Error         string            `json:"Error"`
+	Details       map[string]string `json:"Details"`
+}
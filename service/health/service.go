@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultTimeout is used when the check timeout isn't configured, a check without a deadline could hang the probe.
+const defaultTimeout = 2 * time.Second
+
+type Service struct {
+	checkers []Checker
+	timeout  time.Duration
+}
+
+func NewService(timeout time.Duration, checkers ...Checker) *Service {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &Service{
+		checkers: checkers,
+		timeout:  timeout,
+	}
+}
+
+// Live reports that the process is running and able to serve requests, it doesn't touch dependencies.
+func (s *Service) Live() Report {
+	return Report{
+		Status:       StatusUp,
+		Dependencies: []Dependency{},
+	}
+}
+
+// Ready runs all checks concurrently, each one with its own timeout. Informational dependencies are reported,
+// but don't fail readiness.
+func (s *Service) Ready(ctx context.Context) Report {
+	dependencies := make([]Dependency, len(s.checkers))
+
+	var wg sync.WaitGroup
+	for i, checker := range s.checkers {
+		wg.Go(func() {
+			dependencies[i] = s.check(ctx, checker)
+		})
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:       StatusUp,
+		Dependencies: dependencies,
+	}
+	for _, d := range dependencies {
+		if d.Status == StatusDown && !d.Informational {
+			report.Status = StatusDown
+			break
+		}
+	}
+
+	return report
+}
+
+func (s *Service) check(ctx context.Context, checker Checker) Dependency {
+	checkCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	details := make(map[string]string)
+
+	start := time.Now()
+	err := checker.Check(checkCtx, details)
+	latency := time.Since(start)
+
+	_, informational := checker.(informationalChecker)
+
+	dependency := Dependency{
+		Name:          checker.Name(),
+		Status:        StatusUp,
+		Informational: informational,
+		LatencyMS:     float64(latency.Microseconds()) / 1000,
+		Details:       details,
+	}
+	if err != nil {
+		dependency.Status = StatusDown
+		dependency.Error = err.Error()
+	}
+
+	return dependency
+}
@@ -0,0 +1,45 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeChecker struct {
+	name string
+	err  error
+}
+
+func (c fakeChecker) Name() string {
+	return c.name
+}
+
+func (c fakeChecker) Check(ctx context.Context, details map[string]string) error {
+	if _, ok := ctx.Deadline(); !ok {
+		return errors.New("no deadline")
+	}
+
+	return c.err
+}
+
+func TestReadyIgnoresInformationalDependencies(t *testing.T) {
+	s := NewService(0,
+		fakeChecker{name: "postgres"},
+		Informational(fakeChecker{name: "fileService", err: errors.New("unreachable")}),
+	)
+
+	report := s.Ready(context.Background())
+	if report.Status != StatusUp {
+		t.Fatalf("expected status up, got %v: %+v", report.Status, report.Dependencies)
+	}
+	if d := report.Dependencies[1]; d.Status != StatusDown || !d.Informational {
+		t.Fatalf("expected informational dependency to be down, got %+v", d)
+	}
+
+	s = NewService(time.Second, fakeChecker{name: "postgres", err: errors.New("refused")})
+	if report = s.Ready(context.Background()); report.Status != StatusDown {
+		t.Fatalf("expected status down, got %v", report.Status)
+	}
+}
@@ -0,0 +1,9 @@
+package kpi
+
+import "context"
+
+type Repository interface {
+	GetTasksToday(ctx context.Context) ([]TasksDaily, error)
+	GetBrigadesToday(ctx context.Context) ([]BrigadeDaily, error)
+	GetAnomaliesThisMonth(ctx context.Context) ([]AnomaliesCount, error)
+}
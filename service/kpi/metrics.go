@@ -0,0 +1,50 @@
+package kpi
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	tasksToday = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "analytics",
+		Subsystem: "kpi",
+		Name:      "tasks_today",
+		Help:      "Tasks finished today (UTC day, as in the BI views) by tenant and inspection type.",
+	}, []string{"tenant", "inspection_type"})
+
+	violationsDetectedToday = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "analytics",
+		Subsystem: "kpi",
+		Name:      "violations_detected_today",
+		Help:      "Tasks finished today with a detected violation by tenant.",
+	}, []string{"tenant"})
+
+	taskDurationToday = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "analytics",
+		Subsystem: "kpi",
+		Name:      "task_duration_avg_minutes_today",
+		Help:      "Average duration of tasks finished today in minutes by tenant.",
+	}, []string{"tenant"})
+
+	brigadeTasksToday = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "analytics",
+		Subsystem: "kpi",
+		Name:      "brigade_tasks_today",
+		Help:      "Tasks finished today by tenant and brigade.",
+	}, []string{"tenant", "brigade_id"})
+
+	brigadeTaskDurationToday = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "analytics",
+		Subsystem: "kpi",
+		Name:      "brigade_task_duration_avg_minutes_today",
+		Help:      "Average duration of tasks finished today in minutes by tenant and brigade.",
+	}, []string{"tenant", "brigade_id"})
+
+	anomaliesThisMonth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "analytics",
+		Subsystem: "kpi",
+		Name:      "consumption_anomalies_this_month",
+		Help:      "Consumption anomalies flagged by the anomaly rules for the current month by tenant, dismissed ones excluded.",
+	}, []string{"tenant"})
+)
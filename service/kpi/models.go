@@ -0,0 +1,25 @@
+package kpi
+
+type TasksDaily struct {
+	Tenant                      string
+	TasksCount                  int
+	LimitationCount             int
+	ResumptionCount             int
+	VerificationCount           int
+	UnauthorizedConnectionCount int
+	ViolationsDetectedCount     int
+	AvgDurationMinutes          float64
+}
+
+type BrigadeDaily struct {
+	Tenant                  string
+	BrigadeID               int
+	TasksCount              int
+	AvgDurationMinutes      float64
+	ViolationsDetectedCount int
+}
+
+type AnomaliesCount struct {
+	Tenant         string
+	AnomaliesCount int
+}
@@ -0,0 +1,85 @@
+package kpi
+
+import (
+	"analytics-service/config"
+	"fmt"
+	"strconv"
+
+	"github.com/sunshineOfficial/golib/goctx"
+)
+
+// Service publishes business KPIs as Prometheus gauges. Gauges keep the last collected values between refreshes.
+type Service struct {
+	repository Repository
+	tenants    []string
+}
+
+// NewService publishes zeros for the configured tenants without data, so alerts on them don't go silent.
+func NewService(repository Repository, settings config.Tenancy) *Service {
+	tenants := []string{settings.DefaultTenant}
+	for _, t := range settings.Tenants {
+		tenants = append(tenants, t.ID)
+	}
+
+	return &Service{
+		repository: repository,
+		tenants:    tenants,
+	}
+}
+
+// Collect runs the queries one by one, so a refresh never runs more than one query at a time. "Today" is the day of
+// the BI views, so the gauges match the dashboards, "this month" is taken in Moscow time, as in anomaly evaluation.
+func (s *Service) Collect(ctx goctx.Context) error {
+	tasks, err := s.repository.GetTasksToday(ctx)
+	if err != nil {
+		return fmt.Errorf("get tasks today: %w", err)
+	}
+
+	brigades, err := s.repository.GetBrigadesToday(ctx)
+	if err != nil {
+		return fmt.Errorf("get brigades today: %w", err)
+	}
+
+	anomalies, err := s.repository.GetAnomaliesThisMonth(ctx)
+	if err != nil {
+		return fmt.Errorf("get anomalies this month: %w", err)
+	}
+
+	// Brigades without tasks today must disappear instead of keeping yesterday's values, tenants get zeros.
+	tasksToday.Reset()
+	violationsDetectedToday.Reset()
+	taskDurationToday.Reset()
+	brigadeTasksToday.Reset()
+	brigadeTaskDurationToday.Reset()
+	anomaliesThisMonth.Reset()
+
+	for _, tenant := range s.tenants {
+		setTasksToday(TasksDaily{Tenant: tenant})
+		anomaliesThisMonth.WithLabelValues(tenant).Set(0)
+	}
+
+	for _, t := range tasks {
+		setTasksToday(t)
+	}
+
+	for _, brigade := range brigades {
+		brigadeID := strconv.Itoa(brigade.BrigadeID)
+		brigadeTasksToday.WithLabelValues(brigade.Tenant, brigadeID).Set(float64(brigade.TasksCount))
+		brigadeTaskDurationToday.WithLabelValues(brigade.Tenant, brigadeID).Set(brigade.AvgDurationMinutes)
+	}
+
+	for _, a := range anomalies {
+		anomaliesThisMonth.WithLabelValues(a.Tenant).Set(float64(a.AnomaliesCount))
+	}
+
+	return nil
+}
+
+func setTasksToday(t TasksDaily) {
+	tasksToday.WithLabelValues(t.Tenant, "limitation").Set(float64(t.LimitationCount))
+	tasksToday.WithLabelValues(t.Tenant, "resumption").Set(float64(t.ResumptionCount))
+	tasksToday.WithLabelValues(t.Tenant, "verification").Set(float64(t.VerificationCount))
+	tasksToday.WithLabelValues(t.Tenant, "unauthorized_connection").Set(float64(t.UnauthorizedConnectionCount))
+	violationsDetectedToday.WithLabelValues(t.Tenant).Set(float64(t.ViolationsDetectedCount))
+	taskDurationToday.WithLabelValues(t.Tenant).Set(t.AvgDurationMinutes)
+}
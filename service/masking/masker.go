@@ -0,0 +1,266 @@
+package masking
+
+import (
+	"analytics-service/config"
+	"analytics-service/service/auth"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Policy string
+
+const (
+	PolicyFull    Policy = "full"
+	PolicyPartial Policy = "partial"
+	PolicyHashed  Policy = "hashed"
+	PolicyDropped Policy = "dropped"
+)
+
+// strictness orders the policies from the one revealing the most.
+var strictness = []Policy{PolicyFull, PolicyPartial, PolicyHashed, PolicyDropped}
+
+// ScopeAPI is the masking scope of JSON analytics endpoints. Reports use their type name as a scope.
+const ScopeAPI = "api"
+
+const (
+	wildcard      = "*"
+	hashLength    = 16
+	visibleDigits = 4
+)
+
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case PolicyFull, PolicyPartial, PolicyHashed, PolicyDropped:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown masking policy: %q", s)
+	}
+}
+
+// AtLeastAsStrict returns p and the policies masking more than it does.
+func AtLeastAsStrict(p Policy) []Policy {
+	for i, policy := range strictness {
+		if policy == p {
+			return strictness[i:]
+		}
+	}
+
+	return nil
+}
+
+type policyKey struct {
+	scope string
+	role  auth.Role
+}
+
+type Masker struct {
+	defaultPolicy Policy
+	hashSalt      []byte
+	policies      map[policyKey]Policy
+}
+
+func NewMasker(settings config.Masking) (*Masker, error) {
+	defaultPolicy, err := ParsePolicy(settings.DefaultPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("parse default policy: %w", err)
+	}
+
+	policies := make(map[policyKey]Policy, len(settings.Policies))
+	for _, p := range settings.Policies {
+		policy, parseErr := ParsePolicy(p.Policy)
+		if parseErr != nil {
+			return nil, fmt.Errorf("parse policy for scope %q and role %q: %w", p.Scope, p.Role, parseErr)
+		}
+
+		policies[policyKey{scope: p.Scope, role: auth.Role(p.Role)}] = policy
+	}
+
+	// Phone numbers and INNs are short enough to brute force an unsalted hash.
+	if settings.HashSalt == "" && (defaultPolicy == PolicyHashed || slices.Contains(slices.Collect(maps.Values(policies)), PolicyHashed)) {
+		return nil, fmt.Errorf("hash salt is required by the hashed policy")
+	}
+
+	return &Masker{
+		defaultPolicy: defaultPolicy,
+		hashSalt:      []byte(settings.HashSalt),
+		policies:      policies,
+	}, nil
+}
+
+// Policy picks the most specific configured policy: exact scope and role first, then wildcards, then the default.
+func (m *Masker) Policy(scope string, role auth.Role) Policy {
+	for _, key := range []policyKey{
+		{scope: scope, role: role},
+		{scope: scope, role: wildcard},
+		{scope: wildcard, role: role},
+		{scope: wildcard, role: wildcard},
+	} {
+		if policy, ok := m.policies[key]; ok {
+			return policy
+		}
+	}
+
+	return m.defaultPolicy
+}
+
+// Phone keeps the last four digits in partial mode: +7 *** ***-12-34.
+func (m *Masker) Phone(policy Policy, phone string) string {
+	switch policy {
+	case PolicyFull:
+		return phone
+	case PolicyPartial:
+		digits := onlyDigits(phone)
+		if len(digits) < visibleDigits {
+			return "***"
+		}
+
+		tail := digits[len(digits)-visibleDigits:]
+		return fmt.Sprintf("+7 *** ***-%s-%s", tail[:2], tail[2:])
+	default:
+		return m.other(policy, phone)
+	}
+}
+
+// Email keeps the first letter of the local part and the domain in partial mode: i***@example.com.
+func (m *Masker) Email(policy Policy, email string) string {
+	if policy != PolicyPartial {
+		return m.other(policy, email)
+	}
+
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || len(local) == 0 {
+		return "***"
+	}
+
+	first, _ := utf8.DecodeRuneInString(local)
+	return string(first) + "***@" + domain
+}
+
+// INN keeps the last four digits in partial mode.
+func (m *Masker) INN(policy Policy, inn string) string {
+	if policy != PolicyPartial {
+		return m.other(policy, inn)
+	}
+
+	return lastDigits(inn)
+}
+
+// AccountNumber keeps the last four characters in partial mode, like INN.
+func (m *Masker) AccountNumber(policy Policy, accountNumber string) string {
+	if policy != PolicyPartial {
+		return m.other(policy, accountNumber)
+	}
+
+	return lastDigits(accountNumber)
+}
+
+// Address keeps the comma separated parts without digits in partial mode, so the house, the flat and the postcode
+// are dropped: г. Екатеринбург, ул. Ленина.
+func (m *Masker) Address(policy Policy, address string) string {
+	if policy != PolicyPartial {
+		return m.other(policy, address)
+	}
+
+	parts := make([]string, 0)
+	for part := range strings.SplitSeq(address, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" && !strings.ContainsFunc(part, unicode.IsDigit) {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Surname keeps the first letter in partial mode: И***.
+func (m *Masker) Surname(policy Policy, surname string) string {
+	if policy != PolicyPartial {
+		return m.other(policy, surname)
+	}
+
+	if len(surname) == 0 {
+		return ""
+	}
+
+	first, _ := utf8.DecodeRuneInString(surname)
+	return string(first) + "***"
+}
+
+// Initial is used for first names and patronymics, it keeps only the initial in partial mode: И.
+func (m *Masker) Initial(policy Policy, name string) string {
+	if policy != PolicyPartial {
+		return m.other(policy, name)
+	}
+
+	if len(name) == 0 {
+		return ""
+	}
+
+	first, _ := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + "."
+}
+
+// BirthDate keeps only the year in partial mode. A date can't be hashed, so hashed mode drops it.
+func (m *Masker) BirthDate(policy Policy, date time.Time) time.Time {
+	switch policy {
+	case PolicyFull:
+		return date
+	case PolicyPartial:
+		if date.IsZero() {
+			return date
+		}
+
+		return time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, date.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+func (m *Masker) other(policy Policy, value string) string {
+	switch policy {
+	case PolicyFull:
+		return value
+	case PolicyHashed:
+		return m.hash(value)
+	default:
+		return ""
+	}
+}
+
+func (m *Masker) hash(value string) string {
+	if len(value) == 0 {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, m.hashSalt)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))[:hashLength]
+}
+
+func lastDigits(value string) string {
+	if len(value) <= visibleDigits {
+		return strings.Repeat("*", len(value))
+	}
+
+	return strings.Repeat("*", len(value)-visibleDigits) + value[len(value)-visibleDigits:]
+}
+
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
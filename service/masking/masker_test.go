@@ -0,0 +1,120 @@
+package masking
+
+import (
+	"analytics-service/config"
+	"analytics-service/service/auth"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMaskerPolicyPrefersMostSpecificRule(t *testing.T) {
+	m, err := NewMasker(config.Masking{
+		DefaultPolicy: "dropped",
+		HashSalt:      "salt",
+		Policies: []config.MaskingPolicy{
+			{Scope: "*", Role: "contractor", Policy: "hashed"},
+			{Scope: "basic", Role: "contractor", Policy: "partial"},
+			{Scope: "basic", Role: "*", Policy: "full"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if p := m.Policy("basic", auth.RoleContractor); p != PolicyPartial {
+		t.Fatalf("expected partial policy, got %q", p)
+	}
+	if p := m.Policy("basic", auth.RoleAnalyst); p != PolicyFull {
+		t.Fatalf("expected full policy, got %q", p)
+	}
+	if p := m.Policy(ScopeAPI, auth.RoleContractor); p != PolicyHashed {
+		t.Fatalf("expected hashed policy, got %q", p)
+	}
+	if p := m.Policy(ScopeAPI, auth.RoleUnknown); p != PolicyDropped {
+		t.Fatalf("expected dropped policy, got %q", p)
+	}
+}
+
+func TestMaskerPartialValues(t *testing.T) {
+	m, err := NewMasker(config.Masking{DefaultPolicy: "partial"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if v := m.Phone(PolicyPartial, "+7 (912) 345-12-34"); v != "+7 *** ***-12-34" {
+		t.Fatalf("unexpected phone: %q", v)
+	}
+	if v := m.Email(PolicyPartial, "ivanov@example.com"); v != "i***@example.com" {
+		t.Fatalf("unexpected email: %q", v)
+	}
+	if v := m.INN(PolicyPartial, "500100732259"); v != "********2259" {
+		t.Fatalf("unexpected inn: %q", v)
+	}
+	if v := m.AccountNumber(PolicyPartial, "6600123456"); v != "******3456" {
+		t.Fatalf("unexpected account number: %q", v)
+	}
+	if v := m.Address(PolicyPartial, "620000, г. Екатеринбург, ул. Ленина, д. 5, кв. 12"); v != "г. Екатеринбург, ул. Ленина" {
+		t.Fatalf("unexpected address: %q", v)
+	}
+	if v := m.Surname(PolicyPartial, "Иванов"); v != "И***" {
+		t.Fatalf("unexpected surname: %q", v)
+	}
+	if v := m.Initial(PolicyPartial, "петр"); v != "П." {
+		t.Fatalf("unexpected initial: %q", v)
+	}
+
+	birthDate := m.BirthDate(PolicyPartial, time.Date(1990, time.May, 17, 0, 0, 0, 0, time.UTC))
+	if !birthDate.Equal(time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected birth date: %s", birthDate)
+	}
+}
+
+func TestMaskerHashedAndDroppedValues(t *testing.T) {
+	m, err := NewMasker(config.Masking{DefaultPolicy: "full", HashSalt: "salt"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := m.Email(PolicyHashed, "ivanov@example.com")
+	second := m.Email(PolicyHashed, "ivanov@example.com")
+	if len(first) != hashLength || first != second {
+		t.Fatalf("expected stable hash of length %d, got %q and %q", hashLength, first, second)
+	}
+	if v := m.Phone(PolicyDropped, "+79123451234"); v != "" {
+		t.Fatalf("expected dropped phone, got %q", v)
+	}
+	if v := m.BirthDate(PolicyHashed, time.Now()); !v.IsZero() {
+		t.Fatalf("expected dropped birth date, got %s", v)
+	}
+}
+
+func TestNewMaskerRejectsUnknownPolicy(t *testing.T) {
+	_, err := NewMasker(config.Masking{DefaultPolicy: "secret"})
+	if err == nil {
+		t.Fatal("expected error for unknown policy")
+	}
+}
+
+func TestNewMaskerRequiresSaltForHashedPolicy(t *testing.T) {
+	_, err := NewMasker(config.Masking{
+		DefaultPolicy: "partial",
+		Policies:      []config.MaskingPolicy{{Scope: "*", Role: "analyst", Policy: "hashed"}},
+	})
+	if err == nil {
+		t.Fatal("expected error for hashed policy without salt")
+	}
+
+	if _, err = NewMasker(config.Masking{DefaultPolicy: "hashed", HashSalt: "salt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAtLeastAsStrict(t *testing.T) {
+	if got := AtLeastAsStrict(PolicyPartial); !slices.Equal(got, []Policy{PolicyPartial, PolicyHashed, PolicyDropped}) {
+		t.Fatalf("unexpected policies: %v", got)
+	}
+	if got := AtLeastAsStrict(PolicyFull); len(got) != 4 {
+		t.Fatalf("expected every policy for full, got %v", got)
+	}
+}
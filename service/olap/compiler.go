@@ -0,0 +1,164 @@
+package olap
+
+import (
+	"analytics-service/service/auth"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	taskDuration = "dateDiff('minute', started_at, finished_at)"
+
+	// districtName falls back to the first part of the address for tasks stored before addresses were parsed.
+	districtName = "if(empty(object_district) and empty(object_street), replaceRegexpOne(object_address, ',.*$', ''), object_district)"
+)
+
+// dimensions and metrics are the only expressions a query may select, every value is cast to the type Row expects.
+var (
+	dimensions = map[Dimension]string{
+		DimensionDay:              "toString(toDate(finished_at))",
+		DimensionWeek:             "toString(toMonday(finished_at))",
+		DimensionMonth:            "toString(toStartOfMonth(finished_at))",
+		DimensionBrigade:          "toString(brigade_id)",
+		DimensionInspector:        "toString(inspector.1)",
+		DimensionInspectionType:   "toString(inspection_type)",
+		DimensionResolution:       "toString(inspection_resolution)",
+		DimensionSubscriberStatus: "toString(subscriber_status)",
+		DimensionDistrict:         districtName,
+		DimensionAutomaton:        "toString(object_have_automaton)",
+	}
+
+	metrics = map[Metric]string{
+		MetricCount:          "toFloat64(count())",
+		MetricViolations:     "toFloat64(countIf(inspection_is_violation_detected))",
+		MetricAvgDuration:    "ifNotFinite(round(avg(" + taskDuration + "), 2), 0)",
+		MetricMedianDuration: "ifNotFinite(round(quantile(0.5)(" + taskDuration + "), 2), 0)",
+		MetricP90Duration:    "ifNotFinite(round(quantile(0.9)(" + taskDuration + "), 2), 0)",
+		MetricConsumptionSum: "round(sum(arraySum(arrayMap(d -> toFloat64(d.4), inspected_devices))), 2)",
+	}
+
+	inspectionTypes    = []string{"unknown", "limitation", "resumption", "verification", "unauthorized_connection"}
+	resolutions        = []string{"unknown", "limited", "stopped", "resumed"}
+	subscriberStatuses = []string{"unknown", "active", "violator", "archived"}
+)
+
+// Compile builds parameterised SQL over finished_tasks. The query reads at most limit rows and is stopped
+// by ClickHouse after timeout. The inspector dimension and filter expand every task to its brigade inspectors,
+// so the metrics of a task count for each of them. Only tasks of the scope tenants are read.
+func Compile(q Query, scope auth.TenantScope, limit int, timeout time.Duration) (Statement, error) {
+	if err := validate(q); err != nil {
+		return Statement{}, err
+	}
+
+	var (
+		selects = make([]string, 0, len(q.Dimensions)+len(q.Metrics))
+		groups  = make([]string, 0, len(q.Dimensions))
+		args    = []any{q.From, q.To}
+		where   = []string{"$1 <= finished_at", "finished_at < $2"}
+	)
+
+	for i, d := range q.Dimensions {
+		alias := fmt.Sprintf("d%d", i)
+		selects = append(selects, dimensions[d]+" as "+alias)
+		groups = append(groups, alias)
+	}
+
+	for i, m := range q.Metrics {
+		selects = append(selects, fmt.Sprintf("%s as m%d", metrics[m], i))
+	}
+
+	addFilter := func(condition string, value any) {
+		args = append(args, value)
+		where = append(where, fmt.Sprintf(condition, len(args)))
+	}
+
+	if !scope.All {
+		addFilter("has($%d, tenant)", scope.Tenants)
+	}
+
+	f := q.Filter
+	if f.BrigadeID != 0 {
+		addFilter("brigade_id = $%d", f.BrigadeID)
+	}
+	if f.InspectorID != 0 {
+		addFilter("inspector.1 = $%d", f.InspectorID)
+	}
+	if f.InspectionType != "" {
+		addFilter("inspection_type = $%d", f.InspectionType)
+	}
+	if f.Resolution != "" {
+		addFilter("inspection_resolution = $%d", f.Resolution)
+	}
+	if f.SubscriberStatus != "" {
+		addFilter("subscriber_status = $%d", f.SubscriberStatus)
+	}
+	if f.District != "" {
+		addFilter(dimensions[DimensionDistrict]+" = $%d", f.District)
+	}
+	if f.HaveAutomaton != nil {
+		addFilter("object_have_automaton = $%d", *f.HaveAutomaton)
+	}
+
+	var sql strings.Builder
+	sql.WriteString("select " + strings.Join(selects, ",\n       ") + "\nfrom finished_tasks\n")
+	if slices.Contains(q.Dimensions, DimensionInspector) || f.InspectorID != 0 {
+		sql.WriteString("array join brigade_inspectors as inspector\n")
+	}
+	sql.WriteString("where " + strings.Join(where, "\n  and ") + "\n")
+	if len(groups) > 0 {
+		sql.WriteString("group by " + strings.Join(groups, ", ") + "\n")
+		sql.WriteString("order by " + strings.Join(groups, ", ") + "\n")
+	}
+	fmt.Fprintf(&sql, "limit %d\nsettings max_execution_time = %d;", limit, max(int(timeout.Seconds()), 1))
+
+	return Statement{
+		SQL:        sql.String(),
+		Args:       args,
+		Dimensions: len(q.Dimensions),
+		Metrics:    len(q.Metrics),
+	}, nil
+}
+
+func validate(q Query) error {
+	if !q.From.Before(q.To) {
+		return fmt.Errorf("from must be before to, got: %s - %s", q.From, q.To)
+	}
+
+	if len(q.Metrics) == 0 {
+		return fmt.Errorf("at least one metric is required")
+	}
+
+	for i, d := range q.Dimensions {
+		if _, ok := dimensions[d]; !ok {
+			return fmt.Errorf("unknown dimension: %q", d)
+		}
+		if slices.Contains(q.Dimensions[:i], d) {
+			return fmt.Errorf("duplicate dimension: %q", d)
+		}
+	}
+
+	for _, m := range q.Metrics {
+		if _, ok := metrics[m]; !ok {
+			return fmt.Errorf("unknown metric: %q", m)
+		}
+	}
+
+	enums := []struct {
+		name    string
+		value   string
+		allowed []string
+	}{
+		{name: "inspection type", value: q.Filter.InspectionType, allowed: inspectionTypes},
+		{name: "resolution", value: q.Filter.Resolution, allowed: resolutions},
+		{name: "subscriber status", value: q.Filter.SubscriberStatus, allowed: subscriberStatuses},
+	}
+	for _, e := range enums {
+		if e.value != "" && !slices.Contains(e.allowed, e.value) {
+			return fmt.Errorf("unknown %s: %q", e.name, e.value)
+		}
+	}
+
+	return nil
+}
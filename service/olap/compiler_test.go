@@ -0,0 +1,79 @@
+package olap
+
+import (
+	"analytics-service/service/auth"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompile(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	haveAutomaton := true
+
+	stmt, err := Compile(Query{
+		From:       from,
+		To:         from.AddDate(0, 1, 0),
+		Dimensions: []Dimension{DimensionWeek, DimensionInspector},
+		Metrics:    []Metric{MetricCount, MetricP90Duration},
+		Filter:     Filter{InspectionType: "limitation", District: "Кировский р-н", HaveAutomaton: &haveAutomaton},
+	}, auth.TenantScope{Tenants: []string{"ekb"}}, 101, 30*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, part := range []string{
+		"toString(toMonday(finished_at)) as d0",
+		"toString(inspector.1) as d1",
+		"toFloat64(count()) as m0",
+		"array join brigade_inspectors as inspector",
+		"has($3, tenant)",
+		"inspection_type = $4",
+		"if(empty(object_district) and empty(object_street), replaceRegexpOne(object_address, ',.*$', ''), object_district) = $5",
+		"object_have_automaton = $6",
+		"group by d0, d1",
+		"limit 101",
+		"max_execution_time = 30",
+	} {
+		if !strings.Contains(stmt.SQL, part) {
+			t.Fatalf("expected %q in:\n%s", part, stmt.SQL)
+		}
+	}
+
+	if len(stmt.Args) != 6 || stmt.Args[4] != "Кировский р-н" || stmt.Dimensions != 2 || stmt.Metrics != 2 {
+		t.Fatalf("unexpected statement: %+v", stmt)
+	}
+}
+
+func TestCompileWithoutDimensions(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	stmt, err := Compile(Query{From: from, To: from.AddDate(0, 0, 1), Metrics: []Metric{MetricConsumptionSum}}, auth.TenantScope{All: true}, 10, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(stmt.SQL, "group by") || strings.Contains(stmt.SQL, "array join") || strings.Contains(stmt.SQL, "tenant") {
+		t.Fatalf("unexpected grouping in:\n%s", stmt.SQL)
+	}
+}
+
+func TestCompileRejectsUnknownNames(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	queries := []Query{
+		{From: from, To: to},
+		{From: from, To: to, Metrics: []Metric{"sum(subscriber_inn)"}},
+		{From: from, To: to, Metrics: []Metric{MetricCount}, Dimensions: []Dimension{"subscriber_inn"}},
+		{From: from, To: to, Metrics: []Metric{MetricCount}, Dimensions: []Dimension{DimensionDay, DimensionDay}},
+		{From: from, To: to, Metrics: []Metric{MetricCount}, Filter: Filter{Resolution: "' or 1 = 1"}},
+		{From: to, To: from, Metrics: []Metric{MetricCount}},
+	}
+
+	for _, q := range queries {
+		if _, err := Compile(q, auth.TenantScope{All: true}, 10, time.Second); err == nil {
+			t.Fatalf("expected an error for %+v", q)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package olap
+
+import "context"
+
+type Repository interface {
+	Query(ctx context.Context, stmt Statement) ([]Row, error)
+}
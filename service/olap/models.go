@@ -0,0 +1,75 @@
+package olap
+
+import "time"
+
+type Dimension string
+
+const (
+	DimensionDay              Dimension = "day"
+	DimensionWeek             Dimension = "week"
+	DimensionMonth            Dimension = "month"
+	DimensionBrigade          Dimension = "brigade"
+	DimensionInspector        Dimension = "inspector"
+	DimensionInspectionType   Dimension = "inspection_type"
+	DimensionResolution       Dimension = "resolution"
+	DimensionSubscriberStatus Dimension = "subscriber_status"
+	DimensionDistrict         Dimension = "district"
+	DimensionAutomaton        Dimension = "automaton"
+)
+
+type Metric string
+
+const (
+	MetricCount          Metric = "count"
+	MetricViolations     Metric = "violations"
+	MetricAvgDuration    Metric = "avg_duration"
+	MetricMedianDuration Metric = "median_duration"
+	MetricP90Duration    Metric = "p90_duration"
+	MetricConsumptionSum Metric = "consumption_sum"
+)
+
+// Filter narrows the tasks of the query, zero values mean no filter. Enum filters take the names stored
+// in finished_tasks, for example limitation, limited or violator.
+type Filter struct {
+	BrigadeID        int
+	InspectorID      int
+	InspectionType   string
+	Resolution       string
+	SubscriberStatus string
+	District         string
+	HaveAutomaton    *bool
+}
+
+// Query aggregates the metrics of the tasks finished from From to To grouped by the dimensions.
+// Limit 0 means the configured maximum.
+type Query struct {
+	From       time.Time
+	To         time.Time
+	Dimensions []Dimension
+	Metrics    []Metric
+	Filter     Filter
+	Limit      int
+}
+
+// Statement is a compiled query, Args are bound to its positional parameters.
+type Statement struct {
+	SQL        string
+	Args       []any
+	Dimensions int
+	Metrics    int
+}
+
+// Row holds the values of the query dimensions and metrics in the order they were requested.
+type Row struct {
+	Dimensions []string  `json:"Dimensions"`
+	Metrics    []float64 `json:"Metrics"`
+}
+
+// Result is truncated if the query matched more than Limit rows.
+type Result struct {
+	Dimensions []Dimension `json:"Dimensions"`
+	Metrics    []Metric    `json:"Metrics"`
+	Rows       []Row       `json:"Rows"`
+	Limit      int         `json:"Limit"`
+	Truncated  bool        `json:"Truncated"`
+}
@@ -0,0 +1,80 @@
+package olap
+
+import (
+	"analytics-service/config"
+	"analytics-service/service/auth"
+	"fmt"
+	"time"
+
+	"github.com/sunshineOfficial/golib/goctx"
+	"github.com/sunshineOfficial/golib/gotime"
+)
+
+const (
+	defaultMaxRows = 1000
+	defaultTimeout = 30 * time.Second
+)
+
+// Service answers ad hoc aggregate questions over finished tasks without new BI views.
+type Service struct {
+	repository Repository
+	settings   config.OLAP
+}
+
+// NewService defaults MaxRows and Timeout that aren't set, otherwise every query would be rejected or cancelled.
+func NewService(repository Repository, settings config.OLAP) *Service {
+	if settings.MaxRows <= 0 {
+		settings.MaxRows = defaultMaxRows
+	}
+	if settings.Timeout <= 0 {
+		settings.Timeout = gotime.Duration(defaultTimeout)
+	}
+
+	return &Service{
+		repository: repository,
+		settings:   settings,
+	}
+}
+
+func (s *Service) Query(ctx goctx.Context, q Query) (Result, error) {
+	limit := q.Limit
+	if limit == 0 {
+		limit = s.settings.MaxRows
+	}
+	if limit < 0 || limit > s.settings.MaxRows {
+		return Result{}, fmt.Errorf("limit must be from 1 to %d, got: %d", s.settings.MaxRows, limit)
+	}
+
+	if maxDays := s.settings.MaxPeriodDays; maxDays > 0 && q.To.Sub(q.From) > time.Duration(maxDays)*24*time.Hour {
+		return Result{}, fmt.Errorf("period must not be longer than %d days", maxDays)
+	}
+
+	timeout := time.Duration(s.settings.Timeout)
+
+	// One more row than the limit tells whether the result is truncated.
+	stmt, err := Compile(q, auth.FromContext(ctx).TenantScope(), limit+1, timeout)
+	if err != nil {
+		return Result{}, err
+	}
+
+	queryCtx, cancel := ctx.WithTimeout(timeout)
+	defer cancel()
+
+	rows, err := s.repository.Query(queryCtx, stmt)
+	if err != nil {
+		return Result{}, fmt.Errorf("query: %w", err)
+	}
+
+	result := Result{
+		Dimensions: q.Dimensions,
+		Metrics:    q.Metrics,
+		Rows:       rows,
+		Limit:      limit,
+	}
+	if len(rows) > limit {
+		result.Rows = rows[:limit]
+		result.Truncated = true
+	}
+
+	return result, nil
+}
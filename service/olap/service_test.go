@@ -0,0 +1,21 @@
+package olap
+
+import (
+	"analytics-service/config"
+	"testing"
+	"time"
+
+	"github.com/sunshineOfficial/golib/gotime"
+)
+
+func TestNewServiceDefaultsLimits(t *testing.T) {
+	s := NewService(nil, config.OLAP{})
+	if s.settings.MaxRows != defaultMaxRows || time.Duration(s.settings.Timeout) != defaultTimeout {
+		t.Fatalf("expected default limits, got %+v", s.settings)
+	}
+
+	s = NewService(nil, config.OLAP{MaxRows: 10, Timeout: gotime.Duration(time.Second)})
+	if s.settings.MaxRows != 10 || time.Duration(s.settings.Timeout) != time.Second {
+		t.Fatalf("expected configured limits to be kept, got %+v", s.settings)
+	}
+}
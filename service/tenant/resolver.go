@@ -0,0 +1,61 @@
+package tenant
+
+import (
+	"analytics-service/config"
+	"analytics-service/service/address"
+	"strings"
+)
+
+// Resolver assigns finished tasks to tenants. It's safe for concurrent use.
+type Resolver struct {
+	defaultTenant string
+	byBrigade     map[int]string
+	tenants       []config.Tenant
+}
+
+func NewResolver(settings config.Tenancy) *Resolver {
+	r := &Resolver{
+		defaultTenant: settings.DefaultTenant,
+		byBrigade:     make(map[int]string),
+		tenants:       settings.Tenants,
+	}
+
+	for _, t := range settings.Tenants {
+		for _, brigadeID := range t.BrigadeIDs {
+			if _, ok := r.byBrigade[brigadeID]; !ok {
+				r.byBrigade[brigadeID] = t.ID
+			}
+		}
+	}
+
+	return r
+}
+
+// Resolve prefers the brigade mapping, as a brigade works for one branch even outside its usual area.
+func (r *Resolver) Resolve(brigadeID int, addr address.Address) string {
+	if tenant, ok := r.byBrigade[brigadeID]; ok {
+		return tenant
+	}
+
+	for _, t := range r.tenants {
+		if matches(t.Regions, addr.Region) || matches(t.Cities, addr.City) || matches(t.Districts, addr.District) {
+			return t.ID
+		}
+	}
+
+	return r.defaultTenant
+}
+
+func matches(names []string, value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, name := range names {
+		if strings.EqualFold(name, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,36 @@
+package tenant
+
+import (
+	"analytics-service/config"
+	"analytics-service/service/address"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	resolver := NewResolver(config.Tenancy{
+		DefaultTenant: "main",
+		Tenants: []config.Tenant{
+			{ID: "city", BrigadeIDs: []int{7}, Cities: []string{"Екатеринбург"}},
+			{ID: "region", BrigadeIDs: []int{7, 8}, Regions: []string{"Свердловская область"}},
+		},
+	})
+
+	tests := []struct {
+		name      string
+		brigadeID int
+		addr      address.Address
+		want      string
+	}{
+		{name: "first tenant of brigade", brigadeID: 7, addr: address.Address{City: "Тюмень"}, want: "city"},
+		{name: "brigade", brigadeID: 8, addr: address.Address{City: "Екатеринбург"}, want: "region"},
+		{name: "city", brigadeID: 1, addr: address.Address{Region: "Свердловская область", City: "екатеринбург"}, want: "city"},
+		{name: "region", brigadeID: 1, addr: address.Address{Region: "Свердловская область", City: "Верхняя Пышма"}, want: "region"},
+		{name: "default", brigadeID: 1, addr: address.Address{}, want: "main"},
+	}
+
+	for _, tt := range tests {
+		if got := resolver.Resolve(tt.brigadeID, tt.addr); got != tt.want {
+			t.Errorf("%s: Resolve() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -147,7 +147,7 @@ class ClickHouseBiMigrationContractTests(unittest.TestCase):
     def test_finished_task_insert_names_inspected_devices_column(self) -> None:
         sql = ADD_FINISHED_TASK_SQL.read_text(encoding="utf-8").lower()
 
-        self.assertIn("insert into finished_tasks", sql)
+        self.assertIn("insert into {table}", sql)
         self.assertIn("inspection_energy_action_at", sql)
         self.assertIn("inspected_devices", sql)
         self.assertIn("brigade_id", sql)
@@ -0,0 +1,38 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+)
+
+// kafkaHeaders adapts Kafka message headers to propagation.TextMapCarrier.
+type kafkaHeaders []kafka.Header
+
+func (h kafkaHeaders) Get(key string) string {
+	for _, header := range h {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+
+	return ""
+}
+
+// Set is never used, context is only extracted from consumed messages.
+func (h kafkaHeaders) Set(string, string) {}
+
+func (h kafkaHeaders) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for _, header := range h {
+		keys = append(keys, header.Key)
+	}
+
+	return keys
+}
+
+// ExtractKafka returns ctx with the remote span context of the producer, if the message carries one.
+func ExtractKafka(ctx context.Context, message kafka.Message) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, kafkaHeaders(message.Headers))
+}